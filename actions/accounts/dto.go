@@ -0,0 +1,18 @@
+package accounts
+
+import "influence_game/internal/accounts"
+
+type CredentialsDTO struct {
+	Username string `json:"username" openapi:"required,minLength=1,error=username_is_required"`
+	Password string `json:"password" openapi:"required,minLength=1,error=password_is_required"`
+}
+
+func (dto *CredentialsDTO) Validate() error {
+	if dto.Username == "" {
+		return accounts.ErrUsernameRequired
+	}
+	if dto.Password == "" {
+		return accounts.ErrPasswordRequired
+	}
+	return nil
+}
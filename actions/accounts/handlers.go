@@ -0,0 +1,103 @@
+package accounts
+
+import (
+	"influence_game/actions/apierrors"
+	"influence_game/internal/accounts"
+	"influence_game/internal/game"
+	"strings"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+type AccountsController struct {
+	Store *accounts.Store
+}
+
+func NewAccountsController(store *accounts.Store) *AccountsController {
+	return &AccountsController{Store: store}
+}
+
+func (controller *AccountsController) Register(ctx buffalo.Context) error {
+	log.Info().Msg("Registering account.")
+	var dto CredentialsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind register request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate register request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	result, err := controller.Store.Register(dto.Username, dto.Password)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to register account.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Registered account successfully.")
+
+	return ctx.Render(200, renderer.JSON(result))
+}
+
+func (controller *AccountsController) Login(ctx buffalo.Context) error {
+	log.Info().Msg("Logging in.")
+	var dto CredentialsDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind login request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate login request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	result, err := controller.Store.Login(dto.Username, dto.Password)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to log in.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Logged in successfully.")
+
+	return ctx.Render(200, renderer.JSON(result))
+}
+
+func (controller *AccountsController) Me(ctx buffalo.Context) error {
+	token, err := getAccountToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	account, err := controller.Store.ResolveToken(token)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve account session.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(account.Info()))
+}
+
+func getAccountToken(ctx buffalo.Context) (string, error) {
+	authHeader := ctx.Request().Header.Get("Authorization")
+	const prefix = "Bearer "
+
+	if !strings.HasPrefix(authHeader, prefix) {
+		return "", game.ErrMissingAuthorization
+	}
+
+	token := strings.TrimPrefix(authHeader, prefix)
+	if token == "" {
+		return "", game.ErrMissingAuthorization
+	}
+
+	return token, nil
+}
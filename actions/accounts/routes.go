@@ -0,0 +1,29 @@
+package accounts
+
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/accounts"
+)
+
+func Register(routes *openapi.Router, controller *AccountsController) {
+	routes = routes.Tagged("accounts")
+
+	routes.POST("/accounts/register", controller.Register, openapi.Operation{
+		ID:       "register",
+		Summary:  "Create an account and sign in.",
+		Body:     CredentialsDTO{},
+		Response: accounts.AuthResult{},
+	})
+	routes.POST("/accounts/login", controller.Login, openapi.Operation{
+		ID:       "login",
+		Summary:  "Sign in to an account.",
+		Body:     CredentialsDTO{},
+		Response: accounts.AuthResult{},
+	})
+	routes.GET("/accounts/me", controller.Me, openapi.Operation{
+		ID:       "getAccount",
+		Summary:  "The signed-in account.",
+		Auth:     openapi.AuthAccount,
+		Response: accounts.AccountInfo{},
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Package apierrors answers failed requests the same way everywhere:
+
+	{"error": "<code>", "message": "<localized message>"}
+
+with the HTTP status from the game error catalogue. When the error carries
+more than its code, like the step a replay diverged at, it is sent along as
+"detail".
+*/
+package apierrors
+
+import (
+	"influence_game/internal/game"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+// Render answers the request with err.
+func Render(ctx buffalo.Context, err error) error {
+	status, body := Body(ctx, err)
+	return ctx.Render(status, renderer.JSON(body))
+}
+
+// Body is the status and body Render would send, for handlers that add fields.
+func Body(ctx buffalo.Context, err error) (int, map[string]any) {
+	known := game.AsError(err)
+	if known == game.ErrInternal {
+		log.Error().Err(err).Str("path", ctx.Request().URL.Path).Msg("Unexpected error.")
+	}
+
+	body := map[string]any{
+		"error":   known.Code,
+		"message": Message(ctx, known),
+	}
+	if known != game.ErrInternal && err.Error() != known.Code {
+		body["detail"] = err.Error()
+	}
+
+	return known.Status, body
+}
+
+/*
+Message translates the error for the request's language, as picked by the
+i18n middleware from the Accept-Language header. Untranslated codes fall back
+to the code itself.
+*/
+func Message(ctx buffalo.Context, err *game.Error) string {
+	id := "error." + err.Code
+
+	translate, ok := ctx.Value("t").(func(string, ...interface{}) string)
+	if !ok {
+		return err.Code
+	}
+	if message := translate(id); message != id {
+		return message
+	}
+	return err.Code
+}
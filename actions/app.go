@@ -1,9 +1,28 @@
 package actions
 
 import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"influence_game/actions/accounts"
+	"influence_game/actions/archive"
+	"influence_game/actions/matchmaking"
+	"influence_game/actions/openapi"
+	"influence_game/actions/ratings"
+	"influence_game/actions/replays"
 	"influence_game/actions/rooms"
+	"influence_game/actions/tournaments"
+	internalaccounts "influence_game/internal/accounts"
+	internalarchive "influence_game/internal/archive"
+	"influence_game/internal/bots"
 	"influence_game/internal/game"
+	internalmatchmaking "influence_game/internal/matchmaking"
+	internalratings "influence_game/internal/ratings"
+	internalsessions "influence_game/internal/sessions"
+	internaltournaments "influence_game/internal/tournaments"
+	"influence_game/internal/versions"
 	"influence_game/locales"
+	"io/fs"
 	"sync"
 
 	"github.com/gobuffalo/buffalo"
@@ -12,19 +31,22 @@ import (
 	"github.com/gobuffalo/middleware/forcessl"
 	"github.com/gobuffalo/middleware/i18n"
 	"github.com/gobuffalo/middleware/paramlogger"
+	"github.com/gobuffalo/pop/v6"
 	"github.com/gobuffalo/x/sessions"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/cors"
+	"github.com/rs/zerolog/log"
 	"github.com/unrolled/secure"
 )
 
 var ENV = envy.Get("GO_ENV", "development")
 
 var (
-	app       *buffalo.App
-	appOnce   sync.Once
-	T         *i18n.Translator
-	gameStore *game.Store
+	app          *buffalo.App
+	appOnce      sync.Once
+	T            *i18n.Translator
+	gameStore    *game.Store
+	accountStore *internalaccounts.Store
 )
 
 func App() *buffalo.App {
@@ -33,7 +55,12 @@ func App() *buffalo.App {
 			Env:          ENV,
 			SessionStore: sessions.Null{},
 			PreWares: []buffalo.PreWare{
-				cors.Default().Handler,
+				cors.New(cors.Options{
+					AllowedOrigins: []string{"*"},
+					AllowedMethods: []string{"GET", "POST", "PATCH", "DELETE", "HEAD"},
+					AllowedHeaders: []string{"*"},
+					ExposedHeaders: []string{rooms.SessionTokenHeader, rooms.SessionExpiresAtHeader},
+				}).Handler,
 			},
 			SessionName: "_influence_game_session",
 		})
@@ -44,6 +71,11 @@ func App() *buffalo.App {
 		app.Use(contenttype.Set("application/json"))
 		app.Use(translations())
 
+		// Every documented request is checked against the document first.
+		api := openapi.New("Influence API", versions.V1)
+		app.Use(api.Validate)
+		routes := openapi.NewRouter(app, api)
+
 		// Rotas básicas
 		app.GET("/", HomeHandler)
 		app.GET("/healthz", func(ctx buffalo.Context) error {
@@ -51,6 +83,7 @@ func App() *buffalo.App {
 				"status": "ok",
 			}))
 		})
+		app.GET("/openapi.json", api.Handler)
 
 		// ============================================================
 		// 🔥 Redis Client
@@ -64,14 +97,107 @@ func App() *buffalo.App {
 		})
 
 		// ============================================================
-		// 🔥 Store + RoomsController
+		// 🔥 Role packs
+		// ============================================================
+		rolePacksPath := envy.Get("ROLE_PACKS_PATH", "config/role_packs.toml")
+		if err := game.LoadRolePacks(rolePacksPath); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.Fatal().Err(err).Str("path", rolePacksPath).Msg("Role packs could not be loaded.")
+			}
+		}
+
+		// ============================================================
+		// 🔥 Session tokens
+		// ============================================================
+		sessionSecret := []byte(envy.Get("SESSION_SECRET", ""))
+		if len(sessionSecret) == 0 {
+			if ENV == "production" {
+				log.Fatal().Msg("SESSION_SECRET must be set in production.")
+			}
+			log.Warn().Msg("SESSION_SECRET is not set; using a random secret, sessions will not survive a restart.")
+			sessionSecret = make([]byte, 32)
+			_, _ = rand.Read(sessionSecret)
+		}
+		sessionVerifier := internalsessions.NewVerifier(sessionSecret, redisClient)
+		go sessionVerifier.Listen(context.Background())
+
+		gameStore = game.NewStore(redisClient, sessionVerifier)
+		go gameStore.RunJanitor(context.Background())
+		gameStore.OnGameUpdated(bots.NewRunner(gameStore).HandleGameUpdated)
+		turnTimer := bots.NewTurnTimer(gameStore)
+		gameStore.OnGameUpdated(turnTimer.HandleGameUpdated)
+		go turnTimer.Restore(context.Background())
+		accountStore = internalaccounts.NewStore(redisClient)
+		ratingStore := internalratings.NewStore(redisClient)
+		gameStore.OnGameFinished(ratingStore.HandleGameFinished)
+		archiveStore := connectArchive()
+		if archiveStore != nil {
+			gameStore.OnGameFinished(archiveStore.HandleGameFinished)
+		}
+		tournamentStore := internaltournaments.NewStore(redisClient, gameStore)
+		gameStore.OnGameUpdated(tournamentStore.HandleGameUpdated)
+		roomsController := rooms.NewRoomsController(gameStore, accountStore)
+		app.Use(roomsController.SlideSession)
+		// Logging out ends the session and refreshing replaces it: neither slides.
+		app.Middleware.Skip(roomsController.SlideSession, roomsController.Logout, roomsController.RefreshSession)
+
+		accountsController := accounts.NewAccountsController(accountStore)
+		ratingsController := ratings.NewRatingsController(ratingStore, accountStore)
+		replaysController := replays.NewReplaysController(gameStore, archiveStore)
+		tournamentsController := tournaments.NewTournamentsController(tournamentStore, accountStore)
+
+		matchmakingQueue := internalmatchmaking.NewQueue(redisClient, gameStore, ratingStore)
+		go matchmakingQueue.Run(context.Background())
+		go matchmakingQueue.Listen(context.Background())
+		matchmakingController := matchmaking.NewMatchmakingController(matchmakingQueue, accountStore)
+
+		register := func(routes *openapi.Router) {
+			// Registrar rotas da feature /rooms
+			rooms.Register(routes, roomsController)
+			routes.Socket("/ws/rooms/{gameID}", GameWebSocketHandler)
+
+			// Registrar rotas da feature /accounts
+			accounts.Register(routes, accountsController)
+
+			// Registrar rotas da feature /users/{userID}/rating
+			ratings.Register(routes, ratingsController)
+
+			// Registrar rotas da feature /archive
+			if archiveStore != nil {
+				archive.Register(routes, archive.NewArchiveController(archiveStore))
+			}
+
+			// Registrar rotas da feature /games/{gameID}/replay
+			replays.Register(routes, replaysController)
+
+			// Registrar rotas da feature /matchmaking
+			matchmaking.Register(routes, matchmakingController)
+			routes.Socket("/ws/matchmaking", MatchmakingWebSocketHandler)
+
+			// Registrar rotas da feature /tournaments
+			tournaments.Register(routes, tournamentsController)
+		}
+
+		// ============================================================
+		// 🔥 API versions
 		// ============================================================
-		gameStore = game.NewStore(redisClient)
-		roomsController := rooms.NewRoomsController(gameStore)
+		// The routes without a version are the v1 routes old app builds
+		// call. Groups are made last, since they only get the middleware
+		// the app has by then.
+		register(routes)
+		api.DescribeEvents("")
+		for _, version := range versions.All {
+			prefix := "/" + version.String()
+			group := app.Group(prefix)
+			group.Use(apiVersion(version))
 
-		// Registrar rotas da feature /rooms
-		rooms.Register(app, roomsController)
-		app.GET("/ws/rooms/{gameID}", GameWebSocketHandler)
+			doc := openapi.New("Influence API", version)
+			group.Use(doc.Validate)
+			group.GET("/openapi.json", doc.Handler)
+
+			register(openapi.NewRouter(group, doc))
+			doc.DescribeEvents(prefix)
+		}
 
 		// ============================================================
 	})
@@ -79,11 +205,41 @@ func App() *buffalo.App {
 	return app
 }
 
+/*
+connectArchive opens the database named for ENV in database.yml and migrates
+it. In production the API does not start without it; elsewhere it keeps
+running without an archive when the database is unavailable.
+*/
+func connectArchive() *internalarchive.Store {
+	db, err := pop.Connect(ENV)
+	if err == nil {
+		err = db.Open()
+	}
+
+	var store *internalarchive.Store
+	if err == nil {
+		store = internalarchive.NewStore(db)
+		err = store.Migrate()
+	}
+
+	if err != nil {
+		if ENV == "production" {
+			log.Fatal().Err(err).Msg("Game archive is unavailable.")
+		}
+		log.Warn().Err(err).Msg("Game archive is unavailable; finished games will not be recorded.")
+		return nil
+	}
+
+	return store
+}
+
 func translations() buffalo.MiddlewareFunc {
 	var err error
-	if T, err = i18n.New(locales.FS(), "en-US"); err != nil {
+	if T, err = i18n.New(locales.FS(), locales.Languages[0]); err != nil {
 		app.Stop(err)
 	}
+	T.LanguageExtractors = []i18n.LanguageExtractor{playerLanguage, acceptLanguage}
+	game.SetTranslator(translateEvent)
 	return T.Middleware()
 }
 
@@ -0,0 +1,93 @@
+package archive
+
+import (
+	"influence_game/actions/apierrors"
+	"influence_game/internal/archive"
+	"strconv"
+	"strings"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+type ArchiveController struct {
+	Store *archive.Store
+}
+
+func NewArchiveController(store *archive.Store) *ArchiveController {
+	return &ArchiveController{Store: store}
+}
+
+// ListGames pages through finished games, optionally only those of one
+// account (?userId=) or nickname (?nickname=).
+func (controller *ArchiveController) ListGames(ctx buffalo.Context) error {
+	page, err := intParam(ctx, "page", archive.ErrInvalidPage)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+	perPage, err := intParam(ctx, "per_page", archive.ErrInvalidPerPage)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	filter := archive.GameFilter{
+		UserID:   ctx.Param("userId"),
+		Nickname: strings.ToLower(strings.TrimSpace(ctx.Param("nickname"))),
+	}
+
+	games, err := controller.Store.ListGames(filter, page, perPage)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list archived games.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(games))
+}
+
+func (controller *ArchiveController) GetGame(ctx buffalo.Context) error {
+	detail, err := controller.Store.GetGame(ctx.Param("gameID"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get archived game.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(detail))
+}
+
+func (controller *ArchiveController) UserStats(ctx buffalo.Context) error {
+	stats, err := controller.Store.UserStats(ctx.Param("userID"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get user stats.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(stats))
+}
+
+func (controller *ArchiveController) NicknameStats(ctx buffalo.Context) error {
+	nickname := strings.ToLower(strings.TrimSpace(ctx.Param("nickname")))
+
+	stats, err := controller.Store.NicknameStats(nickname)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get nickname stats.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(stats))
+}
+
+func intParam(ctx buffalo.Context, name string, invalid error) (int, error) {
+	value := ctx.Param(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		return 0, invalid
+	}
+	return parsed, nil
+}
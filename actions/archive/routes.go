@@ -0,0 +1,37 @@
+package archive
+
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/archive"
+)
+
+func Register(routes *openapi.Router, controller *ArchiveController) {
+	routes = routes.Tagged("archive")
+
+	routes.GET("/archive/games", controller.ListGames, openapi.Operation{
+		ID:      "listArchivedGames",
+		Summary: "Finished games, newest first.",
+		Query: []openapi.Parameter{
+			{Name: "page", Schema: openapi.AtLeast(1, archive.ErrInvalidPage)},
+			{Name: "per_page", Schema: openapi.AtLeast(1, archive.ErrInvalidPerPage)},
+			{Name: "userId", Description: "Only games this account played.", Schema: openapi.String()},
+			{Name: "nickname", Description: "Only games a player with this nickname played.", Schema: openapi.String()},
+		},
+		Response: archive.GamePage{},
+	})
+	routes.GET("/archive/games/{gameID}", controller.GetGame, openapi.Operation{
+		ID:       "getArchivedGame",
+		Summary:  "A finished game and everyone who played it.",
+		Response: archive.GameDetail{},
+	})
+	routes.GET("/users/{userID}/stats", controller.UserStats, openapi.Operation{
+		ID:       "getUserStats",
+		Summary:  "How an account has done in finished games.",
+		Response: archive.PlayerStats{},
+	})
+	routes.GET("/players/{nickname}/stats", controller.NicknameStats, openapi.Operation{
+		ID:       "getNicknameStats",
+		Summary:  "How a nickname has done in finished games.",
+		Response: archive.PlayerStats{},
+	})
+}
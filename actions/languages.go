@@ -0,0 +1,63 @@
+package actions
+
+import (
+	"influence_game/locales"
+	"net/http"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/middleware/i18n"
+)
+
+/*
+playerLanguage is the language a player saved for their seat, for requests
+made with their game session. It comes before anything the client asks for.
+It runs on every request, which is why the store keeps each seat's answer for
+a while rather than loading the game.
+*/
+func playerLanguage(_ i18n.LanguageExtractorOptions, ctx buffalo.Context) []string {
+	token := extractBearerToken(ctx.Request().Header.Get("Authorization"))
+	if token == "" || gameStore == nil {
+		return nil
+	}
+
+	if language := gameStore.PlayerLanguage(token); language != "" {
+		return []string{language}
+	}
+	return nil
+}
+
+// acceptLanguage is the first supported language of the Accept-Language header.
+func acceptLanguage(_ i18n.LanguageExtractorOptions, ctx buffalo.Context) []string {
+	if language := locales.FromHeader(ctx.Request().Header.Get("Accept-Language")); language != "" {
+		return []string{language}
+	}
+	return nil
+}
+
+/*
+socketLanguage picks the language a game socket gets its messages in: the
+player's saved preference, then ?lang=, since browsers cannot set headers on
+WebSockets, then Accept-Language.
+*/
+func socketLanguage(r *http.Request, token string) string {
+	if language := gameStore.PlayerLanguage(token); language != "" {
+		return language
+	}
+	if language := locales.Match(r.URL.Query().Get("lang")); language != "" {
+		return language
+	}
+	return locales.FromHeader(r.Header.Get("Accept-Language"))
+}
+
+// translateEvent renders event messages for the game package.
+func translateEvent(language string, id string, data map[string]any) string {
+	if language == "" {
+		language = T.DefaultLanguage
+	}
+
+	message, err := T.TranslateWithLang(language, id, data)
+	if err != nil {
+		message, _ = T.TranslateWithLang(T.DefaultLanguage, id, data)
+	}
+	return message
+}
@@ -0,0 +1,95 @@
+package matchmaking
+
+import (
+	"influence_game/actions/apierrors"
+	"influence_game/internal/accounts"
+	"influence_game/internal/game"
+	"influence_game/internal/matchmaking"
+	"strings"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+type MatchmakingController struct {
+	Queue    *matchmaking.Queue
+	Accounts *accounts.Store
+}
+
+func NewMatchmakingController(queue *matchmaking.Queue, accountStore *accounts.Store) *MatchmakingController {
+	return &MatchmakingController{Queue: queue, Accounts: accountStore}
+}
+
+func (controller *MatchmakingController) Enqueue(ctx buffalo.Context) error {
+	log.Info().Msg("Joining matchmaking queue.")
+
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	status, err := controller.Queue.Enqueue(account.ID, account.Username)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to join matchmaking queue.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Joined matchmaking queue successfully.")
+
+	return ctx.Render(200, renderer.JSON(status))
+}
+
+func (controller *MatchmakingController) Status(ctx buffalo.Context) error {
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	status, err := controller.Queue.Status(account.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get matchmaking status.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(status))
+}
+
+func (controller *MatchmakingController) Leave(ctx buffalo.Context) error {
+	log.Info().Msg("Leaving matchmaking queue.")
+
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	if err := controller.Queue.Leave(account.ID); err != nil {
+		log.Error().Err(err).Msg("Failed to leave matchmaking queue.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Left matchmaking queue successfully.")
+
+	return ctx.Render(200, renderer.JSON(map[string]any{
+		"left": true,
+	}))
+}
+
+// Ranked matchmaking needs an account, since ratings follow the user.
+func (controller *MatchmakingController) getAccount(ctx buffalo.Context) (*accounts.Account, error) {
+	authHeader := ctx.Request().Header.Get("Authorization")
+	const prefix = "Bearer "
+
+	if !strings.HasPrefix(authHeader, prefix) {
+		return nil, game.ErrMissingAuthorization
+	}
+
+	token := strings.TrimPrefix(authHeader, prefix)
+	if token == "" {
+		return nil, game.ErrMissingAuthorization
+	}
+
+	return controller.Accounts.ResolveToken(token)
+}
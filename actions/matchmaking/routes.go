@@ -0,0 +1,29 @@
+package matchmaking
+
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/matchmaking"
+)
+
+func Register(routes *openapi.Router, controller *MatchmakingController) {
+	routes = routes.Tagged("matchmaking")
+
+	routes.POST("/matchmaking/queue", controller.Enqueue, openapi.Operation{
+		ID:       "joinQueue",
+		Summary:  "Queue for a ranked game.",
+		Auth:     openapi.AuthAccount,
+		Response: matchmaking.Status{},
+	})
+	routes.GET("/matchmaking/queue", controller.Status, openapi.Operation{
+		ID:       "getQueueStatus",
+		Summary:  "Where the account stands in the queue.",
+		Auth:     openapi.AuthAccount,
+		Response: matchmaking.Status{},
+	})
+	routes.DELETE("/matchmaking/queue", controller.Leave, openapi.Operation{
+		ID:       "leaveQueue",
+		Summary:  "Stop looking for a ranked game.",
+		Auth:     openapi.AuthAccount,
+		Response: openapi.Object(map[string]*openapi.Schema{"left": openapi.Boolean()}, "left"),
+	})
+}
@@ -0,0 +1,206 @@
+/*
+Package openapi describes the API as an OpenAPI 3 document while its routes
+are registered, serves it at /openapi.json and checks requests against it.
+The WebSocket events are described alongside, in the style of AsyncAPI,
+under "x-asyncapi".
+*/
+package openapi
+
+import (
+	"influence_game/internal/versions"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+)
+
+var renderer = render.New(render.Options{})
+
+// Who may call an operation.
+type Auth int
+
+const (
+	AuthNone Auth = iota
+	// A game session token, from creating, joining or rejoining a room.
+	AuthSession
+	// An account token, from signing in.
+	AuthAccount
+	// Anyone; an account token links what they do to their account.
+	AuthOptionalAccount
+)
+
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components Components                       `json:"components"`
+	AsyncAPI   *AsyncAPI                        `json:"x-asyncapi,omitempty"`
+
+	types   map[string]reflect.Type
+	version versions.Version
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]*response      `json:"responses"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+/*
+Operation is what a route documents about itself. Body and Response are a
+value of the type sent and answered, or a *Schema when there is no such type.
+*/
+type Operation struct {
+	ID       string
+	Summary  string
+	Auth     Auth
+	Query    []Parameter
+	Body     any
+	Response any
+	Status   int // of a successful answer; 200 when unset
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type securityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+// New starts the document of one version of the API, in that version's names.
+func New(title string, version versions.Version) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version.String()},
+		Paths:   map[string]map[string]*operation{},
+		Components: Components{
+			Schemas:   map[string]*Schema{},
+			Responses: map[string]*response{},
+			SecuritySchemes: map[string]securityScheme{
+				"session": {Type: "http", Scheme: "bearer", Description: "Game session token, from creating, joining or rejoining a room."},
+				"account": {Type: "http", Scheme: "bearer", Description: "Account token, from registering or logging in."},
+			},
+		},
+		types:   map[string]reflect.Type{},
+		version: version,
+	}
+
+	doc.Components.Schemas["Error"] = Object(map[string]*Schema{
+		"error":   {Type: "string", Description: "Stable error code."},
+		"message": {Type: "string", Description: "The error in the request's language."},
+		"detail":  {Type: "string", Description: "More about this occurrence, when there is more to say."},
+	}, "error", "message")
+	doc.Components.Responses["Error"] = &response{
+		Description: "The request failed.",
+		Content:     jsonContent(&Schema{Ref: "#/components/schemas/Error"}),
+	}
+
+	return doc
+}
+
+// Handler answers with the document itself.
+func (doc *Document) Handler(ctx buffalo.Context) error {
+	return ctx.Render(http.StatusOK, renderer.JSON(doc))
+}
+
+var pathParameter = regexp.MustCompile(`{([^}]+)}`)
+
+func (doc *Document) add(method string, path string, tag string, op Operation) {
+	documented := &operation{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Responses:   map[string]*response{"default": {Ref: "#/components/responses/Error"}},
+	}
+	if tag != "" {
+		documented.Tags = []string{tag}
+	}
+
+	switch op.Auth {
+	case AuthSession:
+		documented.Security = []map[string][]string{{"session": {}}}
+	case AuthAccount:
+		documented.Security = []map[string][]string{{"account": {}}}
+	case AuthOptionalAccount:
+		documented.Security = []map[string][]string{{}, {"account": {}}}
+	}
+
+	for _, match := range pathParameter.FindAllStringSubmatch(path, -1) {
+		documented.Parameters = append(documented.Parameters, Parameter{
+			Name: match[1], In: "path", Required: true, Schema: String(),
+		})
+	}
+	for _, query := range op.Query {
+		query.In = "query"
+		query.Name = doc.version.Key(query.Name)
+		documented.Parameters = append(documented.Parameters, query)
+	}
+
+	if op.Body != nil {
+		documented.RequestBody = &requestBody{Required: true, Content: jsonContent(doc.Of(op.Body))}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	ok := &response{Description: http.StatusText(status)}
+	if op.Response != nil {
+		ok.Content = jsonContent(doc.Of(op.Response))
+	}
+	documented.Responses[strconv.Itoa(status)] = ok
+
+	path = strings.TrimSuffix(path, "/")
+	if doc.Paths[path] == nil {
+		doc.Paths[path] = map[string]*operation{}
+	}
+	doc.Paths[path][strings.ToLower(method)] = documented
+}
+
+func (doc *Document) operation(method string, path string) *operation {
+	return doc.Paths[strings.TrimSuffix(path, "/")][strings.ToLower(method)]
+}
+
+func jsonContent(schema *Schema) map[string]mediaType {
+	return map[string]mediaType{"application/json": {Schema: schema}}
+}
@@ -0,0 +1,205 @@
+package openapi
+
+import (
+	"influence_game/internal/game"
+	"influence_game/internal/matchmaking"
+	"influence_game/internal/versions"
+)
+
+/*
+AsyncAPI describes what the WebSockets send, following the AsyncAPI 2.6
+layout: one channel per socket, one message per event type.
+*/
+type AsyncAPI struct {
+	AsyncAPI string              `json:"asyncapi"`
+	Channels map[string]*channel `json:"channels"`
+}
+
+type channel struct {
+	Description string                `json:"description"`
+	Parameters  map[string]*Parameter `json:"parameters,omitempty"`
+	Bindings    map[string]any        `json:"bindings,omitempty"`
+	Subscribe   subscribe             `json:"subscribe"`
+}
+
+type subscribe struct {
+	Message struct {
+		OneOf []*message `json:"oneOf"`
+	} `json:"message"`
+}
+
+type message struct {
+	Name    string  `json:"name"`
+	Summary string  `json:"summary"`
+	Private bool    `json:"x-private,omitempty"`
+	Payload *Schema `json:"payload"`
+}
+
+// event is one event type and what its payload carries.
+type event struct {
+	name    string
+	summary string
+	private bool
+	payload *Schema
+}
+
+func (doc *Document) gameEvents() []event {
+	player := Object(map[string]*Schema{"playerId": String()}, "playerId")
+
+	canceled := Object(map[string]*Schema{
+		"actionId":   String(),
+		"actionName": String(),
+		"actorId":    String(),
+		"reason":     {Type: "string", Enum: []string{"blocked", "block_upheld", "challenge_won"}},
+	}, "actionId", "actionName", "actorId", "reason")
+
+	resolved := Object(map[string]*Schema{
+		"actionId":   String(),
+		"actionName": String(),
+		"actorId":    String(),
+		"targetId":   {Type: "string", Nullable: true},
+	}, "actionId", "actionName", "actorId")
+
+	return []event{
+		{"player_joined", "A player or bot took a seat.", false,
+			Object(map[string]*Schema{"newPlayer": doc.Of(game.Player{})}, "newPlayer")},
+		{"player_kicked", "The admin removed a player from the lobby.", false,
+			Object(map[string]*Schema{"playerId": String(), "nickname": String()}, "playerId", "nickname")},
+		{"player_rejoined", "A player took their seat back with a rejoin code.", false, player},
+		{"settings_updated", "The admin changed the room settings.", false, nil},
+		{"game_started", "The cards were dealt.", false, nil},
+		{"influence_draft", "The cards dealt to you in the two-player draft.", true,
+			Object(map[string]*Schema{"dealt": ArrayOf(doc.Of(game.Influence{})), "choose": Integer()}, "dealt", "choose")},
+		{"influences_selected", "A player picked their influences in the draft.", false, player},
+		{"draft_completed", "Every player has picked their influences.", false, nil},
+		{"action_declared", "The player whose turn it is declared an action.", false,
+			Object(map[string]*Schema{"actionPayload": doc.Of(game.DeclareActionPayload{})}, "actionPayload")},
+		{"action_blocked", "A player blocked the pending action.", false,
+			Object(map[string]*Schema{"actionId": String(), "blockerId": String(), "role": String()}, "actionId", "blockerId", "role")},
+		{"action_challenged", "A player challenged a claim; claimHolds tells who was right.", false,
+			Object(map[string]*Schema{
+				"actionId":     String(),
+				"claimantId":   String(),
+				"challengerId": String(),
+				"role":         String(),
+				"claimHolds":   Boolean(),
+			}, "actionId", "claimantId", "challengerId", "role", "claimHolds")},
+		{"action_canceled", "The pending action did not happen.", false, canceled},
+		{"action_resolved", "The pending action took effect.", false, resolved},
+		{"influence_lost", "A player revealed an influence.", false,
+			Object(map[string]*Schema{"playerId": String(), "role": String(), "reason": String()}, "playerId", "role", "reason")},
+		{"player_eliminated", "A player has no influence left.", false, player},
+		{"game_finished", "One player is left.", false,
+			Object(map[string]*Schema{"winnerId": String()}, "winnerId")},
+		{"exchange_options", "The cards you drew for an exchange.", true,
+			Object(map[string]*Schema{"drawn": ArrayOf(doc.Of(game.Influence{})), "keep": Integer()}, "drawn", "keep")},
+		{"exchange_completed", "A player finished their exchange.", false, player},
+		{"examine_result", "The card you are examining.", true,
+			Object(map[string]*Schema{"targetId": String(), "card": doc.Of(game.Influence{})}, "targetId", "card")},
+		{"influence_swapped", "An examiner made you swap a card.", true,
+			Object(map[string]*Schema{"lost": doc.Of(game.Influence{}), "received": doc.Of(game.Influence{})}, "lost", "received")},
+		{"examination_completed", "An examination ended.", false,
+			Object(map[string]*Schema{"examinerId": String(), "targetId": String(), "forcedSwap": Boolean()}, "examinerId", "targetId", "forcedSwap")},
+		{"rematch_created", "The finished game was dealt again; the sockets now belong to the rematch.", false,
+			Object(map[string]*Schema{"previousGameId": String()}, "previousGameId")},
+		{"session_migrated", "Your session and rejoin code for the rematch.", true,
+			Object(map[string]*Schema{"token": String(), "rejoinCode": String()}, "token", "rejoinCode")},
+	}
+}
+
+func (doc *Document) matchmakingEvents() []event {
+	return []event{
+		{"queue_status", "How long you have waited and how many are queued.", true,
+			Object(map[string]*Schema{"waitSeconds": Integer(), "queueSize": Integer()}, "waitSeconds", "queueSize")},
+		{"match_found", "You were seated in a ranked game.", true,
+			Object(map[string]*Schema{"match": doc.Of(matchmaking.Match{})}, "match")},
+	}
+}
+
+/*
+DescribeEvents adds the WebSocket channels under prefix, and the events they
+carry, to the document.
+*/
+func (doc *Document) DescribeEvents(prefix string) {
+	// From v2 game events are named the way the matchmaking socket's are.
+	gameEvent, kind := doc.Of(game.ServerEvent{}), "eventType"
+	if doc.version >= versions.V2 {
+		gameEvent, kind = doc.Of(game.ServerEventV2{}), "type"
+	}
+
+	gameMessages := make([]*message, 0)
+	for _, e := range doc.gameEvents() {
+		gameMessages = append(gameMessages, &message{
+			Name:    e.name,
+			Summary: e.summary,
+			Private: e.private,
+			Payload: &Schema{AllOf: []*Schema{gameEvent, Object(map[string]*Schema{
+				kind:      {Type: "string", Enum: []string{e.name}},
+				"payload": payloadSchema(e.payload),
+			})}},
+		})
+	}
+
+	queueMessages := make([]*message, 0)
+	for _, e := range doc.matchmakingEvents() {
+		queueMessages = append(queueMessages, &message{
+			Name:    e.name,
+			Summary: e.summary,
+			Private: e.private,
+			Payload: Object(map[string]*Schema{
+				"type":      {Type: "string", Enum: []string{e.name}},
+				"timestamp": {Type: "string", Format: "date-time"},
+				"payload":   payloadSchema(e.payload),
+			}, "type", "timestamp"),
+		})
+	}
+
+	rooms := &channel{
+		Description: "Everything that happens in a room, for the players seated in it. Events marked x-private only reach the player concerned.",
+		Parameters: map[string]*Parameter{
+			"gameID": {Name: "gameID", In: "path", Required: true, Schema: String()},
+		},
+		Bindings: map[string]any{"ws": map[string]any{"query": Object(map[string]*Schema{
+			"token":   {Type: "string", Description: "Game session token."},
+			"lang":    {Type: "string", Description: "Language for event messages, when the player has not saved one."},
+			"version": versionParameter(),
+		}, "token")}},
+	}
+	rooms.Subscribe.Message.OneOf = gameMessages
+
+	queue := &channel{
+		Description: "Ranked matchmaking updates for a queued account.",
+		Bindings: map[string]any{"ws": map[string]any{"query": Object(map[string]*Schema{
+			"token":   {Type: "string", Description: "Account token."},
+			"version": versionParameter(),
+		}, "token")}},
+	}
+	queue.Subscribe.Message.OneOf = queueMessages
+
+	doc.AsyncAPI = &AsyncAPI{
+		AsyncAPI: "2.6.0",
+		Channels: map[string]*channel{
+			prefix + "/ws/rooms/{gameID}": rooms,
+			prefix + "/ws/matchmaking":    queue,
+		},
+	}
+}
+
+func versionParameter() *Schema {
+	names := make([]string, 0, len(versions.All))
+	for _, version := range versions.All {
+		names = append(names, version.String())
+	}
+	return &Schema{
+		Type:        "string",
+		Enum:        names,
+		Description: "Protocol version, for clients that cannot offer it as an influence.<version> subprotocol. Defaults to the version in the path, or v1.",
+	}
+}
+
+func payloadSchema(payload *Schema) *Schema {
+	if payload == nil {
+		return &Schema{Type: "object", Nullable: true, Description: "Not sent."}
+	}
+	return payload
+}
@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"errors"
+	"influence_game/internal/game"
+	"influence_game/internal/versions"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testSettings struct {
+	MaxPlayers *int     `json:"maxPlayers,omitempty"`
+	Variants   []string `json:"variants,omitempty"`
+}
+
+type testRoomDTO struct {
+	Nickname string        `json:"nickname" openapi:"required,minLength=1,error=nickname_is_required"`
+	Roles    []string      `json:"roles,omitempty" openapi:"minItems=2,maxItems=2,error=two_roles_are_required"`
+	Settings *testSettings `json:"settings,omitempty"`
+	internal string
+}
+
+func testDocument() *Document {
+	doc := New("Test", versions.V1)
+	doc.add("POST", "/rooms/", "rooms", Operation{ID: "createRoom", Body: testRoomDTO{}, Response: testRoomDTO{}})
+	doc.add("GET", "/games/{gameID}/replay/", "replays", Operation{
+		ID:    "getReplay",
+		Query: []Parameter{{Name: "step", Schema: AtLeast(0, game.ErrInvalidStep)}},
+	})
+	return doc
+}
+
+func TestStructsBecomeComponents(t *testing.T) {
+	doc := testDocument()
+
+	room := doc.Components.Schemas["testRoomDTO"]
+	if room == nil {
+		t.Fatalf("expected testRoomDTO to be a component, got %v", doc.Components.Schemas)
+	}
+	if len(room.Required) != 1 || room.Required[0] != "nickname" {
+		t.Fatalf("expected only nickname to be required, got %v", room.Required)
+	}
+	if _, ok := room.Properties["internal"]; ok {
+		t.Fatalf("expected unexported fields to be left out")
+	}
+	settings := room.Properties["settings"]
+	if !settings.Nullable || len(settings.AllOf) != 1 || settings.AllOf[0].Ref != "#/components/schemas/testSettings" {
+		t.Fatalf("expected settings to refer to its component and allow null, got %+v", settings)
+	}
+	if !doc.Components.Schemas["testSettings"].Properties["maxPlayers"].Nullable {
+		t.Fatalf("expected pointer fields to be nullable")
+	}
+
+	created := doc.Paths["/rooms"]["post"]
+	if created == nil || created.Tags[0] != "rooms" || created.Responses["200"] == nil {
+		t.Fatalf("expected POST /rooms to be documented, got %+v", created)
+	}
+	replay := doc.Paths["/games/{gameID}/replay"]["get"]
+	if len(replay.Parameters) != 2 || replay.Parameters[0].In != "path" || replay.Parameters[1].In != "query" {
+		t.Fatalf("expected a path and a query parameter, got %+v", replay.Parameters)
+	}
+}
+
+func TestUnknownTagOptionsPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected an unknown error code to panic")
+		}
+	}()
+
+	type badDTO struct {
+		Name string `json:"name" openapi:"error=no_such_error"`
+	}
+	New("Test", versions.V1).Of(badDTO{})
+}
+
+func TestValidateRequest(t *testing.T) {
+	doc := testDocument()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   error
+		detail string
+	}{
+		{"valid body", "POST", "/rooms/", `{"nickname":"ana","settings":{"maxPlayers":4}}`, nil, ""},
+		{"null slices", "POST", "/rooms/", `{"nickname":"ana","settings":{"variants":null}}`, nil, ""},
+		{"null pointers", "POST", "/rooms/", `{"nickname":"ana","settings":null}`, nil, ""},
+		{"missing field names its error", "POST", "/rooms/", `{}`, game.ErrNicknameRequired, ""},
+		{"empty field names its error", "POST", "/rooms/", `{"nickname":""}`, game.ErrNicknameRequired, ""},
+		{"array bounds name their error", "POST", "/rooms/", `{"nickname":"ana","roles":["duke"]}`, game.ErrTwoRolesRequired, ""},
+		{"null does not meet minItems", "POST", "/rooms/", `{"nickname":"ana","roles":null}`, game.ErrTwoRolesRequired, ""},
+		{"wrong type", "POST", "/rooms/", `{"nickname":"ana","settings":{"maxPlayers":"four"}}`, game.ErrInvalidRequest, "settings.maxPlayers must be a number"},
+		{"not an object", "POST", "/rooms/", `[]`, game.ErrInvalidRequest, "body must be an object"},
+		{"not json", "POST", "/rooms/", `{`, game.ErrInvalidJSON, ""},
+		{"too large", "POST", "/rooms/", `{"nickname":"` + strings.Repeat("a", MaxBodyBytes) + `"}`, game.ErrRequestTooLarge, ""},
+		{"valid query", "GET", "/games/g1/replay/?step=0", "", nil, ""},
+		{"query below minimum", "GET", "/games/g1/replay/?step=-1", "", game.ErrInvalidStep, ""},
+		{"query not a number", "GET", "/games/g1/replay/?step=last", "", game.ErrInvalidStep, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			path := "/games/{gameID}/replay/"
+			if test.method == "POST" {
+				path = "/rooms/"
+			}
+
+			err := doc.validateRequest(httptest.NewRecorder(), req, doc.operation(test.method, path))
+			if !errors.Is(err, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, err)
+			}
+			if test.detail != "" && !strings.HasSuffix(err.Error(), test.detail) {
+				t.Fatalf("expected detail %q, got %q", test.detail, err.Error())
+			}
+
+			if test.method == http.MethodPost && err == nil {
+				restored, _ := io.ReadAll(req.Body)
+				if string(restored) != test.body {
+					t.Fatalf("expected the body to be readable again, got %q", restored)
+				}
+			}
+		})
+	}
+}
+
+func TestEventsAreDescribed(t *testing.T) {
+	doc := New("Test", versions.V1)
+	doc.DescribeEvents("")
+
+	rooms := doc.AsyncAPI.Channels["/ws/rooms/{gameID}"]
+	if rooms == nil || len(rooms.Subscribe.Message.OneOf) == 0 {
+		t.Fatalf("expected the room socket to be described")
+	}
+
+	seen := map[string]bool{}
+	for _, message := range rooms.Subscribe.Message.OneOf {
+		if seen[message.Name] {
+			t.Fatalf("expected %s to be described once", message.Name)
+		}
+		seen[message.Name] = true
+	}
+	for _, name := range []string{"game_started", "action_declared", "game_finished", "session_migrated"} {
+		if !seen[name] {
+			t.Fatalf("expected %s to be described", name)
+		}
+	}
+
+	if doc.Components.Schemas["ServerEvent"] == nil {
+		t.Fatalf("expected ServerEvent to be a component")
+	}
+}
+
+func TestLaterVersionsUseTheirNames(t *testing.T) {
+	type page struct {
+		GameID string `json:"gameID"`
+	}
+
+	doc := New("Test", versions.V2)
+	doc.add("GET", "/v2/archive/games/", "archive", Operation{
+		ID:       "listArchivedGames",
+		Query:    []Parameter{{Name: "per_page", Schema: AtLeast(1, game.ErrInvalidStep)}},
+		Response: page{},
+	})
+	doc.DescribeEvents("/v2")
+
+	if name := doc.Paths["/v2/archive/games"]["get"].Parameters[0].Name; name != "perPage" {
+		t.Fatalf("expected the v2 query parameter name, got %s", name)
+	}
+	if _, ok := doc.Components.Schemas["page"].Properties["gameId"]; !ok {
+		t.Fatalf("expected the v2 field name, got %v", doc.Components.Schemas["page"].Properties)
+	}
+
+	rooms := doc.AsyncAPI.Channels["/v2/ws/rooms/{gameID}"]
+	if rooms == nil {
+		t.Fatalf("expected the room socket under the version's path, got %v", doc.AsyncAPI.Channels)
+	}
+	envelope := rooms.Subscribe.Message.OneOf[0].Payload.AllOf
+	if envelope[0].Ref != "#/components/schemas/ServerEventV2" || envelope[1].Properties["type"] == nil {
+		t.Fatalf("expected v2 events to be named like the matchmaking socket's, got %+v", envelope)
+	}
+}
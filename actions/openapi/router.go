@@ -0,0 +1,48 @@
+package openapi
+
+import "github.com/gobuffalo/buffalo"
+
+/*
+Router registers routes on a buffalo app and documents them in the same
+call, so no route goes undocumented.
+*/
+type Router struct {
+	app *buffalo.App
+	doc *Document
+	tag string
+}
+
+func NewRouter(app *buffalo.App, doc *Document) *Router {
+	return &Router{app: app, doc: doc}
+}
+
+// Tagged groups the routes registered through it under tag in the document.
+func (router *Router) Tagged(tag string) *Router {
+	return &Router{app: router.app, doc: router.doc, tag: tag}
+}
+
+// Schema describes value's type in the document, for responses built inline.
+func (router *Router) Schema(value any) *Schema {
+	return router.doc.Of(value)
+}
+
+// Socket registers a WebSocket; sockets are described by DescribeEvents, not as paths.
+func (router *Router) Socket(path string, handler buffalo.Handler) {
+	router.app.GET(path, handler)
+}
+
+func (router *Router) GET(path string, handler buffalo.Handler, op Operation) {
+	router.doc.add("GET", router.app.GET(path, handler).Path, router.tag, op)
+}
+
+func (router *Router) POST(path string, handler buffalo.Handler, op Operation) {
+	router.doc.add("POST", router.app.POST(path, handler).Path, router.tag, op)
+}
+
+func (router *Router) PATCH(path string, handler buffalo.Handler, op Operation) {
+	router.doc.add("PATCH", router.app.PATCH(path, handler).Path, router.tag, op)
+}
+
+func (router *Router) DELETE(path string, handler buffalo.Handler, op Operation) {
+	router.doc.add("DELETE", router.app.DELETE(path, handler).Path, router.tag, op)
+}
@@ -0,0 +1,255 @@
+package openapi
+
+import (
+	"encoding"
+	"influence_game/internal/game"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Schema is the part of JSON Schema that OpenAPI 3.0 uses, as far as this API
+needs it. Error names the catalogued error a request gets when this value
+does not match, instead of the generic invalid_request.
+*/
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Error                string             `json:"x-error,omitempty"`
+}
+
+func String() *Schema  { return &Schema{Type: "string"} }
+func Integer() *Schema { return &Schema{Type: "integer"} }
+func Boolean() *Schema { return &Schema{Type: "boolean"} }
+
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// AtLeast is a whole number no lower than minimum; anything else is answered with invalid.
+func AtLeast(minimum float64, invalid *game.Error) *Schema {
+	return &Schema{Type: "integer", Minimum: &minimum, Error: invalid.Code}
+}
+
+// Object is an inline object schema; every property listed in required must be sent.
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+/*
+Of describes the JSON encoding of value's type, the way encoding/json writes
+it. Named structs go to the document's components and are referenced from
+there. Fields can be narrowed with an openapi struct tag:
+
+	Nickname string `json:"nickname" openapi:"required,minLength=1,error=nickname_is_required"`
+
+A *Schema is returned as is, for bodies that are not a Go type.
+*/
+func (doc *Document) Of(value any) *Schema {
+	if schema, ok := value.(*Schema); ok {
+		return schema
+	}
+	return doc.schemaOf(reflect.TypeOf(value))
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (doc *Document) schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	// Types that write themselves: times, UUIDs and the nulls package.
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(textMarshalerType):
+		return String()
+	case t.PkgPath() == "github.com/gobuffalo/nulls" && t.Kind() == reflect.Struct:
+		// Each is its value's field plus Valid, and null when not valid.
+		return nullable(doc.schemaOf(t.Field(0).Type))
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(doc.schemaOf(t.Elem()))
+	case reflect.Struct:
+		if t.Name() == "" {
+			return doc.objectOf(t)
+		}
+		return doc.component(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		// encoding/json writes nil slices and maps as null.
+		return &Schema{Type: "array", Items: doc.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: doc.schemaOf(t.Elem()), Nullable: true}
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer()
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+
+	// Interfaces and anything else: any JSON value.
+	return &Schema{}
+}
+
+// nullable is schema that also accepts null.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		// OpenAPI 3.0 ignores siblings of $ref.
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	copied := *schema
+	copied.Nullable = true
+	return &copied
+}
+
+/*
+component registers a named struct under components/schemas. Types from
+different packages that share a name are told apart by their package.
+*/
+func (doc *Document) component(t reflect.Type) *Schema {
+	name := t.Name()
+	if other, taken := doc.types[name]; taken && other != t {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, done := doc.types[name]; done {
+		return ref
+	}
+
+	// Registered before it is filled in, so types that refer to themselves end.
+	doc.types[name] = t
+	doc.Components.Schemas[name] = &Schema{}
+	*doc.Components.Schemas[name] = *doc.objectOf(t)
+
+	return ref
+}
+
+func (doc *Document) objectOf(t reflect.Type) *Schema {
+	object := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	doc.addFields(object, t)
+	return object
+}
+
+func (doc *Document) addFields(object *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				doc.addFields(object, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = doc.version.Key(name)
+
+		schema := doc.schemaOf(field.Type)
+		if options := field.Tag.Get("openapi"); options != "" {
+			schema = narrow(schema, options, func() { object.Required = append(object.Required, name) })
+		}
+		object.Properties[name] = schema
+	}
+}
+
+/*
+narrow applies the options of an openapi struct tag to a copy of schema. A
+referenced schema is wrapped, since OpenAPI 3.0 ignores siblings of $ref.
+*/
+func narrow(schema *Schema, options string, required func()) *Schema {
+	narrowed := *schema
+	if narrowed.Ref != "" {
+		narrowed = Schema{AllOf: []*Schema{schema}}
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "required":
+			required()
+		case "error":
+			if _, ok := game.LookupError(value); !ok {
+				panic("unknown error code " + value)
+			}
+			narrowed.Error = value
+		case "enum":
+			narrowed.Enum = strings.Split(value, "|")
+		case "minimum":
+			narrowed.Minimum = floatOption(value)
+		case "maximum":
+			narrowed.Maximum = floatOption(value)
+		case "minLength":
+			narrowed.MinLength = intOption(value)
+		case "minItems":
+			// null has no items to count, so it cannot have enough.
+			narrowed.MinItems = intOption(value)
+			narrowed.Nullable = false
+		case "maxItems":
+			narrowed.MaxItems = intOption(value)
+		default:
+			panic("unknown openapi tag option " + key)
+		}
+	}
+
+	return &narrowed
+}
+
+func floatOption(value string) *float64 {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		panic("bad openapi tag number " + value)
+	}
+	return &parsed
+}
+
+func intOption(value string) *int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		panic("bad openapi tag number " + value)
+	}
+	return &parsed
+}
@@ -0,0 +1,267 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"influence_game/actions/apierrors"
+	"influence_game/internal/game"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gobuffalo/buffalo"
+)
+
+/*
+MaxBodyBytes is the largest request body read for validation. The biggest
+bodies the API takes are notation documents sent to be verified, and a long
+game's moves fit well within it.
+*/
+const MaxBodyBytes = 1 << 20
+
+/*
+Validate checks the query and body of every documented request against the
+document before its handler runs. Requests that do not match are answered
+with the error the failing field names, or invalid_request with what was
+wrong as the detail.
+*/
+func (doc *Document) Validate(next buffalo.Handler) buffalo.Handler {
+	return func(ctx buffalo.Context) error {
+		route, _ := ctx.Value("current_route").(buffalo.RouteInfo)
+
+		documented := doc.operation(route.Method, route.Path)
+		if documented == nil {
+			return next(ctx)
+		}
+
+		if err := doc.validateRequest(ctx.Response(), ctx.Request(), documented); err != nil {
+			return apierrors.Render(ctx, err)
+		}
+
+		return next(ctx)
+	}
+}
+
+func (doc *Document) validateRequest(w http.ResponseWriter, req *http.Request, documented *operation) error {
+	query := req.URL.Query()
+	for _, parameter := range documented.Parameters {
+		if parameter.In != "query" {
+			continue
+		}
+
+		raw := query.Get(parameter.Name)
+		if raw == "" {
+			if parameter.Required {
+				return doc.reject(&violation{path: parameter.Name, problem: "is required"}, parameter.Schema)
+			}
+			continue
+		}
+
+		if v := doc.check(parameter.Schema, queryValue(parameter.Schema, raw), parameter.Name); v != nil {
+			return doc.reject(v, parameter.Schema)
+		}
+	}
+
+	if documented.RequestBody == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, req.Body, MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return game.ErrRequestTooLarge
+		}
+		return err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	var body any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return game.ErrInvalidJSON
+	}
+
+	schema := documented.RequestBody.Content["application/json"].Schema
+	if v := doc.check(schema, body, ""); v != nil {
+		return doc.reject(v, schema)
+	}
+
+	return nil
+}
+
+// queryValue reads a query parameter as the JSON value its schema expects.
+func queryValue(schema *Schema, raw string) any {
+	switch schema.Type {
+	case "integer", "number":
+		if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(raw); err == nil {
+			return parsed
+		}
+	}
+	return raw
+}
+
+// violation is the first place a value does not match its schema.
+type violation struct {
+	path    string
+	problem string
+	code    string // of the closest enclosing schema that names an error
+}
+
+func (doc *Document) reject(v *violation, schema *Schema) error {
+	code := v.code
+	if code == "" {
+		code = schema.Error
+	}
+	if known, ok := game.LookupError(code); ok {
+		return known
+	}
+
+	path := v.path
+	if path == "" {
+		path = "body"
+	}
+	return fmt.Errorf("%w: %s %s", game.ErrInvalidRequest, path, v.problem)
+}
+
+func (doc *Document) check(schema *Schema, value any, path string) *violation {
+	if value == nil && schema.Nullable {
+		return nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		return doc.check(doc.Components.Schemas[name], value, path)
+	}
+	for _, part := range schema.AllOf {
+		if v := doc.check(part, value, path); v != nil {
+			return v
+		}
+	}
+
+	if value == nil {
+		if schema.Type == "" {
+			return nil
+		}
+		return &violation{path: path, problem: "must not be null"}
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return &violation{path: path, problem: "must be an object"}
+		}
+		return doc.checkObject(schema, object, path)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return &violation{path: path, problem: "must be an array"}
+		}
+		return doc.checkArray(schema, array, path)
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return &violation{path: path, problem: "must be a string"}
+		}
+		if schema.MinLength != nil && utf8.RuneCountInString(text) < *schema.MinLength {
+			return &violation{path: path, problem: fmt.Sprintf("must be at least %d characters long", *schema.MinLength)}
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+			return &violation{path: path, problem: "must be one of " + strings.Join(schema.Enum, ", ")}
+		}
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok {
+			return &violation{path: path, problem: "must be a number"}
+		}
+		if schema.Type == "integer" && number != math.Trunc(number) {
+			return &violation{path: path, problem: "must be a whole number"}
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			return &violation{path: path, problem: fmt.Sprintf("must be at least %v", *schema.Minimum)}
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			return &violation{path: path, problem: fmt.Sprintf("must be at most %v", *schema.Maximum)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &violation{path: path, problem: "must be true or false"}
+		}
+	}
+
+	return nil
+}
+
+func (doc *Document) checkObject(schema *Schema, object map[string]any, path string) *violation {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return &violation{path: join(path, name), problem: "is required", code: schema.Properties[name].Error}
+		}
+	}
+
+	for name, property := range schema.Properties {
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+		if v := doc.check(property, value, join(path, name)); v != nil {
+			if v.code == "" {
+				v.code = property.Error
+			}
+			return v
+		}
+	}
+
+	if schema.AdditionalProperties != nil {
+		for name, value := range object {
+			if v := doc.check(schema.AdditionalProperties, value, join(path, name)); v != nil {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+func (doc *Document) checkArray(schema *Schema, array []any, path string) *violation {
+	if schema.MinItems != nil && len(array) < *schema.MinItems {
+		return &violation{path: path, problem: fmt.Sprintf("must have at least %d items", *schema.MinItems)}
+	}
+	if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+		return &violation{path: path, problem: fmt.Sprintf("must have at most %d items", *schema.MaxItems)}
+	}
+
+	if schema.Items != nil {
+		for i, item := range array {
+			if v := doc.check(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); v != nil {
+				return v
+			}
+		}
+	}
+
+	return nil
+}
+
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package ratings
+
+import (
+	"influence_game/actions/apierrors"
+	"influence_game/internal/accounts"
+	"influence_game/internal/ratings"
+	"strconv"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+type RatingsController struct {
+	Store    *ratings.Store
+	Accounts *accounts.Store
+}
+
+func NewRatingsController(store *ratings.Store, accountStore *accounts.Store) *RatingsController {
+	return &RatingsController{Store: store, Accounts: accountStore}
+}
+
+func (controller *RatingsController) GetRating(ctx buffalo.Context) error {
+	userID := ctx.Param("userID")
+
+	account, err := controller.Accounts.GetAccount(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get account for rating.")
+		return apierrors.Render(ctx, err)
+	}
+
+	rating, err := controller.Store.GetRating(account.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get rating.")
+		return apierrors.Render(ctx, err)
+	}
+	rating.Username = account.Username
+
+	return ctx.Render(200, renderer.JSON(rating))
+}
+
+func (controller *RatingsController) Leaderboard(ctx buffalo.Context) error {
+	limit := 0
+	if value := ctx.Param("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return apierrors.Render(ctx, ratings.ErrInvalidLimit)
+		}
+		limit = parsed
+	}
+
+	entries, err := controller.Store.Leaderboard(limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get leaderboard.")
+		return apierrors.Render(ctx, err)
+	}
+
+	for i := range entries {
+		if account, err := controller.Accounts.GetAccount(entries[i].UserID); err == nil {
+			entries[i].Username = account.Username
+		}
+	}
+
+	return ctx.Render(200, renderer.JSON(entries))
+}
@@ -0,0 +1,24 @@
+package ratings
+
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/ratings"
+)
+
+func Register(routes *openapi.Router, controller *RatingsController) {
+	routes = routes.Tagged("ratings")
+
+	routes.GET("/users/{userID}/rating", controller.GetRating, openapi.Operation{
+		ID:       "getRating",
+		Summary:  "An account's rating and how it got there.",
+		Response: ratings.RatingWithHistory{},
+	})
+	routes.GET("/leaderboard", controller.Leaderboard, openapi.Operation{
+		ID:      "getLeaderboard",
+		Summary: "The highest rated accounts.",
+		Query: []openapi.Parameter{
+			{Name: "limit", Schema: openapi.AtLeast(1, ratings.ErrInvalidLimit)},
+		},
+		Response: []ratings.LeaderboardEntry{},
+	})
+}
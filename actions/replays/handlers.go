@@ -0,0 +1,123 @@
+package replays
+
+import (
+	"errors"
+	"influence_game/actions/apierrors"
+	"influence_game/internal/archive"
+	"influence_game/internal/game"
+	"influence_game/internal/notation"
+	"strconv"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+// ReplaysController serves replays of finished games. Archive may be nil, in
+// which case only games still held in Redis can be replayed.
+type ReplaysController struct {
+	Games   *game.Store
+	Archive *archive.Store
+}
+
+func NewReplaysController(games *game.Store, archiveStore *archive.Store) *ReplaysController {
+	return &ReplaysController{Games: games, Archive: archiveStore}
+}
+
+/*
+GetReplay returns every step of a finished game, or just one with ?step=n.
+Step 0 is the deal; step n is the game after the n-th move.
+*/
+func (controller *ReplaysController) GetReplay(ctx buffalo.Context) error {
+	gameID := ctx.Param("gameID")
+
+	step := -1
+	if value := ctx.Param("step"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return apierrors.Render(ctx, game.ErrInvalidStep)
+		}
+		step = parsed
+	}
+
+	replayLog, err := controller.replayLog(gameID)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	steps, err := game.Replay(replayLog)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to replay game.")
+		return apierrors.Render(ctx, err)
+	}
+
+	if step >= len(steps) {
+		return apierrors.Render(ctx, game.ErrInvalidStep)
+	}
+	if step >= 0 {
+		steps = steps[step : step+1]
+	}
+
+	return ctx.Render(200, renderer.JSON(map[string]any{
+		"gameId":     gameID,
+		"totalSteps": len(replayLog.Commands) + 1,
+		"steps":      steps,
+	}))
+}
+
+// ExportNotation returns a finished game as a portable notation document.
+func (controller *ReplaysController) ExportNotation(ctx buffalo.Context) error {
+	replayLog, err := controller.replayLog(ctx.Param("gameID"))
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	document, err := notation.Export(replayLog)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to export game notation.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(document))
+}
+
+/*
+VerifyNotation plays an uploaded notation document through the rules engine
+and reports whether it reaches the deal and the result it claims.
+*/
+func (controller *ReplaysController) VerifyNotation(ctx buffalo.Context) error {
+	var document notation.Notation
+	if err := ctx.Bind(&document); err != nil {
+		log.Error().Err(err).Msg("Failed to bind notation document.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	verification, err := notation.Import(&document)
+	if err != nil {
+		status, body := apierrors.Body(ctx, err)
+		body["verified"] = false
+		return ctx.Render(status, renderer.JSON(body))
+	}
+
+	return ctx.Render(200, renderer.JSON(map[string]any{
+		"verified": true,
+		"moves":    verification.Moves,
+		"winner":   verification.Winner,
+	}))
+}
+
+// replayLog looks for the game in Redis first, then in the archive.
+func (controller *ReplaysController) replayLog(gameID string) (*game.ReplayLog, error) {
+	replayLog, err := controller.Games.GetReplayLog(gameID)
+	if !errors.Is(err, game.ErrGameNotFound) || controller.Archive == nil {
+		return replayLog, err
+	}
+
+	replayLog, err = controller.Archive.GetReplayLog(gameID)
+	if errors.Is(err, archive.ErrGameNotArchived) {
+		return nil, game.ErrGameNotFound
+	}
+	return replayLog, err
+}
@@ -0,0 +1,39 @@
+package replays
+
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/game"
+	"influence_game/internal/notation"
+)
+
+func Register(routes *openapi.Router, controller *ReplaysController) {
+	routes = routes.Tagged("replays")
+
+	routes.GET("/games/{gameID}/replay", controller.GetReplay, openapi.Operation{
+		ID:      "getReplay",
+		Summary: "Every step of a finished game.",
+		Query: []openapi.Parameter{
+			{Name: "step", Description: "Only this step; 0 is the deal.", Schema: openapi.AtLeast(0, game.ErrInvalidStep)},
+		},
+		Response: openapi.Object(map[string]*openapi.Schema{
+			"gameId":     openapi.String(),
+			"totalSteps": openapi.Integer(),
+			"steps":      openapi.ArrayOf(routes.Schema(game.ReplayStep{})),
+		}, "gameId", "totalSteps", "steps"),
+	})
+	routes.GET("/games/{gameID}/notation", controller.ExportNotation, openapi.Operation{
+		ID:       "exportNotation",
+		Summary:  "A finished game in portable notation.",
+		Response: notation.Notation{},
+	})
+	routes.POST("/notation/verify", controller.VerifyNotation, openapi.Operation{
+		ID:      "verifyNotation",
+		Summary: "Play a notation document through the rules and check its deal and result.",
+		Body:    notation.Notation{},
+		Response: openapi.Object(map[string]*openapi.Schema{
+			"verified": openapi.Boolean(),
+			"moves":    openapi.Integer(),
+			"winner":   openapi.String(),
+		}, "verified", "moves"),
+	})
+}
@@ -1,39 +1,159 @@
 package rooms
 
 import (
-	"errors"
+	"influence_game/internal/bots"
+	"influence_game/internal/game"
+	"influence_game/locales"
 )
 
 type CreateRoomDTO struct {
-	Nickname string `json:"nickname"`
+	Nickname string                  `json:"nickname" openapi:"required,minLength=1,error=nickname_is_required"`
+	Settings *game.RoomSettingsPatch `json:"settings,omitempty"`
 }
 
 func (dto *CreateRoomDTO) Validate() error {
 	if dto.Nickname == "" {
-		return errors.New("nickname_is_required")
+		return game.ErrNicknameRequired
 	}
-	return nil
+	return dto.Settings.Apply(game.DefaultRoomSettings()).Validate()
 }
 
 type JoinRoomDTO struct {
-	Nickname string `json:"nickname"`
+	Nickname string `json:"nickname" openapi:"required,minLength=1,error=nickname_is_required"`
 }
 
 func (dto *JoinRoomDTO) Validate() error {
 	if dto.Nickname == "" {
-		return errors.New("nickname_is_required")
+		return game.ErrNicknameRequired
+	}
+	return nil
+}
+
+type UpdateSettingsDTO struct {
+	Settings game.RoomSettingsPatch `json:"settings"`
+}
+
+type SetLanguageDTO struct {
+	Language string `json:"language"`
+}
+
+// Validate settles the language on a supported one; empty clears the preference.
+func (dto *SetLanguageDTO) Validate() error {
+	if dto.Language == "" {
+		return nil
+	}
+	language := locales.Match(dto.Language)
+	if language == "" {
+		return game.ErrUnsupportedLanguage
+	}
+	dto.Language = language
+	return nil
+}
+
+type AddBotDTO struct {
+	Strategy string `json:"strategy"`
+}
+
+func (dto *AddBotDTO) Validate() error {
+	if dto.Strategy == "" {
+		dto.Strategy = bots.DefaultStrategy
+	}
+	if _, ok := bots.Lookup(dto.Strategy); !ok {
+		return bots.ErrUnknownStrategy
+	}
+	return nil
+}
+
+type SelectInfluencesDTO struct {
+	Roles []string `json:"roles" openapi:"required,minItems=2,maxItems=2,error=two_roles_are_required"`
+}
+
+func (dto *SelectInfluencesDTO) Validate() error {
+	if len(dto.Roles) != 2 {
+		return game.ErrTwoRolesRequired
 	}
 	return nil
 }
 
 type DeclareActionDTO struct {
-	ActionName     string  `json:"actionName"`
+	ActionName     string  `json:"actionName" openapi:"required,minLength=1,error=action_is_required"`
 	TargetPlayerID *string `json:"targetId,omitempty"`
+	ClaimedRole    string  `json:"claimedRole,omitempty"`
 }
 
 func (dto *DeclareActionDTO) Validate() error {
 	if dto.ActionName == "" {
-		return errors.New("action_is_required")
+		return game.ErrActionRequired
+	}
+	return nil
+}
+
+type BlockActionDTO struct {
+	BlockingRole string `json:"blockingRole" openapi:"required,minLength=1,error=blocking_role_is_required"`
+}
+
+func (dto *BlockActionDTO) Validate() error {
+	if dto.BlockingRole == "" {
+		return game.ErrBlockingRoleRequired
+	}
+	return nil
+}
+
+type CompleteExchangeDTO struct {
+	Keep []string `json:"keep" openapi:"required,minItems=1,error=keep_is_required"`
+}
+
+func (dto *CompleteExchangeDTO) Validate() error {
+	if len(dto.Keep) == 0 {
+		return game.ErrKeepRequired
+	}
+	return nil
+}
+
+type ShowInfluenceDTO struct {
+	Role string `json:"role" openapi:"required,minLength=1,error=role_is_required"`
+}
+
+func (dto *ShowInfluenceDTO) Validate() error {
+	if dto.Role == "" {
+		return game.ErrRoleRequired
+	}
+	return nil
+}
+
+type CompleteExaminationDTO struct {
+	ForceSwap bool `json:"forceSwap"`
+}
+
+type LoseInfluenceDTO struct {
+	Role string `json:"role" openapi:"required,minLength=1,error=role_is_required"`
+}
+
+func (dto *LoseInfluenceDTO) Validate() error {
+	if dto.Role == "" {
+		return game.ErrRoleRequired
+	}
+	return nil
+}
+
+type RejoinDTO struct {
+	RejoinCode string `json:"rejoinCode" openapi:"required,minLength=1,error=rejoin_code_is_required"`
+}
+
+func (dto *RejoinDTO) Validate() error {
+	if dto.RejoinCode == "" {
+		return game.ErrRejoinCodeRequired
+	}
+	return nil
+}
+
+type QuickPlayDTO struct {
+	Nickname string `json:"nickname" openapi:"required,minLength=1,error=nickname_is_required"`
+}
+
+func (dto *QuickPlayDTO) Validate() error {
+	if dto.Nickname == "" {
+		return game.ErrNicknameRequired
 	}
 	return nil
 }
@@ -1,7 +1,8 @@
 package rooms
 
 import (
-	"errors"
+	"influence_game/actions/apierrors"
+	"influence_game/internal/accounts"
 	"influence_game/internal/game"
 	"strings"
 
@@ -13,11 +14,12 @@ import (
 var renderer = render.New(render.Options{})
 
 type RoomsController struct {
-	Store *game.Store
+	Store    *game.Store
+	Accounts *accounts.Store
 }
 
-func NewRoomsController(store *game.Store) *RoomsController {
-	return &RoomsController{Store: store}
+func NewRoomsController(store *game.Store, accountStore *accounts.Store) *RoomsController {
+	return &RoomsController{Store: store, Accounts: accountStore}
 }
 
 func (controller *RoomsController) CreateRoom(ctx buffalo.Context) error {
@@ -26,26 +28,25 @@ func (controller *RoomsController) CreateRoom(ctx buffalo.Context) error {
 
 	if err := ctx.Bind(&dto); err != nil {
 		log.Error().Err(err).Msg("Failed to bind create room request.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": "invalid_json",
-		}))
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
 	}
 
 	if err := dto.Validate(); err != nil {
 		log.Error().Err(err).Msg("Failed to validate create room request.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
+	}
+
+	userID, err := controller.getUserID(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
 	}
 
 	nickname := strings.ToLower(strings.TrimSpace(dto.Nickname))
 
-	newGamePublicInfo, err := controller.Store.CreateGameRoom(nickname)
+	newGamePublicInfo, err := controller.Store.CreateGameRoom(nickname, dto.Settings, userID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create new game room.")
-		return ctx.Render(500, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	log.Info().Msg("Created new game room successfully.")
@@ -53,22 +54,69 @@ func (controller *RoomsController) CreateRoom(ctx buffalo.Context) error {
 	return ctx.Render(200, renderer.JSON(newGamePublicInfo))
 }
 
+func (controller *RoomsController) ListPublicRooms(ctx buffalo.Context) error {
+	lobbies, err := controller.Store.ListPublicLobbies()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list public rooms.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(lobbies))
+}
+
+func (controller *RoomsController) QuickPlay(ctx buffalo.Context) error {
+	log.Info().Msg("Finding a room for quick play.")
+	var dto QuickPlayDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind quick play request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate quick play request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	userID, err := controller.getUserID(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	nickname := strings.ToLower(strings.TrimSpace(dto.Nickname))
+
+	onboardingResult, err := controller.Store.QuickPlay(nickname, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find a room for quick play.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Quick play found a room successfully.")
+
+	return ctx.Render(200, renderer.JSON(onboardingResult))
+}
+
+func (controller *RoomsController) ListRolePacks(ctx buffalo.Context) error {
+	return ctx.Render(200, renderer.JSON(game.ListRolePacks()))
+}
+
 func (controller *RoomsController) JoinRoom(ctx buffalo.Context) error {
 	log.Info().Msg("Joining game room.")
 	var dto JoinRoomDTO
 
 	if err := ctx.Bind(&dto); err != nil {
 		log.Error().Err(err).Msg("Failed to bind join room request.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": "invalid_json",
-		}))
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
 	}
 
 	if err := dto.Validate(); err != nil {
 		log.Error().Err(err).Msg("Failed to validate join room request.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
+	}
+
+	userID, err := controller.getUserID(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
 	}
 
 	joinCode := ctx.Param("joinCode")
@@ -77,12 +125,11 @@ func (controller *RoomsController) JoinRoom(ctx buffalo.Context) error {
 	onboardingResult, err := controller.Store.Join(
 		joinCode,
 		nickname,
+		userID,
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to join game room.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	log.Info().Msg("Joined game room successfully.")
@@ -95,34 +142,53 @@ func getSessionToken(ctx buffalo.Context) (string, error) {
 	const prefix = "Bearer "
 
 	if !strings.HasPrefix(authHeader, prefix) {
-		return "", errors.New("missing or invalid authorization header")
+		return "", game.ErrMissingAuthorization
 	}
 
 	token := strings.TrimPrefix(authHeader, prefix)
 	if token == "" {
-		return "", errors.New("empty bearer token")
+		return "", game.ErrMissingAuthorization
 	}
 
 	return token, nil
 }
 
+/*
+getUserID resolves the optional account token sent when creating or joining
+a room. Without one the player stays anonymous; a bad one is rejected rather
+than silently ignored.
+*/
+func (controller *RoomsController) getUserID(ctx buffalo.Context) (string, error) {
+	if ctx.Request().Header.Get("Authorization") == "" {
+		return "", nil
+	}
+
+	token, err := getSessionToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	account, err := controller.Accounts.ResolveToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	return account.ID, nil
+}
+
 func (controller *RoomsController) StartGame(ctx buffalo.Context) error {
 	log.Info().Msg("Starting game.")
 	gameID := ctx.Param("gameID")
 
 	sessionToken, err := getSessionToken(ctx)
 	if err != nil {
-		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	updatedGameState, err := controller.Store.StartGame(gameID, sessionToken)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to start game.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	log.Info().Msg("Game started successfully.")
@@ -130,6 +196,174 @@ func (controller *RoomsController) StartGame(ctx buffalo.Context) error {
 	return ctx.Render(200, renderer.JSON(updatedGameState))
 }
 
+func (controller *RoomsController) UpdateSettings(ctx buffalo.Context) error {
+	log.Info().Msg("Updating room settings.")
+	gameID := ctx.Param("gameID")
+
+	var dto UpdateSettingsDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind update settings request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	updatedGameState, err := controller.Store.UpdateRoomSettings(gameID, dto.Settings, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update room settings.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Room settings updated successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
+func (controller *RoomsController) SetLanguage(ctx buffalo.Context) error {
+	log.Info().Msg("Setting player language.")
+	gameID := ctx.Param("gameID")
+
+	var dto SetLanguageDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind set language request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate set language request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	if err := controller.Store.SetPlayerLanguage(gameID, dto.Language, sessionToken); err != nil {
+		log.Error().Err(err).Msg("Failed to set player language.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(map[string]any{
+		"language": dto.Language,
+	}))
+}
+
+func (controller *RoomsController) AddBot(ctx buffalo.Context) error {
+	log.Info().Msg("Adding bot to game room.")
+	gameID := ctx.Param("gameID")
+
+	var dto AddBotDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind add bot request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate add bot request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	updatedGameState, err := controller.Store.AddBot(gameID, dto.Strategy, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to add bot.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Bot added successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
+func (controller *RoomsController) KickPlayer(ctx buffalo.Context) error {
+	log.Info().Msg("Kicking player from game room.")
+	gameID := ctx.Param("gameID")
+	playerID := ctx.Param("playerID")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	updatedGameState, err := controller.Store.KickPlayer(gameID, playerID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to kick player.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Player kicked successfully.")
+
+	return ctx.Render(200, renderer.JSON(updatedGameState))
+}
+
+func (controller *RoomsController) RefreshSession(ctx buffalo.Context) error {
+	log.Info().Msg("Refreshing session.")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	session, err := controller.Store.RefreshSession(sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to refresh session.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(session))
+}
+
+func (controller *RoomsController) Rejoin(ctx buffalo.Context) error {
+	log.Info().Msg("Rejoining game room.")
+	var dto RejoinDTO
+
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind rejoin request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate rejoin request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	onboardingResult, err := controller.Store.Rejoin(strings.ToUpper(strings.TrimSpace(dto.RejoinCode)))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to rejoin game room.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Rejoined game room successfully.")
+
+	return ctx.Render(200, renderer.JSON(onboardingResult))
+}
+
+func (controller *RoomsController) Logout(ctx buffalo.Context) error {
+	log.Info().Msg("Logging out of session.")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	if err := controller.Store.Logout(sessionToken); err != nil {
+		log.Error().Err(err).Msg("Failed to log out of session.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(map[string]any{
+		"loggedOut": true,
+	}))
+}
+
 func (controller *RoomsController) DeclareAction(ctx buffalo.Context) error {
 	log.Info().Msg("Declaring action.")
 	gameID := ctx.Param("gameID")
@@ -137,37 +371,30 @@ func (controller *RoomsController) DeclareAction(ctx buffalo.Context) error {
 	var dto DeclareActionDTO
 	if err := ctx.Bind(&dto); err != nil {
 		log.Error().Err(err).Msg("Failed to bind declare action request.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": "invalid_json",
-		}))
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
 	}
 
 	if err := dto.Validate(); err != nil {
 		log.Error().Err(err).Msg("Failed to validate declare action request.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	sessionToken, err := getSessionToken(ctx)
 	if err != nil {
-		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 	currentGameState, err := controller.Store.DeclareAction(
 		gameID,
 		game.DeclareActionPayload{
 			ActionName:     dto.ActionName,
 			TargetPlayerID: dto.TargetPlayerID,
+			ClaimedRole:    dto.ClaimedRole,
 		},
 		sessionToken,
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to declare action.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	log.Info().Msg("Action declared successfully.")
@@ -181,62 +408,296 @@ func (controller *RoomsController) GetPlayerInfluences(ctx buffalo.Context) erro
 
 	sessionToken, err := getSessionToken(ctx)
 	if err != nil {
-		return ctx.Render(401, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	playerInfluences, err := controller.Store.GetPlayerInfluences(gameID, sessionToken)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get player influences.")
-		return ctx.Render(400, renderer.JSON(map[string]any{
-			"error": err.Error(),
-		}))
+		return apierrors.Render(ctx, err)
 	}
 
 	return ctx.Render(200, renderer.JSON(playerInfluences))
 }
 
-// func (controller *RoomsController) BlockAction(ctx buffalo.Context) error {
-// 	log.Info().Msg("Blocking action.")
-// 	gameID := ctx.Param("gameID")
-// 	actionID := ctx.Param("actionID")
-
-// 	var dto BlockActionDTO
-// 	if err := ctx.Bind(&dto); err != nil {
-// 		log.Error().Err(err).Msg("Failed to bind block action request.")
-// 		return ctx.Render(400, renderer.JSON(map[string]any{
-// 			"error": "invalid_json",
-// 		}))
-// 	}
-
-// 	if err := dto.Validate(); err != nil {
-// 		log.Error().Err(err).Msg("Failed to validate block action request.")
-// 		return ctx.Render(400, renderer.JSON(map[string]any{
-// 			"error": err.Error(),
-// 		}))
-// 	}
-
-// 	sessionToken, err := getSessionToken(ctx)
-// 	if err != nil {
-// 		return ctx.Render(401, renderer.JSON(map[string]any{
-// 			"error": err.Error(),
-// 		}))
-// 	}
-// 	currentGameState, err := controller.Store.BlockAction(
-// 		gameID,
-// 		actionID,
-// 		dto.BlockingRole,
-// 		sessionToken,
-// 	)
-// 	if err != nil {
-// 		log.Error().Err(err).Msg("Failed to block action.")
-// 		return ctx.Render(400, renderer.JSON(map[string]any{
-// 			"error": err.Error(),
-// 		}))
-// 	}
-
-// 	log.Info().Msg("Action blocked successfully.")
-
-// 	return ctx.Render(200, renderer.JSON(currentGameState))
-// }
+func (controller *RoomsController) GetPlayerDraft(ctx buffalo.Context) error {
+	log.Info().Msg("Getting player draft.")
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	dealt, err := controller.Store.GetPlayerDraft(gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get player draft.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(dealt))
+}
+
+func (controller *RoomsController) SelectInfluences(ctx buffalo.Context) error {
+	log.Info().Msg("Selecting drafted influences.")
+	gameID := ctx.Param("gameID")
+
+	var dto SelectInfluencesDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind select influences request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate select influences request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.SelectInfluences(gameID, dto.Roles, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to select influences.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Influences selected successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) BlockAction(ctx buffalo.Context) error {
+	log.Info().Msg("Blocking action.")
+	gameID := ctx.Param("gameID")
+	actionID := ctx.Param("actionID")
+
+	var dto BlockActionDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind block action request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate block action request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+	currentGameState, err := controller.Store.BlockAction(
+		gameID,
+		actionID,
+		dto.BlockingRole,
+		sessionToken,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to block action.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Action blocked successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) ChallengeAction(ctx buffalo.Context) error {
+	log.Info().Msg("Challenging action.")
+	gameID := ctx.Param("gameID")
+	actionID := ctx.Param("actionID")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.ChallengeAction(gameID, actionID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to challenge action.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Action challenged successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) PassAction(ctx buffalo.Context) error {
+	log.Info().Msg("Passing on action.")
+	gameID := ctx.Param("gameID")
+	actionID := ctx.Param("actionID")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.PassAction(gameID, actionID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to pass on action.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) LoseInfluence(ctx buffalo.Context) error {
+	log.Info().Msg("Losing influence.")
+	gameID := ctx.Param("gameID")
+
+	var dto LoseInfluenceDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind lose influence request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate lose influence request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.LoseInfluence(gameID, dto.Role, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to lose influence.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Influence lost successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) GetPendingExchange(ctx buffalo.Context) error {
+	log.Info().Msg("Getting pending exchange.")
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	exchange, err := controller.Store.GetPendingExchange(gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get pending exchange.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(exchange))
+}
+
+func (controller *RoomsController) CompleteExchange(ctx buffalo.Context) error {
+	log.Info().Msg("Completing exchange.")
+	gameID := ctx.Param("gameID")
+
+	var dto CompleteExchangeDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind complete exchange request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate complete exchange request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.CompleteExchange(gameID, dto.Keep, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to complete exchange.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Exchange completed successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) ShowInfluence(ctx buffalo.Context) error {
+	log.Info().Msg("Showing influence to examiner.")
+	gameID := ctx.Param("gameID")
+
+	var dto ShowInfluenceDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind show influence request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Error().Err(err).Msg("Failed to validate show influence request.")
+		return apierrors.Render(ctx, err)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.ShowInfluence(gameID, dto.Role, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to show influence.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Influence shown successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) CompleteExamination(ctx buffalo.Context) error {
+	log.Info().Msg("Completing examination.")
+	gameID := ctx.Param("gameID")
+
+	var dto CompleteExaminationDTO
+	if err := ctx.Bind(&dto); err != nil {
+		log.Error().Err(err).Msg("Failed to bind complete examination request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	currentGameState, err := controller.Store.CompleteExamination(gameID, dto.ForceSwap, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to complete examination.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Examination completed successfully.")
+
+	return ctx.Render(200, renderer.JSON(currentGameState))
+}
+
+func (controller *RoomsController) Rematch(ctx buffalo.Context) error {
+	log.Info().Msg("Creating rematch.")
+	gameID := ctx.Param("gameID")
+
+	sessionToken, err := getSessionToken(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	onboardingResult, err := controller.Store.Rematch(gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create rematch.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Rematch created successfully.")
+
+	return ctx.Render(200, renderer.JSON(onboardingResult))
+}
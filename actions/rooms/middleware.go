@@ -0,0 +1,88 @@
+package rooms
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/buffalo"
+)
+
+const (
+	SessionTokenHeader     = "X-Session-Token"
+	SessionExpiresAtHeader = "X-Session-Expires-At"
+)
+
+/*
+SlideSession renews the session of active players: when the bearer token is
+a session past half of its lifetime, the first successful response after
+that carries a fresh one in the X-Session-Token header. Any other bearer
+token is left alone.
+
+The token is only minted once the handler has succeeded, as its status is
+written, so failed requests never hand out a session. Routes that end or
+replace the session themselves skip this middleware; see app.go.
+*/
+func (controller *RoomsController) SlideSession(next buffalo.Handler) buffalo.Handler {
+	return func(ctx buffalo.Context) error {
+		authHeader := ctx.Request().Header.Get("Authorization")
+
+		token, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || token == "" {
+			return next(ctx)
+		}
+
+		if response, ok := ctx.Response().(*buffalo.Response); ok {
+			response.ResponseWriter = &slidingWriter{
+				ResponseWriter: response.ResponseWriter,
+				slide: func(header http.Header) {
+					if renewed, ok := controller.Store.SlideSession(token); ok {
+						header.Set(SessionTokenHeader, renewed.Token)
+						header.Set(SessionExpiresAtHeader, renewed.ExpiresAt.Format(time.RFC3339))
+					}
+				},
+			}
+		}
+
+		return next(ctx)
+	}
+}
+
+// slidingWriter renews the session right before a successful status goes out.
+type slidingWriter struct {
+	http.ResponseWriter
+	slide       func(http.Header)
+	wroteHeader bool
+}
+
+func (writer *slidingWriter) WriteHeader(status int) {
+	if !writer.wroteHeader {
+		writer.wroteHeader = true
+		if status < http.StatusBadRequest {
+			writer.slide(writer.Header())
+		}
+	}
+	writer.ResponseWriter.WriteHeader(status)
+}
+
+func (writer *slidingWriter) Write(data []byte) (int, error) {
+	if !writer.wroteHeader {
+		writer.WriteHeader(http.StatusOK)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+// Hijack and Flush pass through, so sockets and streams behind this still work.
+func (writer *slidingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(writer.ResponseWriter).Hijack()
+}
+
+func (writer *slidingWriter) Flush() {
+	_ = http.NewResponseController(writer.ResponseWriter).Flush()
+}
+
+func (writer *slidingWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
@@ -1,13 +1,181 @@
 package rooms
 
-import "github.com/gobuffalo/buffalo"
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/game"
+)
 
-func Register(app *buffalo.App, controller *RoomsController) {
-	app.POST("/rooms", controller.CreateRoom)
-	app.POST("/rooms/{joinCode}/join", controller.JoinRoom)
-	app.POST("/rooms/{gameID}/start", controller.StartGame)
+func Register(routes *openapi.Router, controller *RoomsController) {
+	routes = routes.Tagged("rooms")
+
+	routes.POST("/rooms", controller.CreateRoom, openapi.Operation{
+		ID:       "createRoom",
+		Summary:  "Open a room and take its first seat as admin.",
+		Auth:     openapi.AuthOptionalAccount,
+		Body:     CreateRoomDTO{},
+		Response: game.OnboardingResult{},
+	})
+	routes.GET("/rooms", controller.ListPublicRooms, openapi.Operation{
+		ID:       "listPublicRooms",
+		Summary:  "Public rooms that are waiting for players.",
+		Response: []game.PublicLobby{},
+	})
+	routes.POST("/matchmaking/quick", controller.QuickPlay, openapi.Operation{
+		ID:       "quickPlay",
+		Summary:  "Take a seat in the fullest public room, or open one.",
+		Auth:     openapi.AuthOptionalAccount,
+		Body:     QuickPlayDTO{},
+		Response: game.OnboardingResult{},
+	})
+	routes.GET("/role-packs", controller.ListRolePacks, openapi.Operation{
+		ID:       "listRolePacks",
+		Summary:  "The role packs a room can be played with.",
+		Response: []game.RolePack{},
+	})
+	routes.POST("/rooms/{joinCode}/join", controller.JoinRoom, openapi.Operation{
+		ID:       "joinRoom",
+		Summary:  "Take a seat in a room.",
+		Auth:     openapi.AuthOptionalAccount,
+		Body:     JoinRoomDTO{},
+		Response: game.OnboardingResult{},
+	})
+	routes.PATCH("/rooms/{gameID}/settings", controller.UpdateSettings, openapi.Operation{
+		ID:       "updateSettings",
+		Summary:  "Change the room settings before the game starts.",
+		Auth:     openapi.AuthSession,
+		Body:     UpdateSettingsDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/rooms/{gameID}/bots", controller.AddBot, openapi.Operation{
+		ID:       "addBot",
+		Summary:  "Seat a bot in the room.",
+		Auth:     openapi.AuthSession,
+		Body:     AddBotDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/rooms/{gameID}/players/{playerID}/kick", controller.KickPlayer, openapi.Operation{
+		ID:       "kickPlayer",
+		Summary:  "Remove a player from the lobby.",
+		Auth:     openapi.AuthSession,
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/rooms/{gameID}/start", controller.StartGame, openapi.Operation{
+		ID:       "startGame",
+		Summary:  "Deal the cards.",
+		Auth:     openapi.AuthSession,
+		Response: game.PublicGameState{},
+	})
+
+	routes.POST("/sessions/refresh", controller.RefreshSession, openapi.Operation{
+		ID:       "refreshSession",
+		Summary:  "Swap the session token for a fresh one.",
+		Auth:     openapi.AuthSession,
+		Response: game.SessionResult{},
+	})
+	routes.POST("/sessions/logout", controller.Logout, openapi.Operation{
+		ID:       "logout",
+		Summary:  "End the session: every token of the seat is revoked.",
+		Auth:     openapi.AuthSession,
+		Response: openapi.Object(map[string]*openapi.Schema{"loggedOut": openapi.Boolean()}, "loggedOut"),
+	})
+	routes.POST("/sessions/rejoin", controller.Rejoin, openapi.Operation{
+		ID:       "rejoin",
+		Summary:  "Take a seat back with its rejoin code. The code is used up; the response carries its replacement.",
+		Body:     RejoinDTO{},
+		Response: game.OnboardingResult{},
+	})
 
 	// In-game routes
-	app.GET("/games/{gameID}/player/influences", controller.GetPlayerInfluences)
-	app.POST("/games/{gameID}/actions/declare", controller.DeclareAction)
+	routes.GET("/games/{gameID}/player/influences", controller.GetPlayerInfluences, openapi.Operation{
+		ID:       "getPlayerInfluences",
+		Summary:  "Your cards.",
+		Auth:     openapi.AuthSession,
+		Response: []game.Influence{},
+	})
+	routes.GET("/games/{gameID}/player/draft", controller.GetPlayerDraft, openapi.Operation{
+		ID:       "getPlayerDraft",
+		Summary:  "The cards dealt to you in the two-player draft.",
+		Auth:     openapi.AuthSession,
+		Response: []game.Influence{},
+	})
+	routes.PATCH("/games/{gameID}/player/language", controller.SetLanguage, openapi.Operation{
+		ID:       "setLanguage",
+		Summary:  "The language for your errors and event messages; empty follows Accept-Language.",
+		Auth:     openapi.AuthSession,
+		Body:     SetLanguageDTO{},
+		Response: openapi.Object(map[string]*openapi.Schema{"language": openapi.String()}, "language"),
+	})
+	routes.POST("/games/{gameID}/player/influences/select", controller.SelectInfluences, openapi.Operation{
+		ID:       "selectInfluences",
+		Summary:  "Keep two of the cards dealt in the draft.",
+		Auth:     openapi.AuthSession,
+		Body:     SelectInfluencesDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/actions/declare", controller.DeclareAction, openapi.Operation{
+		ID:       "declareAction",
+		Summary:  "Declare an action on your turn.",
+		Auth:     openapi.AuthSession,
+		Body:     DeclareActionDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/actions/{actionID}/block", controller.BlockAction, openapi.Operation{
+		ID:       "blockAction",
+		Summary:  "Block the pending action, claiming a role.",
+		Auth:     openapi.AuthSession,
+		Body:     BlockActionDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/actions/{actionID}/challenge", controller.ChallengeAction, openapi.Operation{
+		ID:       "challengeAction",
+		Summary:  "Challenge the claim behind the pending action or block.",
+		Auth:     openapi.AuthSession,
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/actions/{actionID}/pass", controller.PassAction, openapi.Operation{
+		ID:       "passAction",
+		Summary:  "Let the pending action or block stand.",
+		Auth:     openapi.AuthSession,
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/player/influences/lose", controller.LoseInfluence, openapi.Operation{
+		ID:       "loseInfluence",
+		Summary:  "Reveal the card you give up.",
+		Auth:     openapi.AuthSession,
+		Body:     LoseInfluenceDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.GET("/games/{gameID}/player/exchange", controller.GetPendingExchange, openapi.Operation{
+		ID:       "getPendingExchange",
+		Summary:  "The cards you drew for your exchange.",
+		Auth:     openapi.AuthSession,
+		Response: game.PendingExchange{},
+	})
+	routes.POST("/games/{gameID}/player/exchange", controller.CompleteExchange, openapi.Operation{
+		ID:       "completeExchange",
+		Summary:  "Choose the cards you keep from your exchange.",
+		Auth:     openapi.AuthSession,
+		Body:     CompleteExchangeDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/player/show", controller.ShowInfluence, openapi.Operation{
+		ID:       "showInfluence",
+		Summary:  "Choose which of your cards the examiner looks at.",
+		Auth:     openapi.AuthSession,
+		Body:     ShowInfluenceDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/player/examine", controller.CompleteExamination, openapi.Operation{
+		ID:       "completeExamination",
+		Summary:  "Make the examined player swap their card, or let them keep it.",
+		Auth:     openapi.AuthSession,
+		Body:     CompleteExaminationDTO{},
+		Response: game.PublicGameState{},
+	})
+	routes.POST("/games/{gameID}/rematch", controller.Rematch, openapi.Operation{
+		ID:       "rematch",
+		Summary:  "Deal a finished game again with the same players.",
+		Auth:     openapi.AuthSession,
+		Response: game.OnboardingResult{},
+	})
 }
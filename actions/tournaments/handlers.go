@@ -0,0 +1,138 @@
+package tournaments
+
+import (
+	"influence_game/actions/apierrors"
+	"influence_game/internal/accounts"
+	"influence_game/internal/game"
+	"influence_game/internal/tournaments"
+	"strings"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/rs/zerolog/log"
+)
+
+var renderer = render.New(render.Options{})
+
+type TournamentsController struct {
+	Tournaments *tournaments.Store
+	Accounts    *accounts.Store
+}
+
+func NewTournamentsController(tournamentStore *tournaments.Store, accountStore *accounts.Store) *TournamentsController {
+	return &TournamentsController{Tournaments: tournamentStore, Accounts: accountStore}
+}
+
+func (controller *TournamentsController) Create(ctx buffalo.Context) error {
+	log.Info().Msg("Creating tournament.")
+
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	var options tournaments.Options
+	if err := ctx.Bind(&options); err != nil {
+		log.Error().Err(err).Msg("Failed to bind create tournament request.")
+		return apierrors.Render(ctx, game.ErrInvalidJSON)
+	}
+
+	tournament, err := controller.Tournaments.Create(options, account.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create tournament.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Tournament created successfully.")
+
+	return ctx.Render(201, renderer.JSON(tournament))
+}
+
+func (controller *TournamentsController) List(ctx buffalo.Context) error {
+	list, err := controller.Tournaments.List()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list tournaments.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(map[string]any{
+		"tournaments": list,
+	}))
+}
+
+func (controller *TournamentsController) Get(ctx buffalo.Context) error {
+	tournament, err := controller.Tournaments.Get(ctx.Param("tournamentID"))
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(tournament))
+}
+
+func (controller *TournamentsController) RegisterParticipant(ctx buffalo.Context) error {
+	log.Info().Msg("Registering for tournament.")
+
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	tournament, err := controller.Tournaments.Register(ctx.Param("tournamentID"), account.ID, account.Username)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to register for tournament.")
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(tournament))
+}
+
+func (controller *TournamentsController) Start(ctx buffalo.Context) error {
+	log.Info().Msg("Starting tournament.")
+
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	tournament, err := controller.Tournaments.Start(ctx.Param("tournamentID"), account.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to start tournament.")
+		return apierrors.Render(ctx, err)
+	}
+
+	log.Info().Msg("Tournament started successfully.")
+
+	return ctx.Render(200, renderer.JSON(tournament))
+}
+
+// Seat hands participants the session for their current table.
+func (controller *TournamentsController) Seat(ctx buffalo.Context) error {
+	account, err := controller.getAccount(ctx)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	seat, err := controller.Tournaments.Seat(ctx.Param("tournamentID"), account.ID)
+	if err != nil {
+		return apierrors.Render(ctx, err)
+	}
+
+	return ctx.Render(200, renderer.JSON(seat))
+}
+
+// Tournaments are played with accounts, so results follow the user.
+func (controller *TournamentsController) getAccount(ctx buffalo.Context) (*accounts.Account, error) {
+	authHeader := ctx.Request().Header.Get("Authorization")
+	const prefix = "Bearer "
+
+	if !strings.HasPrefix(authHeader, prefix) {
+		return nil, game.ErrMissingAuthorization
+	}
+
+	token := strings.TrimPrefix(authHeader, prefix)
+	if token == "" {
+		return nil, game.ErrMissingAuthorization
+	}
+
+	return controller.Accounts.ResolveToken(token)
+}
@@ -0,0 +1,48 @@
+package tournaments
+
+import (
+	"influence_game/actions/openapi"
+	"influence_game/internal/tournaments"
+	"net/http"
+)
+
+func Register(routes *openapi.Router, controller *TournamentsController) {
+	routes = routes.Tagged("tournaments")
+
+	routes.POST("/tournaments", controller.Create, openapi.Operation{
+		ID:       "createTournament",
+		Summary:  "Organize a tournament.",
+		Auth:     openapi.AuthAccount,
+		Body:     tournaments.Options{},
+		Response: tournaments.Details{},
+		Status:   http.StatusCreated,
+	})
+	routes.GET("/tournaments", controller.List, openapi.Operation{
+		ID:       "listTournaments",
+		Summary:  "Every tournament.",
+		Response: openapi.Object(map[string]*openapi.Schema{"tournaments": routes.Schema([]tournaments.Details{})}, "tournaments"),
+	})
+	routes.GET("/tournaments/{tournamentID}", controller.Get, openapi.Operation{
+		ID:       "getTournament",
+		Summary:  "A tournament, its standings and its tables.",
+		Response: tournaments.Details{},
+	})
+	routes.POST("/tournaments/{tournamentID}/register", controller.RegisterParticipant, openapi.Operation{
+		ID:       "registerForTournament",
+		Summary:  "Take part in a tournament that has not started.",
+		Auth:     openapi.AuthAccount,
+		Response: tournaments.Details{},
+	})
+	routes.POST("/tournaments/{tournamentID}/start", controller.Start, openapi.Operation{
+		ID:       "startTournament",
+		Summary:  "Close registration and seat the first round.",
+		Auth:     openapi.AuthAccount,
+		Response: tournaments.Details{},
+	})
+	routes.GET("/tournaments/{tournamentID}/seat", controller.Seat, openapi.Operation{
+		ID:       "getTournamentSeat",
+		Summary:  "The session for the account's current table.",
+		Auth:     openapi.AuthAccount,
+		Response: tournaments.Seat{},
+	})
+}
@@ -0,0 +1,111 @@
+package actions
+
+import (
+	"bytes"
+	"influence_game/internal/versions"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/gorilla/websocket"
+)
+
+/*
+apiVersion serves the routes of a version group in that version's names.
+Handlers keep writing and reading v1 names: responses are upgraded on the way
+out, and query parameters are read under the version's name.
+*/
+func apiVersion(version versions.Version) buffalo.MiddlewareFunc {
+	return func(next buffalo.Handler) buffalo.Handler {
+		return func(ctx buffalo.Context) error {
+			ctx.Set("api_version", version)
+			if version == versions.V1 {
+				return next(ctx)
+			}
+			return next(&versionedContext{Context: ctx, version: version})
+		}
+	}
+}
+
+type versionedContext struct {
+	buffalo.Context
+	version versions.Version
+}
+
+func (ctx *versionedContext) Render(status int, renderer render.Renderer) error {
+	if renderer == nil {
+		return ctx.Context.Render(status, nil)
+	}
+	return ctx.Context.Render(status, upgradedRenderer{Renderer: renderer, version: ctx.version})
+}
+
+// Param reads a query parameter under the name the version gives it.
+func (ctx *versionedContext) Param(key string) string {
+	if name := ctx.version.Key(key); name != key {
+		if values, ok := ctx.Request().URL.Query()[name]; ok {
+			return values[0]
+		}
+	}
+	return ctx.Context.Param(key)
+}
+
+type upgradedRenderer struct {
+	render.Renderer
+	version versions.Version
+}
+
+func (renderer upgradedRenderer) Render(w io.Writer, data render.Data) error {
+	var written bytes.Buffer
+	if err := renderer.Renderer.Render(&written, data); err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(renderer.ContentType(), "application/json") {
+		_, err := w.Write(written.Bytes())
+		return err
+	}
+	_, err := w.Write(renderer.version.Upgrade(written.Bytes()))
+	return err
+}
+
+// Sockets offer their version as a subprotocol, newest first.
+var socketProtocols = func() []string {
+	protocols := make([]string, 0, len(versions.All))
+	for i := len(versions.All) - 1; i >= 0; i-- {
+		protocols = append(protocols, "influence."+versions.All[i].String())
+	}
+	return protocols
+}()
+
+/*
+socketVersion picks the version a socket speaks: the influence.<version>
+subprotocol it negotiated, then ?version=, since not every client can offer
+subprotocols, then the version in the path. Sockets outside a version group
+speak v1.
+*/
+func socketVersion(ctx buffalo.Context, conn *websocket.Conn) versions.Version {
+	if protocol := conn.Subprotocol(); protocol != "" {
+		if version, ok := versions.Parse(strings.TrimPrefix(protocol, "influence.")); ok {
+			return version
+		}
+	}
+	if version, ok := versions.Parse(ctx.Request().URL.Query().Get("version")); ok {
+		return version
+	}
+	if version, ok := ctx.Value("api_version").(versions.Version); ok {
+		return version
+	}
+	return versions.V1
+}
+
+// validSocketVersion rejects a ?version= the API does not serve before the socket opens.
+func validSocketVersion(r *http.Request) bool {
+	value := r.URL.Query().Get("version")
+	if value == "" {
+		return true
+	}
+	_, ok := versions.Parse(value)
+	return ok
+}
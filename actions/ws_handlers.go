@@ -1,21 +1,20 @@
 package actions
 
 import (
-	"context"
-	"encoding/json"
-	"errors"
 	"net/http"
 
+	"influence_game/actions/apierrors"
 	"influence_game/internal/game"
+	internalmatchmaking "influence_game/internal/matchmaking"
 	"influence_game/internal/realtime"
 
 	"github.com/gobuffalo/buffalo"
 	"github.com/gorilla/websocket"
-	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
 var wsUpgrader = websocket.Upgrader{
+	Subprotocols: socketProtocols,
 	CheckOrigin: func(r *http.Request) bool {
 		// depois dá pra restringir por domínio
 		return true
@@ -27,37 +26,70 @@ func GameWebSocketHandler(c buffalo.Context) error {
 
 	gameID := c.Param("gameID")
 	if gameID == "" {
-		return c.Error(http.StatusBadRequest, errors.New("missing gameID"))
+		return apierrors.Render(c, game.ErrGameNotFound)
 	}
 
 	token := r.URL.Query().Get("token")
 	if token == "" {
 		log.Error().Msg("Missing token in query params.")
-		return c.Error(http.StatusUnauthorized, errors.New("missing token"))
+		return apierrors.Render(c, game.ErrMissingSocketToken)
 	}
 
-	ctx := context.Background()
-	sessionKey := "session:" + token
+	session, err := gameStore.ResolveSession(gameID, token)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid session for WebSocket.")
+		return apierrors.Render(c, err)
+	}
 
-	sessionJSON, err := gameStore.GetRedis().Get(ctx, sessionKey).Bytes()
-	if err == redis.Nil {
-		log.Error().Msg("Session not found for token: " + token)
-		return c.Error(http.StatusUnauthorized, errors.New("session not found"))
+	if !validSocketVersion(r) {
+		return apierrors.Render(c, game.ErrUnsupportedVersion)
 	}
+
+	conn, err := wsUpgrader.Upgrade(c.Response(), r, nil)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get session from Redis.")
-		return c.Error(http.StatusUnauthorized, errors.New("failed to get session from Redis"))
+		return err
+	}
+
+	client := &realtime.Client{
+		Conn:     conn,
+		GameID:   gameID,
+		PlayerID: session.PlayerID,
+		Language: socketLanguage(r, token),
+		Version:  socketVersion(c, conn),
+	}
+
+	realtime.Manager.AddClient(client)
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			realtime.Manager.RemoveClient(client)
+			_ = conn.Close()
+			return nil
+		}
+	}
+}
+
+/*
+MatchmakingWebSocketHandler streams queue status and the match_found event to
+a queued player. It authenticates with the account token, not a game session.
+*/
+func MatchmakingWebSocketHandler(c buffalo.Context) error {
+	r := c.Request()
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		log.Error().Msg("Missing token in query params.")
+		return apierrors.Render(c, game.ErrMissingSocketToken)
 	}
 
-	var session game.PlayerSession
-	if err := json.Unmarshal(sessionJSON, &session); err != nil {
-		log.Error().Err(err).Msg("Failed to unmarshal session.")
-		return c.Error(http.StatusUnauthorized, errors.New("failed to decode session"))
+	account, err := accountStore.ResolveToken(token)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid account session for WebSocket.")
+		return apierrors.Render(c, err)
 	}
 
-	if session.GameID != gameID {
-		log.Error().Msg("Invalid session game ID.")
-		return c.Error(http.StatusUnauthorized, errors.New("invalid game session"))
+	if !validSocketVersion(r) {
+		return apierrors.Render(c, game.ErrUnsupportedVersion)
 	}
 
 	conn, err := wsUpgrader.Upgrade(c.Response(), r, nil)
@@ -67,8 +99,9 @@ func GameWebSocketHandler(c buffalo.Context) error {
 
 	client := &realtime.Client{
 		Conn:     conn,
-		GameID:   gameID,
-		PlayerID: session.PlayerID,
+		GameID:   internalmatchmaking.RealtimeChannel,
+		PlayerID: account.ID,
+		Version:  socketVersion(c, conn),
 	}
 
 	realtime.Manager.AddClient(client)
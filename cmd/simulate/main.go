@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"influence_game/internal/bots"
+	"influence_game/internal/game"
+)
+
+// simulate plays bot-only games on the rules directly and prints win rates,
+// e.g. go run ./cmd/simulate -games 5000 -players 4 -strategies heuristic,random
+func main() {
+	games := flag.Int("games", 1000, "number of games to play")
+	players := flag.Int("players", 4, "players per game")
+	strategies := flag.String("strategies", bots.DefaultStrategy, "comma-separated strategies, assigned to players in order: "+strings.Join(bots.Names(), ", "))
+	rotate := flag.Bool("rotate", true, "shift strategies one seat along every game")
+	variants := flag.String("variants", "", "comma-separated variants to enable")
+	rolePack := flag.String("role-pack", game.RolePackBase, "role pack to play with")
+	rolePacksPath := flag.String("role-packs", "", "optional TOML or JSON file with custom role packs")
+	copies := flag.Int("copies", 0, "copies per role, 0 picks the default for the player count")
+	startingCoins := flag.Int("coins", game.DefaultStartingCoins, "starting coins")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed")
+	maxMoves := flag.Int("max-moves", bots.DefaultMaxMoves, "moves before a game is given up as unfinished")
+	format := flag.String("format", "json", "output format: json or csv")
+	flag.Parse()
+
+	if *rolePacksPath != "" {
+		if err := game.LoadRolePacks(*rolePacksPath); err != nil {
+			fail(err)
+		}
+	}
+
+	settings := game.DefaultRoomSettings()
+	settings.MaxPlayers = *players
+	settings.RolePack = *rolePack
+	settings.CopiesPerRole = *copies
+	settings.StartingCoins = *startingCoins
+	settings.Variants = splitList(*variants)
+
+	report, err := bots.Simulate(bots.SimulationConfig{
+		Games:      *games,
+		Players:    *players,
+		Strategies: splitList(*strategies),
+		Settings:   settings,
+		Seed:       *seed,
+		MaxMoves:   *maxMoves,
+		Rotate:     *rotate,
+	})
+	if err != nil {
+		fail(err)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	case "csv":
+		err = writeCSV(os.Stdout, report)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func writeCSV(out io.Writer, report bots.SimulationReport) error {
+	writer := csv.NewWriter(out)
+
+	rows := [][]string{
+		{"group", "key", "games", "wins", "win_rate"},
+	}
+	for _, seat := range report.Seats {
+		rows = append(rows, []string{
+			"seat", strconv.Itoa(seat.Seat), strconv.Itoa(seat.Games), strconv.Itoa(seat.Wins), formatFloat(seat.WinRate),
+		})
+	}
+	for _, strategy := range report.Strategies {
+		rows = append(rows, []string{
+			"strategy", strategy.Strategy, strconv.Itoa(strategy.Games), strconv.Itoa(strategy.Wins), formatFloat(strategy.WinRate),
+		})
+	}
+	rows = append(rows,
+		[]string{"summary", "average_turns", strconv.Itoa(report.Games), "", formatFloat(report.AverageTurns)},
+		[]string{"summary", "average_moves", strconv.Itoa(report.Games), "", formatFloat(report.AverageMoves)},
+		[]string{"summary", "unfinished", strconv.Itoa(report.Games), strconv.Itoa(report.Unfinished), ""},
+	)
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "simulate:", err)
+	os.Exit(1)
+}
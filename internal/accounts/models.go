@@ -0,0 +1,55 @@
+package accounts
+
+import (
+	"influence_game/internal/game"
+	"net/http"
+	"time"
+)
+
+const (
+	AccountSessionDuration = 30 * 24 * time.Hour
+	MinPasswordLength      = 8
+	MinUsernameLength      = 3
+	MaxUsernameLength      = 20
+)
+
+var (
+	ErrInvalidUsername       = game.NewError("invalid_username", http.StatusBadRequest)
+	ErrPasswordTooShort      = game.NewError("password_too_short", http.StatusBadRequest)
+	ErrUsernameTaken         = game.NewError("username_taken", http.StatusConflict)
+	ErrInvalidCredentials    = game.NewError("invalid_credentials", http.StatusUnauthorized)
+	ErrInvalidAccountSession = game.NewError("invalid_account_session", http.StatusUnauthorized)
+	ErrAccountNotFound       = game.NewError("account_not_found", http.StatusNotFound)
+	ErrUsernameRequired      = game.NewError("username_is_required", http.StatusBadRequest)
+	ErrPasswordRequired      = game.NewError("password_is_required", http.StatusBadRequest)
+)
+
+/*
+Account is a stable identity that outlives games. Accounts are optional:
+anonymous players keep joining with just a nickname.
+*/
+type Account struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"passwordHash"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type AccountInfo struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AuthResult struct {
+	Account AccountInfo `json:"account"`
+	Token   string      `json:"token"`
+}
+
+func (account *Account) Info() AccountInfo {
+	return AccountInfo{
+		ID:        account.ID,
+		Username:  account.Username,
+		CreatedAt: account.CreatedAt,
+	}
+}
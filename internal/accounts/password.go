@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	passwordScheme     = "pbkdf2-sha256"
+	passwordIterations = 600_000
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+)
+
+// hashPassword returns "pbkdf2-sha256$iterations$salt$key", base64 encoded.
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"%s$%d$%s$%s",
+		passwordScheme,
+		passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func checkPassword(password string, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
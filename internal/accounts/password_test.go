@@ -0,0 +1,33 @@
+package accounts
+
+import "testing"
+
+func TestPasswordHashRoundTrip(t *testing.T) {
+	encoded, err := hashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !checkPassword("correct horse", encoded) {
+		t.Fatal("expected the password to match its hash")
+	}
+	if checkPassword("wrong horse", encoded) {
+		t.Fatal("expected a different password to be rejected")
+	}
+
+	other, _ := hashPassword("correct horse")
+	if other == encoded {
+		t.Fatal("expected a fresh salt for every hash")
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	for _, username := range []string{"ab", "has space", "UPPER", "thisusernameiswaytoolong"} {
+		if ValidateUsername(username) == nil {
+			t.Fatalf("expected %q to be rejected", username)
+		}
+	}
+	if err := ValidateUsername("player_one"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+Store keeps accounts in Redis under their own keys, apart from the game keys:
+
+	account:{id}                 the account
+	account:username:{username}  account ID, reserved with SETNX
+	auth:{token}                 account ID of a login session
+*/
+type Store struct {
+	redis *redis.Client
+}
+
+func NewStore(redisClient *redis.Client) *Store {
+	return &Store{
+		redis: redisClient,
+	}
+}
+
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+func ValidateUsername(username string) error {
+	if len(username) < MinUsernameLength || len(username) > MaxUsernameLength {
+		return ErrInvalidUsername
+	}
+	for _, r := range username {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return ErrInvalidUsername
+		}
+	}
+	return nil
+}
+
+func (store *Store) Register(username string, password string) (*AuthResult, error) {
+	ctx := context.Background()
+
+	username = NormalizeUsername(username)
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	if len(password) < MinPasswordLength {
+		return nil, ErrPasswordTooShort
+	}
+
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to hash password.")
+		return nil, err
+	}
+
+	account := &Account{
+		ID:           uuid.NewString(),
+		Username:     username,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	reserved, err := store.redis.SetNX(ctx, "account:username:"+username, account.ID, 0).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reserve username.")
+		return nil, err
+	}
+	if !reserved {
+		return nil, ErrUsernameTaken
+	}
+
+	if err := store.saveAccount(ctx, account); err != nil {
+		_ = store.redis.Del(ctx, "account:username:"+username).Err()
+		return nil, err
+	}
+
+	return store.createAuthSession(ctx, account)
+}
+
+func (store *Store) Login(username string, password string) (*AuthResult, error) {
+	ctx := context.Background()
+
+	accountID, err := store.redis.Get(ctx, "account:username:"+NormalizeUsername(username)).Result()
+	if err == redis.Nil {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to look up username.")
+		return nil, err
+	}
+
+	account, err := store.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkPassword(password, account.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+
+	return store.createAuthSession(ctx, account)
+}
+
+// ResolveToken returns the account a login token belongs to.
+func (store *Store) ResolveToken(token string) (*Account, error) {
+	if token == "" {
+		return nil, ErrInvalidAccountSession
+	}
+
+	ctx := context.Background()
+
+	accountID, err := store.redis.Get(ctx, "auth:"+token).Result()
+	if err == redis.Nil {
+		return nil, ErrInvalidAccountSession
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get account session from Redis.")
+		return nil, err
+	}
+
+	return store.GetAccount(accountID)
+}
+
+func (store *Store) GetAccount(accountID string) (*Account, error) {
+	ctx := context.Background()
+
+	data, err := store.redis.Get(ctx, "account:"+accountID).Bytes()
+	if err == redis.Nil {
+		return nil, ErrAccountNotFound
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get account from Redis.")
+		return nil, err
+	}
+
+	var account Account
+	if err := json.Unmarshal(data, &account); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal account.")
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (store *Store) saveAccount(ctx context.Context, account *Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to serialize account.")
+		return err
+	}
+
+	if err := store.redis.Set(ctx, "account:"+account.ID, data, 0).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to save account to Redis.")
+		return err
+	}
+
+	return nil
+}
+
+func (store *Store) createAuthSession(ctx context.Context, account *Account) (*AuthResult, error) {
+	token := uuid.NewString()
+
+	if err := store.redis.Set(ctx, "auth:"+token, account.ID, AccountSessionDuration).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to save account session to Redis.")
+		return nil, err
+	}
+
+	return &AuthResult{
+		Account: account.Info(),
+		Token:   token,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"influence_game/internal/game"
+	"net/http"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrGameNotArchived = game.NewError("game_not_archived", http.StatusNotFound)
+	ErrInvalidPage     = game.NewError("invalid_page", http.StatusBadRequest)
+	ErrInvalidPerPage  = game.NewError("invalid_per_page", http.StatusBadRequest)
+)
+
+type ArchivedGame struct {
+	ID             uuid.UUID    `json:"-" db:"id"`
+	GameID         string       `json:"gameId" db:"game_id"`
+	RolePack       string       `json:"rolePack" db:"role_pack"`
+	Variants       string       `json:"variants" db:"variants"` // comma separated
+	PlayerCount    int          `json:"playerCount" db:"player_count"`
+	Turns          int          `json:"turns" db:"turns"`
+	WinnerPlayerID string       `json:"winnerPlayerId" db:"winner_player_id"`
+	WinnerNickname string       `json:"winnerNickname" db:"winner_nickname"`
+	Seed           int64        `json:"seed" db:"seed"`
+	RoomCreatedAt  time.Time    `json:"roomCreatedAt" db:"room_created_at"`
+	FinishedAt     time.Time    `json:"finishedAt" db:"finished_at"`
+	ReplayLog      nulls.String `json:"-" db:"replay_log"` // game.ReplayLog as JSON
+	CreatedAt      time.Time    `json:"-" db:"created_at"`
+	UpdatedAt      time.Time    `json:"-" db:"updated_at"`
+}
+
+type ArchivedGames []ArchivedGame
+
+/*
+ArchivedPlayer is one seat of a finished game. Bluffs count both claimed
+actions and blocks made without the role; a bluff is successful when no
+challenge caught it. A challenge is won by whichever side turned out right,
+so claimants collect wins and losses too.
+*/
+type ArchivedPlayer struct {
+	ID               uuid.UUID    `json:"-" db:"id"`
+	GameID           string       `json:"gameId" db:"game_id"`
+	PlayerID         string       `json:"playerId" db:"player_id"`
+	UserID           nulls.String `json:"userId" db:"user_id"`
+	Nickname         string       `json:"nickname" db:"nickname"`
+	IsBot            bool         `json:"isBot" db:"is_bot"`
+	Placement        int          `json:"placement" db:"placement"` // 1 is the winner
+	Won              bool         `json:"won" db:"won"`
+	ActionsTaken     int          `json:"actionsTaken" db:"actions_taken"`
+	Bluffs           int          `json:"bluffs" db:"bluffs"`
+	SuccessfulBluffs int          `json:"successfulBluffs" db:"successful_bluffs"`
+	ChallengesMade   int          `json:"challengesMade" db:"challenges_made"`
+	ChallengesWon    int          `json:"challengesWon" db:"challenges_won"`
+	ChallengesLost   int          `json:"challengesLost" db:"challenges_lost"`
+	CreatedAt        time.Time    `json:"-" db:"created_at"`
+	UpdatedAt        time.Time    `json:"-" db:"updated_at"`
+}
+
+type ArchivedPlayers []ArchivedPlayer
+
+type ArchivedAction struct {
+	ID              uuid.UUID    `json:"-" db:"id"`
+	GameID          string       `json:"gameId" db:"game_id"`
+	Sequence        int          `json:"sequence" db:"sequence"`
+	ActorPlayerID   string       `json:"actorPlayerId" db:"actor_player_id"`
+	ActionName      string       `json:"actionName" db:"action_name"`
+	TargetPlayerID  nulls.String `json:"targetPlayerId" db:"target_player_id"`
+	ClaimedRole     string       `json:"claimedRole" db:"claimed_role"`
+	Bluff           bool         `json:"bluff" db:"bluff"`
+	BlockerPlayerID nulls.String `json:"blockerPlayerId" db:"blocker_player_id"`
+	BlockRole       string       `json:"blockRole" db:"block_role"`
+	BlockBluff      bool         `json:"blockBluff" db:"block_bluff"`
+	Challenged      bool         `json:"challenged" db:"challenged"`
+	Outcome         string       `json:"outcome" db:"outcome"`
+	CreatedAt       time.Time    `json:"-" db:"created_at"`
+	UpdatedAt       time.Time    `json:"-" db:"updated_at"`
+}
+
+type ArchivedActions []ArchivedAction
+
+type GameDetail struct {
+	ArchivedGame
+	Players ArchivedPlayers `json:"players"`
+	Actions ArchivedActions `json:"actions"`
+}
+
+type GamePage struct {
+	Games      ArchivedGames `json:"games"`
+	Page       int           `json:"page"`
+	PerPage    int           `json:"perPage"`
+	Total      int           `json:"total"`
+	TotalPages int           `json:"totalPages"`
+}
+
+type GameFilter struct {
+	UserID   string
+	Nickname string
+}
+
+type PlayerStats struct {
+	Games            int     `json:"games" db:"games"`
+	Wins             int     `json:"wins" db:"wins"`
+	WinRate          float64 `json:"winRate" db:"-"`
+	AveragePlacement float64 `json:"averagePlacement" db:"average_placement"`
+	ActionsTaken     int     `json:"actionsTaken" db:"actions_taken"`
+	Bluffs           int     `json:"bluffs" db:"bluffs"`
+	SuccessfulBluffs int     `json:"successfulBluffs" db:"successful_bluffs"`
+	ChallengesMade   int     `json:"challengesMade" db:"challenges_made"`
+	ChallengesWon    int     `json:"challengesWon" db:"challenges_won"`
+	ChallengesLost   int     `json:"challengesLost" db:"challenges_lost"`
+}
@@ -0,0 +1,6 @@
+//go:build cgo
+
+package archive
+
+// SQLite is the local development database; the driver needs cgo.
+import _ "github.com/mattn/go-sqlite3"
@@ -0,0 +1,196 @@
+package archive
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"influence_game/internal/game"
+	"influence_game/migrations"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+Store is the durable archive of finished games. Redis only holds games while
+they are played; once a game finishes its seats and action history are
+written here and kept.
+*/
+type Store struct {
+	db *pop.Connection
+}
+
+func NewStore(db *pop.Connection) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// Migrate brings the archive tables up to date from the embedded migrations.
+func (store *Store) Migrate() error {
+	migrator, err := pop.NewMigrationBox(migrations.FS, store.db)
+	if err != nil {
+		return err
+	}
+	return migrator.Up()
+}
+
+/*
+HandleGameFinished is meant to be registered with game.Store.OnGameFinished.
+The game is archived in the background, off the request that finished it.
+*/
+func (store *Store) HandleGameFinished(finished *game.Game) {
+	go func() {
+		if err := store.RecordGame(finished); err != nil {
+			log.Error().Err(err).Str("gameID", finished.ID).Msg("Failed to archive game.")
+		}
+	}()
+}
+
+// RecordGame archives a finished game. Archiving the same game twice is a no-op.
+func (store *Store) RecordGame(finished *game.Game) error {
+	archived, players, actions := Summarize(finished, time.Now().UTC())
+
+	return store.db.Transaction(func(tx *pop.Connection) error {
+		exists, err := tx.Where("game_id = ?", finished.ID).Exists(&ArchivedGame{})
+		if err != nil || exists {
+			return err
+		}
+
+		if err := tx.Create(&archived); err != nil {
+			return err
+		}
+		if len(players) > 0 {
+			if err := tx.Create(&players); err != nil {
+				return err
+			}
+		}
+		if len(actions) > 0 {
+			if err := tx.Create(&actions); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListGames returns finished games, most recent first.
+func (store *Store) ListGames(filter GameFilter, page int, perPage int) (*GamePage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultPageSize
+	}
+	perPage = min(perPage, MaxPageSize)
+
+	query := store.db.Order("finished_at DESC").Paginate(page, perPage)
+	if filter.UserID != "" {
+		query = query.Where("game_id IN (SELECT game_id FROM archived_players WHERE user_id = ?)", filter.UserID)
+	}
+	if filter.Nickname != "" {
+		query = query.Where("game_id IN (SELECT game_id FROM archived_players WHERE nickname = ?)", filter.Nickname)
+	}
+
+	games := ArchivedGames{}
+	if err := query.All(&games); err != nil {
+		log.Error().Err(err).Msg("Failed to list archived games.")
+		return nil, err
+	}
+
+	return &GamePage{
+		Games:      games,
+		Page:       query.Paginator.Page,
+		PerPage:    query.Paginator.PerPage,
+		Total:      query.Paginator.TotalEntriesSize,
+		TotalPages: query.Paginator.TotalPages,
+	}, nil
+}
+
+func (store *Store) GetGame(gameID string) (*GameDetail, error) {
+	detail := &GameDetail{}
+
+	err := store.db.Where("game_id = ?", gameID).First(&detail.ArchivedGame)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGameNotArchived
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get archived game.")
+		return nil, err
+	}
+
+	if err := store.db.Where("game_id = ?", gameID).Order("placement ASC").All(&detail.Players); err != nil {
+		log.Error().Err(err).Msg("Failed to get archived players.")
+		return nil, err
+	}
+	if err := store.db.Where("game_id = ?", gameID).Order("sequence ASC").All(&detail.Actions); err != nil {
+		log.Error().Err(err).Msg("Failed to get archived actions.")
+		return nil, err
+	}
+
+	return detail, nil
+}
+
+// GetReplayLog returns the command log archived with a game.
+func (store *Store) GetReplayLog(gameID string) (*game.ReplayLog, error) {
+	archived := ArchivedGame{}
+
+	err := store.db.Where("game_id = ?", gameID).First(&archived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGameNotArchived
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get archived game.")
+		return nil, err
+	}
+	if !archived.ReplayLog.Valid {
+		return nil, game.ErrReplayUnavailable
+	}
+
+	var replayLog game.ReplayLog
+	if err := json.Unmarshal([]byte(archived.ReplayLog.String), &replayLog); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal replay log.")
+		return nil, err
+	}
+
+	return &replayLog, nil
+}
+
+// UserStats adds up every archived seat played by an account.
+func (store *Store) UserStats(userID string) (*PlayerStats, error) {
+	return store.stats("user_id = ?", userID)
+}
+
+// NicknameStats adds up every archived seat played under a nickname, guests included.
+func (store *Store) NicknameStats(nickname string) (*PlayerStats, error) {
+	return store.stats("nickname = ?", nickname)
+}
+
+func (store *Store) stats(condition string, arg any) (*PlayerStats, error) {
+	stats := &PlayerStats{}
+
+	err := store.db.RawQuery(`
+		SELECT
+			COUNT(*) AS games,
+			COALESCE(SUM(CASE WHEN won THEN 1 ELSE 0 END), 0) AS wins,
+			COALESCE(AVG(placement), 0) AS average_placement,
+			COALESCE(SUM(actions_taken), 0) AS actions_taken,
+			COALESCE(SUM(bluffs), 0) AS bluffs,
+			COALESCE(SUM(successful_bluffs), 0) AS successful_bluffs,
+			COALESCE(SUM(challenges_made), 0) AS challenges_made,
+			COALESCE(SUM(challenges_won), 0) AS challenges_won,
+			COALESCE(SUM(challenges_lost), 0) AS challenges_lost
+		FROM archived_players
+		WHERE `+condition, arg).First(stats)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get player stats.")
+		return nil, err
+	}
+
+	if stats.Games > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(stats.Games)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,69 @@
+package archive
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gobuffalo/pop/v6"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	db, err := pop.NewConnection(&pop.ConnectionDetails{
+		Dialect:  "sqlite3",
+		Database: filepath.Join(t.TempDir(), "archive.sqlite"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected connection error %v", err)
+	}
+	if err := db.Open(); err != nil {
+		t.Skipf("sqlite is not available: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := NewStore(db)
+	if err := store.Migrate(); err != nil {
+		t.Fatalf("unexpected migration error %v", err)
+	}
+	return store
+}
+
+func TestRecordGameIsListedOnceWithStats(t *testing.T) {
+	store := newTestStore(t)
+	finished := finishedGame()
+
+	for range 2 {
+		if err := store.RecordGame(finished); err != nil {
+			t.Fatalf("unexpected record error %v", err)
+		}
+	}
+
+	page, err := store.ListGames(GameFilter{UserID: "u1"}, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected list error %v", err)
+	}
+	if page.Total != 1 || len(page.Games) != 1 || page.Games[0].WinnerNickname != "alice" {
+		t.Fatalf("expected the game listed once, got %+v", page)
+	}
+
+	detail, err := store.GetGame(finished.ID)
+	if err != nil {
+		t.Fatalf("unexpected get error %v", err)
+	}
+	if len(detail.Players) != 2 || len(detail.Actions) != 1 {
+		t.Fatalf("expected 2 players and 1 action, got %d and %d", len(detail.Players), len(detail.Actions))
+	}
+
+	stats, err := store.NicknameStats("bob")
+	if err != nil {
+		t.Fatalf("unexpected stats error %v", err)
+	}
+	if stats.Games != 1 || stats.Wins != 0 || stats.Bluffs != 1 || stats.ChallengesLost != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if _, err := store.GetGame("missing"); err != ErrGameNotArchived {
+		t.Fatalf("expected %v, got %v", ErrGameNotArchived, err)
+	}
+}
@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"encoding/json"
+	"influence_game/internal/game"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+)
+
+// Summarize turns a finished game into the rows the archive stores.
+func Summarize(finished *game.Game, finishedAt time.Time) (ArchivedGame, ArchivedPlayers, ArchivedActions) {
+	archived := ArchivedGame{
+		GameID:        finished.ID,
+		RolePack:      finished.Settings.RolePack,
+		Variants:      strings.Join(finished.Settings.Variants, ","),
+		PlayerCount:   len(finished.Players),
+		Turns:         len(finished.History),
+		Seed:          finished.Seed,
+		RoomCreatedAt: finished.CreatedAt,
+		FinishedAt:    finishedAt,
+	}
+
+	if replayLog, err := game.NewReplayLog(finished); err == nil {
+		if data, err := json.Marshal(replayLog); err == nil {
+			archived.ReplayLog = nulls.NewString(string(data))
+		}
+	}
+
+	players := ArchivedPlayers{}
+	seats := map[string]int{}
+	for i, player := range game.FinishingOrder(finished) {
+		archivedPlayer := ArchivedPlayer{
+			GameID:    finished.ID,
+			PlayerID:  player.ID,
+			Nickname:  player.Nickname,
+			IsBot:     player.IsBot,
+			Placement: i + 1,
+			Won:       i == 0,
+		}
+		if player.UserID != "" {
+			archivedPlayer.UserID = nulls.NewString(player.UserID)
+		}
+		if i == 0 {
+			archived.WinnerPlayerID = player.ID
+			archived.WinnerNickname = player.Nickname
+		}
+
+		seats[player.ID] = len(players)
+		players = append(players, archivedPlayer)
+	}
+
+	stats := func(playerID string) *ArchivedPlayer {
+		if seat, ok := seats[playerID]; ok {
+			return &players[seat]
+		}
+		return &ArchivedPlayer{}
+	}
+
+	actions := ArchivedActions{}
+	for i, record := range finished.History {
+		action := ArchivedAction{
+			GameID:        finished.ID,
+			Sequence:      i + 1,
+			ActorPlayerID: record.ActorID,
+			ActionName:    record.ActionName,
+			ClaimedRole:   record.ClaimedRole,
+			Bluff:         record.Bluff,
+			BlockRole:     record.BlockRole,
+			BlockBluff:    record.BlockBluff,
+			Challenged:    len(record.Challenges) > 0,
+			Outcome:       record.Outcome,
+		}
+		if record.TargetID != nil {
+			action.TargetPlayerID = nulls.NewString(*record.TargetID)
+		}
+		if record.BlockerID != "" {
+			action.BlockerPlayerID = nulls.NewString(record.BlockerID)
+		}
+		actions = append(actions, action)
+
+		stats(record.ActorID).ActionsTaken++
+
+		claimCaught, blockCaught := false, false
+		for _, challenge := range record.Challenges {
+			challenger, claimant := stats(challenge.ChallengerID), stats(challenge.ClaimantID)
+			challenger.ChallengesMade++
+
+			if challenge.ClaimHolds {
+				claimant.ChallengesWon++
+				challenger.ChallengesLost++
+				continue
+			}
+
+			challenger.ChallengesWon++
+			claimant.ChallengesLost++
+			if challenge.ClaimantID == record.BlockerID {
+				blockCaught = true
+			} else {
+				claimCaught = true
+			}
+		}
+
+		if record.Bluff {
+			stats(record.ActorID).Bluffs++
+			if !claimCaught && record.Outcome != "" {
+				stats(record.ActorID).SuccessfulBluffs++
+			}
+		}
+		if record.BlockBluff {
+			stats(record.BlockerID).Bluffs++
+			if !blockCaught && record.Outcome != "" {
+				stats(record.BlockerID).SuccessfulBluffs++
+			}
+		}
+	}
+
+	return archived, players, actions
+}
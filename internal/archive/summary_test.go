@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"influence_game/internal/game"
+	"testing"
+	"time"
+)
+
+// finishedGame is a two-player game bob lost by bluffing a Duke into alice's challenge.
+func finishedGame() *game.Game {
+	winner := "p1"
+	return &game.Game{
+		ID:       "game-1",
+		Finished: true,
+		WinnerID: &winner,
+		Players: []*game.Player{
+			{ID: "p1", Nickname: "alice", UserID: "u1"},
+			{ID: "p2", Nickname: "bob"},
+		},
+		EliminatedPlayerIDs: []string{"p2"},
+		History: []game.ActionRecord{{
+			ActionID:    "a1",
+			ActorID:     "p2",
+			ActionName:  "tax",
+			ClaimedRole: "Duke",
+			Bluff:       true,
+			Challenges: []game.ChallengeRecord{{
+				ChallengerID: "p1",
+				ClaimantID:   "p2",
+				Role:         "Duke",
+				ClaimHolds:   false,
+			}},
+			Outcome: "challenge_won",
+		}},
+	}
+}
+
+func TestSummarizeCountsBluffsAndChallenges(t *testing.T) {
+	archived, players, actions := Summarize(finishedGame(), time.Now())
+
+	if archived.WinnerNickname != "alice" || archived.Turns != 1 || len(actions) != 1 {
+		t.Fatalf("unexpected summary %+v", archived)
+	}
+
+	alice, bob := players[0], players[1]
+	if !alice.Won || alice.Placement != 1 || alice.ChallengesMade != 1 || alice.ChallengesWon != 1 {
+		t.Fatalf("unexpected winner stats %+v", alice)
+	}
+	if bob.Placement != 2 || bob.ActionsTaken != 1 || bob.Bluffs != 1 || bob.SuccessfulBluffs != 0 || bob.ChallengesLost != 1 {
+		t.Fatalf("unexpected loser stats %+v", bob)
+	}
+	if !actions[0].Challenged || actions[0].Outcome != "challenge_won" {
+		t.Fatalf("unexpected action %+v", actions[0])
+	}
+}
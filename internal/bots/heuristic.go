@@ -0,0 +1,353 @@
+package bots
+
+import (
+	"influence_game/internal/game"
+	"math/rand"
+	"sort"
+)
+
+const (
+	bluffChance           = 0.25
+	speculativeChallenge  = 0.05
+	foreignAidChance      = 0.5
+	lastInfluenceBluffing = 0.8
+)
+
+/*
+Heuristic plays its own cards honestly, bluffs now and then, and counts cards:
+a claim is challenged for sure once every copy of the role is accounted for
+in its hand or face up on the table.
+*/
+type Heuristic struct{}
+
+func (Heuristic) Name() string {
+	return StrategyHeuristic
+}
+
+func (Heuristic) Declare(view game.PlayerView, rng *rand.Rand) game.DeclareActionPayload {
+	if coups := legalActionsNamed(view, "coup"); len(coups) > 0 {
+		return mostThreatening(view, coups)
+	}
+
+	honest := []game.DeclareActionPayload{}
+	bluffs := []game.DeclareActionPayload{}
+	for _, action := range view.LegalActions {
+		if action.ClaimedRole == "" {
+			continue
+		}
+		if holds(view, action.ClaimedRole) {
+			honest = append(honest, action)
+		} else if !claimImpossible(view, action.ClaimedRole, view.PlayerID) {
+			bluffs = append(bluffs, action)
+		}
+	}
+
+	if action, ok := bestClaimedAction(view, honest); ok {
+		return action
+	}
+
+	if embezzles := legalActionsNamed(view, "embezzle"); len(embezzles) > 0 &&
+		view.State.TreasuryReserve >= 3 && !holdsRoleGranting(view, "tax") {
+		return embezzles[0]
+	}
+
+	if len(bluffs) > 0 && rng.Float64() < bluffChance {
+		if action, ok := bestClaimedAction(view, bluffs); ok {
+			return action
+		}
+	}
+
+	if aid := legalActionsNamed(view, "foreign_aid"); len(aid) > 0 && rng.Float64() < foreignAidChance {
+		return aid[0]
+	}
+	if income := legalActionsNamed(view, "income"); len(income) > 0 {
+		return income[0]
+	}
+
+	return view.LegalActions[0]
+}
+
+func (Heuristic) Respond(view game.PlayerView, rng *rand.Rand) game.ActionResponse {
+	pending := view.State.PendingAction
+	pass := game.ActionResponse{Response: game.ResponsePass}
+	challenge := game.ActionResponse{Response: game.ResponseChallenge}
+
+	if pending.Status == "blocked" {
+		if canRespond(view, challenge) && claimImpossible(view, pending.Block.Role, pending.Block.BlockerID) {
+			return challenge
+		}
+		return pass
+	}
+
+	if canRespond(view, challenge) && !pending.InvertedClaim &&
+		claimImpossible(view, pending.ClaimedRole, pending.ActorID) {
+		return challenge
+	}
+
+	blocks := legalBlocks(view)
+	for _, block := range blocks {
+		if holds(view, block.Role) {
+			return block
+		}
+	}
+
+	targeted := pending.TargetID != nil && *pending.TargetID == view.PlayerID
+	if targeted && pending.ActionName == "assassinate" && len(hiddenRoles(view)) == 1 {
+		// Losing the last card ends the game anyway, so a bluff costs nothing.
+		for _, block := range blocks {
+			if !claimImpossible(view, block.Role, view.PlayerID) && rng.Float64() < lastInfluenceBluffing {
+				return block
+			}
+		}
+		if canRespond(view, challenge) {
+			return challenge
+		}
+	}
+
+	if canRespond(view, challenge) && !pending.InvertedClaim &&
+		len(hiddenRoles(view)) > 1 && rng.Float64() < speculativeChallenge {
+		return challenge
+	}
+
+	return pass
+}
+
+func (Heuristic) LoseInfluence(view game.PlayerView, rng *rand.Rand) string {
+	hidden := hiddenRoles(view)
+	sortByValue(view, hidden)
+	return hidden[len(hidden)-1]
+}
+
+func (Heuristic) Exchange(view game.PlayerView, rng *rand.Rand) []string {
+	return bestDistinct(view, exchangePool(view), view.Keep)
+}
+
+// ShowInfluence shows the weakest card, the one an examiner is least likely to take.
+func (Heuristic) ShowInfluence(view game.PlayerView, rng *rand.Rand) string {
+	hidden := hiddenRoles(view)
+	sortByValue(view, hidden)
+	return hidden[len(hidden)-1]
+}
+
+// Examine forces a swap when the examined card is one of the strong ones.
+func (Heuristic) Examine(view game.PlayerView, rng *rand.Rand) bool {
+	if view.Examined == nil {
+		return false
+	}
+	return roleValue(view, view.Examined.Role) >= 3
+}
+
+func (Heuristic) Draft(view game.PlayerView, rng *rand.Rand) []string {
+	return bestDistinct(view, optionRoles(view), view.Keep)
+}
+
+/*
+claimImpossible reports whether a claim by the claimant must be a bluff: every
+copy of the role is either in this bot's hand or already revealed.
+*/
+func claimImpossible(view game.PlayerView, role string, claimantID string) bool {
+	if role == "" || view.CopiesPerRole == 0 {
+		return false
+	}
+
+	seen := 0
+	if claimantID != view.PlayerID {
+		for _, hidden := range hiddenRoles(view) {
+			if hidden == role {
+				seen++
+			}
+		}
+	}
+
+	for _, player := range view.State.Players {
+		for _, influence := range player.Influences {
+			if influence.Revealed && influence.Role != nil && *influence.Role == role {
+				seen++
+			}
+		}
+	}
+
+	return seen >= view.CopiesPerRole
+}
+
+func holds(view game.PlayerView, role string) bool {
+	for _, hidden := range hiddenRoles(view) {
+		if hidden == role {
+			return true
+		}
+	}
+	return false
+}
+
+func holdsRoleGranting(view game.PlayerView, actionName string) bool {
+	for _, role := range view.Roles {
+		for _, action := range role.Actions {
+			if action == actionName && holds(view, role.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func canRespond(view game.PlayerView, response game.ActionResponse) bool {
+	for _, legal := range view.LegalResponses {
+		if legal == response {
+			return true
+		}
+	}
+	return false
+}
+
+func legalBlocks(view game.PlayerView) []game.ActionResponse {
+	blocks := []game.ActionResponse{}
+	for _, response := range view.LegalResponses {
+		if response.Response == game.ResponseBlock {
+			blocks = append(blocks, response)
+		}
+	}
+	return blocks
+}
+
+func legalActionsNamed(view game.PlayerView, name string) []game.DeclareActionPayload {
+	actions := []game.DeclareActionPayload{}
+	for _, action := range view.LegalActions {
+		if action.ActionName == name {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+/*
+bestClaimedAction prefers killing, then money, then improving the hand.
+Targeted actions go after the most threatening opponent.
+*/
+func bestClaimedAction(view game.PlayerView, actions []game.DeclareActionPayload) (game.DeclareActionPayload, bool) {
+	for _, name := range []string{"assassinate", "tax", "steal", "exchange", "examine"} {
+		candidates := []game.DeclareActionPayload{}
+		for _, action := range actions {
+			if action.ActionName != name {
+				continue
+			}
+			if name == "steal" && coinsOf(view, action.TargetPlayerID) < 2 {
+				continue
+			}
+			candidates = append(candidates, action)
+		}
+		if len(candidates) > 0 {
+			return mostThreatening(view, candidates), true
+		}
+	}
+	return game.DeclareActionPayload{}, false
+}
+
+// mostThreatening picks the target with the most hidden cards, then coins.
+func mostThreatening(view game.PlayerView, actions []game.DeclareActionPayload) game.DeclareActionPayload {
+	best := actions[0]
+	bestScore := -1
+	for _, action := range actions {
+		if action.TargetPlayerID == nil {
+			continue
+		}
+		score := 0
+		for _, player := range view.State.Players {
+			if player.ID != *action.TargetPlayerID {
+				continue
+			}
+			for _, influence := range player.Influences {
+				if !influence.Revealed {
+					score += 100
+				}
+			}
+			score += player.Coins
+		}
+		if score > bestScore {
+			best, bestScore = action, score
+		}
+	}
+	return best
+}
+
+func coinsOf(view game.PlayerView, playerID *string) int {
+	if playerID == nil {
+		return 0
+	}
+	for _, player := range view.State.Players {
+		if player.ID == *playerID {
+			return player.Coins
+		}
+	}
+	return 0
+}
+
+var actionValues = map[string]int{
+	"tax":         3,
+	"assassinate": 3,
+	"steal":       2,
+	"exchange":    1,
+	"examine":     1,
+}
+
+var blockValues = map[string]int{
+	"assassinate": 2,
+	"steal":       1,
+	"foreign_aid": 1,
+}
+
+func roleValue(view game.PlayerView, roleName string) int {
+	for _, role := range view.Roles {
+		if role.Name != roleName {
+			continue
+		}
+		value := 0
+		for _, action := range role.Actions {
+			value += actionValues[action]
+		}
+		for _, action := range role.Blocks {
+			value += blockValues[action]
+		}
+		return value
+	}
+	return 0
+}
+
+// sortByValue orders roles from most to least useful.
+func sortByValue(view game.PlayerView, roles []string) {
+	sort.SliceStable(roles, func(i, j int) bool {
+		return roleValue(view, roles[i]) > roleValue(view, roles[j])
+	})
+}
+
+// bestDistinct keeps the most useful roles, avoiding duplicates when it can.
+func bestDistinct(view game.PlayerView, pool []string, count int) []string {
+	sorted := append([]string{}, pool...)
+	sortByValue(view, sorted)
+
+	kept := []string{}
+	var duplicates []string
+	for _, role := range sorted {
+		if len(kept) < count && !contains(kept, role) {
+			kept = append(kept, role)
+		} else {
+			duplicates = append(duplicates, role)
+		}
+	}
+	for _, role := range duplicates {
+		if len(kept) == count {
+			break
+		}
+		kept = append(kept, role)
+	}
+
+	return kept
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
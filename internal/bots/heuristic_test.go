@@ -0,0 +1,92 @@
+package bots
+
+import (
+	"influence_game/internal/game"
+	"math/rand"
+	"testing"
+)
+
+// newTable builds a started game with fixed hands, seats in order.
+func newTable(hands ...[]game.Influence) *game.Game {
+	table := &game.Game{
+		ID:       "game",
+		Started:  true,
+		Settings: game.DefaultRoomSettings(),
+		Deck:     game.NewBaseDeck(),
+	}
+	for i, hand := range hands {
+		table.Players = append(table.Players, &game.Player{
+			ID:         string(rune('a' + i)),
+			Nickname:   string(rune('a' + i)),
+			Coins:      game.DefaultStartingCoins,
+			Alive:      true,
+			Influences: hand,
+		})
+	}
+	return table
+}
+
+func hidden(roles ...string) []game.Influence {
+	hand := []game.Influence{}
+	for _, role := range roles {
+		hand = append(hand, game.Influence{Role: role})
+	}
+	return hand
+}
+
+func TestHeuristicChallengesImpossibleClaim(t *testing.T) {
+	table := newTable(
+		hidden("Captain", "Contessa"),
+		hidden("Duke", "Duke"),
+		[]game.Influence{{Role: "Duke", Revealed: true}, {Role: "Assassin"}},
+	)
+	table.PendingAction = &game.PendingAction{
+		ID:          "action",
+		ActorID:     "a",
+		ActionName:  "tax",
+		ClaimedRole: "Duke",
+		Status:      "declared",
+	}
+
+	view := game.NewPlayerView(table, "b")
+	response := Heuristic{}.Respond(view, rand.New(rand.NewSource(1)))
+	if response.Response != game.ResponseChallenge {
+		t.Fatalf("expected a challenge, got %+v", response)
+	}
+}
+
+func TestHeuristicBlocksWithHeldRole(t *testing.T) {
+	table := newTable(
+		hidden("Assassin", "Duke"),
+		hidden("Contessa", "Captain"),
+	)
+	target := "b"
+	table.PendingAction = &game.PendingAction{
+		ID:          "action",
+		ActorID:     "a",
+		ActionName:  "assassinate",
+		TargetID:    &target,
+		ClaimedRole: "Assassin",
+		Status:      "declared",
+	}
+
+	view := game.NewPlayerView(table, "b")
+	response := Heuristic{}.Respond(view, rand.New(rand.NewSource(1)))
+	if response.Response != game.ResponseBlock || response.Role != "Contessa" {
+		t.Fatalf("expected a Contessa block, got %+v", response)
+	}
+}
+
+func TestHeuristicKeepsStrongCards(t *testing.T) {
+	table := newTable(
+		hidden("Duke", "Ambassador"),
+		hidden("Captain", "Contessa"),
+	)
+	table.PendingLosses = []game.InfluenceLoss{{PlayerID: "a", Reason: "coup"}}
+
+	view := game.NewPlayerView(table, "a")
+	move, ok := Decide(Heuristic{}, view, rand.New(rand.NewSource(1)))
+	if !ok || move.Roles[0] != "Ambassador" {
+		t.Fatalf("expected to give up the Ambassador, got %+v", move)
+	}
+}
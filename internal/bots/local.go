@@ -0,0 +1,28 @@
+package bots
+
+import "influence_game/internal/game"
+
+// Play applies a move straight to an in-memory game, for callers that run
+// the rules without a Store.
+func Play(current *game.Game, playerID string, move Move) error {
+	var err error
+
+	switch move.Decision {
+	case game.DecisionDeclare:
+		_, err = game.Declare(current, playerID, move.Action)
+	case game.DecisionRespond:
+		_, err = game.Respond(current, playerID, move.Response)
+	case game.DecisionLoseInfluence:
+		_, err = game.LoseInfluence(current, playerID, move.Roles[0])
+	case game.DecisionExchange:
+		_, err = game.CompleteExchange(current, playerID, move.Roles)
+	case game.DecisionShowInfluence:
+		_, err = game.ShowInfluence(current, playerID, move.Roles[0])
+	case game.DecisionExamination:
+		_, err = game.CompleteExamination(current, playerID, move.ForceSwap)
+	case game.DecisionDraft:
+		_, err = game.SelectInfluences(current, playerID, move.Roles)
+	}
+
+	return err
+}
@@ -0,0 +1,51 @@
+package bots
+
+import (
+	"influence_game/internal/game"
+	"math/rand"
+)
+
+// Random picks uniformly among legal moves. It is mostly useful as a baseline.
+type Random struct{}
+
+func (Random) Name() string {
+	return StrategyRandom
+}
+
+func (Random) Declare(view game.PlayerView, rng *rand.Rand) game.DeclareActionPayload {
+	return view.LegalActions[rng.Intn(len(view.LegalActions))]
+}
+
+func (Random) Respond(view game.PlayerView, rng *rand.Rand) game.ActionResponse {
+	return view.LegalResponses[rng.Intn(len(view.LegalResponses))]
+}
+
+func (Random) LoseInfluence(view game.PlayerView, rng *rand.Rand) string {
+	hidden := hiddenRoles(view)
+	return hidden[rng.Intn(len(hidden))]
+}
+
+func (Random) Exchange(view game.PlayerView, rng *rand.Rand) []string {
+	return pickRandom(exchangePool(view), view.Keep, rng)
+}
+
+func (Random) ShowInfluence(view game.PlayerView, rng *rand.Rand) string {
+	hidden := hiddenRoles(view)
+	return hidden[rng.Intn(len(hidden))]
+}
+
+func (Random) Examine(view game.PlayerView, rng *rand.Rand) bool {
+	return rng.Intn(2) == 0
+}
+
+func (Random) Draft(view game.PlayerView, rng *rand.Rand) []string {
+	return pickRandom(optionRoles(view), view.Keep, rng)
+}
+
+func pickRandom(roles []string, count int, rng *rand.Rand) []string {
+	shuffled := append([]string{}, roles...)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:min(count, len(shuffled))]
+}
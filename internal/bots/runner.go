@@ -0,0 +1,128 @@
+package bots
+
+import (
+	"influence_game/internal/game"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ThinkDelay spaces bot moves out so humans can follow the table.
+const ThinkDelay = 750 * time.Millisecond
+
+/*
+Runner plays every bot seated in a game. It listens to store updates and,
+while some bot has a decision pending, acts for it through Store.PlayForSeat,
+under the same rules a human client's requests go through.
+*/
+type Runner struct {
+	store *game.Store
+	delay time.Duration
+
+	mu      sync.Mutex
+	running map[string]bool
+	dirty   map[string]bool
+}
+
+func NewRunner(store *game.Store) *Runner {
+	return &Runner{
+		store:   store,
+		delay:   ThinkDelay,
+		running: map[string]bool{},
+		dirty:   map[string]bool{},
+	}
+}
+
+// HandleGameUpdated is meant to be registered with Store.OnGameUpdated.
+func (runner *Runner) HandleGameUpdated(updated *game.Game) {
+	if !updated.Started || updated.Finished || !hasBots(updated) {
+		return
+	}
+
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	if runner.running[updated.ID] {
+		runner.dirty[updated.ID] = true
+		return
+	}
+
+	runner.running[updated.ID] = true
+	go runner.run(updated.ID)
+}
+
+/*
+run keeps one goroutine per game, so bots never race each other. Updates that
+arrive while it is busy mark the game dirty and it takes another look.
+*/
+func (runner *Runner) run(gameID string) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for {
+		time.Sleep(runner.delay)
+
+		if runner.step(gameID, rng) {
+			continue
+		}
+
+		runner.mu.Lock()
+		if runner.dirty[gameID] {
+			delete(runner.dirty, gameID)
+			runner.mu.Unlock()
+			continue
+		}
+		delete(runner.running, gameID)
+		runner.mu.Unlock()
+		return
+	}
+}
+
+// step makes one move for the first bot with a decision pending.
+func (runner *Runner) step(gameID string, rng *rand.Rand) bool {
+	current, err := runner.store.GetGame(gameID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load game for bots.")
+		return false
+	}
+
+	for _, player := range current.Players {
+		if !player.IsBot {
+			continue
+		}
+
+		view := game.NewPlayerView(current, player.ID)
+		if view.Decision == game.DecisionNone {
+			continue
+		}
+
+		strategy, ok := Lookup(player.Strategy)
+		if !ok {
+			strategy = Random{}
+		}
+
+		move, _ := Decide(strategy, view, rng)
+		if _, err := runner.store.PlayForSeat(gameID, player.ID, move); err != nil {
+			log.Error().Err(err).Str("strategy", strategy.Name()).Msg("Bot move was rejected, falling back.")
+
+			if _, err := runner.store.PlayForSeat(gameID, player.ID, FallbackMove(view)); err != nil {
+				log.Error().Err(err).Msg("Failed to make fallback bot move.")
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func hasBots(current *game.Game) bool {
+	for _, player := range current.Players {
+		if player.IsBot {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package bots
+
+import (
+	"fmt"
+	"influence_game/internal/game"
+	"math/rand"
+)
+
+const DefaultMaxMoves = 2000
+
+type SimulationConfig struct {
+	Games      int
+	Players    int
+	Strategies []string // assigned to players in order, cycling when shorter
+	Settings   game.RoomSettings
+	Seed       int64
+	MaxMoves   int  // games still running after this many moves count as unfinished
+	Rotate     bool // shift strategies one player along every game
+}
+
+type SeatStats struct {
+	Seat    int     `json:"seat"` // 1 is the starting player
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"winRate"`
+}
+
+type StrategyStats struct {
+	Strategy string  `json:"strategy"`
+	Games    int     `json:"games"`
+	Wins     int     `json:"wins"`
+	WinRate  float64 `json:"winRate"`
+}
+
+type SimulationReport struct {
+	Games        int             `json:"games"`
+	Players      int             `json:"players"`
+	Seed         int64           `json:"seed"`
+	Unfinished   int             `json:"unfinished"`
+	AverageTurns float64         `json:"averageTurns"`
+	AverageMoves float64         `json:"averageMoves"`
+	Seats        []SeatStats     `json:"seats"`
+	Strategies   []StrategyStats `json:"strategies"`
+}
+
+/*
+Simulate plays bots against each other straight on the rules, without Redis
+or HTTP. The same config and seed always produce the same report.
+*/
+func Simulate(config SimulationConfig) (SimulationReport, error) {
+	if config.Players < game.MinPlayers {
+		return SimulationReport{}, fmt.Errorf("%w: got %d", game.ErrNeedAtLeastTwoPlayers, config.Players)
+	}
+	if config.Players > game.MaxPlayers {
+		return SimulationReport{}, fmt.Errorf("%w: got %d", game.ErrTooManyPlayers, config.Players)
+	}
+	if config.Games < 0 {
+		return SimulationReport{}, fmt.Errorf("games must not be negative, got %d", config.Games)
+	}
+	if config.MaxMoves == 0 {
+		config.MaxMoves = DefaultMaxMoves
+	}
+	if len(config.Strategies) == 0 {
+		config.Strategies = []string{DefaultStrategy}
+	}
+	for _, name := range config.Strategies {
+		if _, ok := Lookup(name); !ok {
+			return SimulationReport{}, fmt.Errorf("%w: %s", ErrUnknownStrategy, name)
+		}
+	}
+
+	report := SimulationReport{
+		Games:   config.Games,
+		Players: config.Players,
+		Seed:    config.Seed,
+		Seats:   make([]SeatStats, config.Players),
+	}
+	for i := range report.Seats {
+		report.Seats[i].Seat = i + 1
+	}
+
+	strategyIndex := map[string]int{}
+	for _, name := range config.Strategies {
+		if _, ok := strategyIndex[name]; !ok {
+			strategyIndex[name] = len(report.Strategies)
+			report.Strategies = append(report.Strategies, StrategyStats{Strategy: name})
+		}
+	}
+
+	rng := rand.New(rand.NewSource(config.Seed))
+	totalTurns, totalMoves := 0, 0
+
+	for gameNumber := range config.Games {
+		assigned := make([]string, config.Players)
+		for i := range assigned {
+			offset := i
+			if config.Rotate {
+				offset += gameNumber
+			}
+			assigned[i] = config.Strategies[offset%len(config.Strategies)]
+		}
+
+		result, err := simulateGame(config, assigned, game.DeriveSeed(config.Seed, gameNumber), rng)
+		if err != nil {
+			return SimulationReport{}, err
+		}
+
+		totalTurns += result.turns
+		totalMoves += result.moves
+
+		for i, name := range assigned {
+			seat := (i - result.startingIndex + config.Players) % config.Players
+			report.Seats[seat].Games++
+			report.Strategies[strategyIndex[name]].Games++
+
+			if result.winnerIndex == i {
+				report.Seats[seat].Wins++
+				report.Strategies[strategyIndex[name]].Wins++
+			}
+		}
+		if result.winnerIndex < 0 {
+			report.Unfinished++
+		}
+	}
+
+	if config.Games > 0 {
+		report.AverageTurns = float64(totalTurns) / float64(config.Games)
+		report.AverageMoves = float64(totalMoves) / float64(config.Games)
+	}
+	for i := range report.Seats {
+		report.Seats[i].WinRate = winRate(report.Seats[i].Wins, report.Seats[i].Games)
+	}
+	for i := range report.Strategies {
+		report.Strategies[i].WinRate = winRate(report.Strategies[i].Wins, report.Strategies[i].Games)
+	}
+
+	return report, nil
+}
+
+type gameResult struct {
+	startingIndex int
+	winnerIndex   int // -1 when the game did not finish
+	turns         int
+	moves         int
+}
+
+func simulateGame(config SimulationConfig, assigned []string, seed int64, rng *rand.Rand) (gameResult, error) {
+	current, err := game.NewLocalGame(config.Players, config.Settings, seed)
+	if err != nil {
+		return gameResult{}, err
+	}
+
+	result := gameResult{startingIndex: current.TurnIndex, winnerIndex: -1}
+
+	for result.moves < config.MaxMoves && !current.Finished {
+		acted := false
+
+		for i, player := range current.Players {
+			view := game.NewPlayerView(current, player.ID)
+			if view.Decision == game.DecisionNone {
+				continue
+			}
+
+			strategy, _ := Lookup(assigned[i])
+			move, _ := Decide(strategy, view, rng)
+			if err := Play(current, player.ID, move); err != nil {
+				if err := Play(current, player.ID, FallbackMove(view)); err != nil {
+					return gameResult{}, fmt.Errorf("%s could not move: %w", strategy.Name(), err)
+				}
+			}
+
+			if view.Decision == game.DecisionDeclare {
+				result.turns++
+			}
+			result.moves++
+			acted = true
+			break
+		}
+
+		if !acted {
+			return gameResult{}, fmt.Errorf("game %d stalled with no decision pending", seed)
+		}
+	}
+
+	if current.WinnerID != nil {
+		for i, player := range current.Players {
+			if player.ID == *current.WinnerID {
+				result.winnerIndex = i
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func winRate(wins int, games int) float64 {
+	if games == 0 {
+		return 0
+	}
+	return float64(wins) / float64(games)
+}
@@ -0,0 +1,39 @@
+package bots
+
+import (
+	"influence_game/internal/game"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	settings := game.DefaultRoomSettings()
+	config := SimulationConfig{
+		Games:      20,
+		Players:    4,
+		Strategies: []string{StrategyHeuristic, StrategyRandom},
+		Settings:   settings,
+		Seed:       42,
+		Rotate:     true,
+	}
+
+	first, err := Simulate(config)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	second, err := Simulate(config)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected identical reports, got %+v and %+v", first, second)
+	}
+
+	wins := 0
+	for _, seat := range first.Seats {
+		wins += seat.Wins
+	}
+	if wins+first.Unfinished != config.Games {
+		t.Fatalf("expected every game to be won or unfinished, got %d wins and %d unfinished", wins, first.Unfinished)
+	}
+}
@@ -0,0 +1,132 @@
+package bots
+
+import (
+	"influence_game/internal/game"
+	"math/rand"
+	"net/http"
+	"sort"
+)
+
+const (
+	StrategyRandom    = "random"
+	StrategyHeuristic = "heuristic"
+
+	DefaultStrategy = StrategyHeuristic
+)
+
+var ErrUnknownStrategy = game.NewError("unknown_strategy", http.StatusBadRequest)
+
+/*
+Strategy decides a bot's moves. Each method is only called for the matching
+decision in the view, and should pick from the legal moves it lists; anything
+the rules reject is replaced by a safe fallback move.
+*/
+type Strategy interface {
+	Name() string
+	Declare(view game.PlayerView, rng *rand.Rand) game.DeclareActionPayload
+	Respond(view game.PlayerView, rng *rand.Rand) game.ActionResponse
+	LoseInfluence(view game.PlayerView, rng *rand.Rand) string
+	Exchange(view game.PlayerView, rng *rand.Rand) []string
+	ShowInfluence(view game.PlayerView, rng *rand.Rand) string
+	Examine(view game.PlayerView, rng *rand.Rand) bool
+	Draft(view game.PlayerView, rng *rand.Rand) []string
+}
+
+// Move is a strategy's answer to whatever decision the view was built for.
+type Move = game.Move
+
+var strategies = map[string]Strategy{
+	StrategyRandom:    Random{},
+	StrategyHeuristic: Heuristic{},
+}
+
+func Lookup(name string) (Strategy, bool) {
+	strategy, ok := strategies[name]
+	return strategy, ok
+}
+
+func Names() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decide asks the strategy for a move. It returns false when the view has no
+// decision pending.
+func Decide(strategy Strategy, view game.PlayerView, rng *rand.Rand) (Move, bool) {
+	move := Move{Decision: view.Decision}
+
+	switch view.Decision {
+	case game.DecisionDeclare:
+		move.Action = strategy.Declare(view, rng)
+	case game.DecisionRespond:
+		move.ActionID = view.State.PendingAction.ID
+		move.Response = strategy.Respond(view, rng)
+	case game.DecisionLoseInfluence:
+		move.Roles = []string{strategy.LoseInfluence(view, rng)}
+	case game.DecisionExchange:
+		move.Roles = strategy.Exchange(view, rng)
+	case game.DecisionShowInfluence:
+		move.Roles = []string{strategy.ShowInfluence(view, rng)}
+	case game.DecisionExamination:
+		move.ForceSwap = strategy.Examine(view, rng)
+	case game.DecisionDraft:
+		move.Roles = strategy.Draft(view, rng)
+	default:
+		return Move{}, false
+	}
+
+	return move, true
+}
+
+/*
+FallbackMove is always accepted by the rules: the first legal action, a pass,
+the first hidden card, or keeping the current hand.
+*/
+func FallbackMove(view game.PlayerView) Move {
+	move := Move{Decision: view.Decision}
+
+	switch view.Decision {
+	case game.DecisionDeclare:
+		if len(view.LegalActions) > 0 {
+			move.Action = view.LegalActions[0]
+		}
+	case game.DecisionRespond:
+		move.ActionID = view.State.PendingAction.ID
+		move.Response = game.ActionResponse{Response: game.ResponsePass}
+	case game.DecisionLoseInfluence, game.DecisionShowInfluence:
+		move.Roles = hiddenRoles(view)[:1]
+	case game.DecisionExchange:
+		move.Roles = hiddenRoles(view)
+	case game.DecisionDraft:
+		move.Roles = optionRoles(view)[:view.Keep]
+	}
+
+	return move
+}
+
+func hiddenRoles(view game.PlayerView) []string {
+	roles := []string{}
+	for _, influence := range view.Hand {
+		if !influence.Revealed {
+			roles = append(roles, influence.Role)
+		}
+	}
+	return roles
+}
+
+func optionRoles(view game.PlayerView) []string {
+	roles := make([]string, 0, len(view.Options))
+	for _, influence := range view.Options {
+		roles = append(roles, influence.Role)
+	}
+	return roles
+}
+
+// exchangePool is every card the bot may keep when exchanging.
+func exchangePool(view game.PlayerView) []string {
+	return append(optionRoles(view), hiddenRoles(view)...)
+}
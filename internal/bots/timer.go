@@ -0,0 +1,144 @@
+package bots
+
+import (
+	"context"
+	"influence_game/internal/game"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+/*
+TurnTimer enforces the room's TurnTimerSeconds. Every move re-arms the game's
+clock; when it runs out with nothing played, each human still holding a
+decision has the fallback move made for them, just as a bot whose strategy
+failed would.
+*/
+type TurnTimer struct {
+	store *game.Store
+	unit  time.Duration
+
+	mu     sync.Mutex
+	timers map[string]*armedTimer
+}
+
+type armedTimer struct {
+	timer  *time.Timer
+	marker int
+}
+
+func NewTurnTimer(store *game.Store) *TurnTimer {
+	return &TurnTimer{
+		store:  store,
+		unit:   time.Second,
+		timers: map[string]*armedTimer{},
+	}
+}
+
+// HandleGameUpdated is meant to be registered with Store.OnGameUpdated.
+func (turnTimer *TurnTimer) HandleGameUpdated(updated *game.Game) {
+	turnTimer.mu.Lock()
+	defer turnTimer.mu.Unlock()
+
+	armed, ok := turnTimer.timers[updated.ID]
+	if !updated.Started || updated.Finished || updated.Settings.TurnTimerSeconds == 0 {
+		if ok {
+			armed.timer.Stop()
+			delete(turnTimer.timers, updated.ID)
+		}
+		return
+	}
+
+	// Only moves restart the clock; chat and language changes do not.
+	marker := len(updated.Commands)
+	if ok {
+		if armed.marker == marker {
+			return
+		}
+		armed.timer.Stop()
+	}
+
+	gameID := updated.ID
+	limit := time.Duration(updated.Settings.TurnTimerSeconds) * turnTimer.unit
+	turnTimer.timers[gameID] = &armedTimer{
+		marker: marker,
+		timer:  time.AfterFunc(limit, func() { turnTimer.expire(gameID, marker) }),
+	}
+}
+
+/*
+Restore arms the clock of every game in play, as timers live in memory and a
+restart would otherwise leave idle seats stalling their games. Each clock
+starts over in full.
+*/
+func (turnTimer *TurnTimer) Restore(ctx context.Context) {
+	err := turnTimer.store.EachGameInPlay(ctx, turnTimer.HandleGameUpdated)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to restore turn timers.")
+	}
+}
+
+type overdueDecision struct {
+	playerID string
+	decision game.Decision
+	actionID string
+}
+
+/*
+expire plays for everyone who was still deciding when the clock ran out. Each
+seat is checked again right before its move, so a player who answered in the
+meantime, or whose decision was settled by someone else's, is left alone.
+*/
+func (turnTimer *TurnTimer) expire(gameID string, marker int) {
+	turnTimer.mu.Lock()
+	if armed, ok := turnTimer.timers[gameID]; ok && armed.marker == marker {
+		delete(turnTimer.timers, gameID)
+	}
+	turnTimer.mu.Unlock()
+
+	current, err := turnTimer.store.GetGame(gameID)
+	if err != nil || current.Finished || len(current.Commands) != marker {
+		return
+	}
+
+	overdue := []overdueDecision{}
+	for _, player := range current.Players {
+		if player.IsBot {
+			continue
+		}
+		if decision, ok := pendingDecision(current, player.ID); ok {
+			overdue = append(overdue, decision)
+		}
+	}
+
+	for _, expected := range overdue {
+		current, err := turnTimer.store.GetGame(gameID)
+		if err != nil || current.Finished {
+			return
+		}
+
+		decision, ok := pendingDecision(current, expected.playerID)
+		if !ok || decision != expected {
+			continue
+		}
+
+		view := game.NewPlayerView(current, expected.playerID)
+		if _, err := turnTimer.store.PlayForSeat(gameID, expected.playerID, FallbackMove(view)); err != nil {
+			log.Error().Err(err).Str("gameID", gameID).Msg("Failed to make timed out move.")
+		}
+	}
+}
+
+func pendingDecision(current *game.Game, playerID string) (overdueDecision, bool) {
+	view := game.NewPlayerView(current, playerID)
+	if view.Decision == game.DecisionNone {
+		return overdueDecision{}, false
+	}
+
+	decision := overdueDecision{playerID: playerID, decision: view.Decision}
+	if current.PendingAction != nil {
+		decision.actionID = current.PendingAction.ID
+	}
+	return decision, true
+}
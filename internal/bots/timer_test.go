@@ -0,0 +1,91 @@
+package bots
+
+import (
+	"context"
+	"influence_game/internal/game"
+	"influence_game/internal/sessions"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTimerStore(t *testing.T) *game.Store {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return game.NewStore(client, sessions.NewVerifier([]byte("secret"), client))
+}
+
+// startTimedGame deals a two-player game with a 30 second turn timer.
+func startTimedGame(t *testing.T, store *game.Store) string {
+	t.Helper()
+
+	seconds := 30
+	admin, err := store.CreateGameRoom("ana", &game.RoomSettingsPatch{TurnTimerSeconds: &seconds}, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := store.Join(admin.Game.JoinCode, "bea", ""); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := store.StartGame(admin.Game.GameID, admin.Token); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return admin.Game.GameID
+}
+
+func TestTurnTimerPlaysForPlayersWhoRunOutTheClock(t *testing.T) {
+	store := newTimerStore(t)
+
+	turnTimer := NewTurnTimer(store)
+	turnTimer.unit = time.Hour
+	store.OnGameUpdated(turnTimer.HandleGameUpdated)
+
+	gameID := startTimedGame(t, store)
+
+	started, _ := store.GetGame(gameID)
+	marker := len(started.Commands)
+	turnTimer.mu.Lock()
+	armed, ok := turnTimer.timers[started.ID]
+	turnTimer.mu.Unlock()
+	if !ok || armed.marker != marker {
+		t.Fatalf("expected the clock to be armed at move %d", marker)
+	}
+
+	turnTimer.expire(started.ID, marker)
+
+	played, _ := store.GetGame(started.ID)
+	if len(played.Commands) == marker {
+		t.Fatalf("expected a move to be made for the idle player")
+	}
+
+	// A clock that ran out before the last move has nothing left to do.
+	turnTimer.expire(started.ID, marker)
+
+	again, _ := store.GetGame(started.ID)
+	if len(again.Commands) != len(played.Commands) {
+		t.Fatalf("expected a stale clock to leave the game alone")
+	}
+}
+
+func TestTurnTimerIsRestoredForGamesInPlay(t *testing.T) {
+	store := newTimerStore(t)
+
+	gameID := startTimedGame(t, store)
+
+	// A fresh timer, as after a restart, knows nothing until restored.
+	turnTimer := NewTurnTimer(store)
+	turnTimer.unit = time.Hour
+	turnTimer.Restore(context.Background())
+
+	turnTimer.mu.Lock()
+	_, ok := turnTimer.timers[gameID]
+	turnTimer.mu.Unlock()
+	if !ok {
+		t.Fatal("expected the clock of the game in play to be armed again")
+	}
+}
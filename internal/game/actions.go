@@ -0,0 +1,307 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var actionTypes = map[string]ActionType{
+	"income": {
+		name:        "income",
+		isImmediate: true,
+	},
+	"foreign_aid": {
+		name:        "foreign_aid",
+		isBlockable: true,
+	},
+	"coup": {
+		name:           "coup",
+		cost:           CoupCost,
+		isImmediate:    true,
+		requiresTarget: true,
+	},
+	"tax": {
+		name:          "tax",
+		isContestable: true,
+	},
+	"assassinate": {
+		name:             "assassinate",
+		cost:             AssassinateCost,
+		isBlockable:      true,
+		isContestable:    true,
+		requiresTarget:   true,
+		onlyTargetBlocks: true,
+	},
+	"steal": {
+		name:             "steal",
+		isBlockable:      true,
+		isContestable:    true,
+		requiresTarget:   true,
+		onlyTargetBlocks: true,
+	},
+	"exchange": {
+		name:          "exchange",
+		isContestable: true,
+	},
+	"examine": {
+		name:           "examine",
+		isContestable:  true,
+		requiresTarget: true,
+	},
+	"convert": {
+		name:           "convert",
+		cost:           ConvertSelfCost,
+		isImmediate:    true,
+		optionalTarget: true,
+		variant:        VariantReformation,
+	},
+	"embezzle": {
+		name:            "embezzle",
+		isContestable:   true,
+		invertedClaimOf: "tax",
+		variant:         VariantReformation,
+	},
+}
+
+/*
+declaration is a validated action request: everything declareAction needs to
+charge the actor and open the response window.
+*/
+type declaration struct {
+	actionType  ActionType
+	claimedRole string
+	target      *Player
+	cost        int
+}
+
+func declareAction(
+	game *Game,
+	actor *Player,
+	action DeclareActionPayload,
+	events *eventLog,
+) (DeclareActionPayload, error) {
+
+	checked, err := checkDeclaration(game, actor, action)
+	if err != nil {
+		return DeclareActionPayload{}, err
+	}
+
+	recordCommand(game, Command{
+		Type:     CommandDeclare,
+		PlayerID: actor.ID,
+		Action: &DeclareActionPayload{
+			ActionName:     action.ActionName,
+			TargetPlayerID: action.TargetPlayerID,
+			ClaimedRole:    action.ClaimedRole,
+		},
+	})
+
+	actionType := checked.actionType
+	claimedRole := checked.claimedRole
+	target := checked.target
+
+	actor.Coins -= checked.cost
+	if actionType.name == "convert" {
+		game.TreasuryReserve += checked.cost
+	}
+
+	pending := &PendingAction{
+		ID:              uuid.New().String(),
+		ActorID:         actor.ID,
+		ActionName:      actionType.name,
+		ClaimedRole:     claimedRole,
+		InvertedClaim:   actionType.invertedClaimOf != "",
+		CreatedAt:       time.Now().UTC(),
+		Status:          "declared",
+		PassedPlayerIDs: []string{},
+	}
+
+	payload := DeclareActionPayload{
+		ID:                  pending.ID,
+		ActionName:          actionType.name,
+		ActorPlayerID:       actor.ID,
+		ActorPlayerNickname: actor.Nickname,
+		RequiresTarget:      actionType.requiresTarget,
+		IsImmediate:         actionType.isImmediate,
+		BlockableRoles:      blockingRolesFor(game, actionType),
+		IsContestable:       actionType.isContestable,
+		ClaimedRole:         claimedRole,
+		InvertedClaim:       actionType.invertedClaimOf != "",
+	}
+
+	if target != nil {
+		pending.TargetID = &target.ID
+		payload.TargetPlayerID = &target.ID
+		payload.TargetPlayerNickname = &target.Nickname
+	}
+
+	recordDeclaration(game, pending, actor)
+
+	if actionType.isImmediate {
+		resolveAction(game, pending, events)
+		finishTurn(game)
+		return payload, nil
+	}
+
+	game.PendingAction = pending
+
+	if len(eligibleResponders(game)) == 0 {
+		closeResponseWindow(game, events)
+	}
+
+	return payload, nil
+}
+
+/*
+checkDeclaration applies every rule that decides whether the actor may
+declare the action, without changing the game.
+*/
+func checkDeclaration(game *Game, actor *Player, action DeclareActionPayload) (declaration, error) {
+	actionType, ok := actionTypes[action.ActionName]
+	if !ok {
+		return declaration{}, ErrInvalidActionName
+	}
+
+	if !game.Settings.AllowsAction(actionType.name) {
+		return declaration{}, ErrActionNotAllowed
+	}
+	if actionType.variant != "" && !game.Settings.HasVariant(actionType.variant) {
+		return declaration{}, ErrActionNotAllowed
+	}
+
+	claimedRole := claimedRoleFor(game, actionType, action.ClaimedRole)
+	if actionType.isContestable && claimedRole == "" {
+		return declaration{}, ErrActionNotAllowed
+	}
+
+	if actor.Coins >= MustCoupCoins && actionType.name != "coup" {
+		return declaration{}, ErrMustCoup
+	}
+
+	target, err := resolveActionTarget(game, actor, actionType, action.TargetPlayerID)
+	if err != nil {
+		return declaration{}, err
+	}
+
+	cost := actionCost(actionType, actor, target)
+	if actor.Coins < cost {
+		return declaration{}, ErrNotEnoughCoins
+	}
+
+	return declaration{
+		actionType:  actionType,
+		claimedRole: claimedRole,
+		target:      target,
+		cost:        cost,
+	}, nil
+}
+
+func resolveActionTarget(
+	game *Game,
+	actor *Player,
+	actionType ActionType,
+	targetPlayerID *string,
+) (*Player, error) {
+
+	if !actionType.requiresTarget && !actionType.optionalTarget {
+		return nil, nil
+	}
+
+	if targetPlayerID == nil {
+		if actionType.requiresTarget {
+			return nil, ErrTargetRequired
+		}
+		return nil, nil
+	}
+
+	target, err := findPlayerByID(game, *targetPlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !target.Alive {
+		return nil, ErrTargetPlayerIsDead
+	}
+
+	if actionType.optionalTarget {
+		return target, nil
+	}
+
+	if target.ID == actor.ID {
+		return nil, ErrCannotTargetSelf
+	}
+
+	if violatesFaction(game, actor, target) {
+		return nil, ErrSameFactionTarget
+	}
+
+	return target, nil
+}
+
+func actionCost(actionType ActionType, actor *Player, target *Player) int {
+	if actionType.name == "convert" {
+		return convertCost(actor, target)
+	}
+	return actionType.cost
+}
+
+func resolveAction(game *Game, pending *PendingAction, events *eventLog) {
+	actor, err := findPlayerByID(game, pending.ActorID)
+	if err != nil {
+		return
+	}
+
+	var target *Player
+	if pending.TargetID != nil {
+		target, _ = findPlayerByID(game, *pending.TargetID)
+	}
+
+	switch pending.ActionName {
+	case "income":
+		actor.Coins++
+
+	case "foreign_aid":
+		actor.Coins += 2
+
+	case "tax":
+		actor.Coins += 3
+
+	case "coup":
+		loseInfluence(game, target, "coup", events)
+
+	case "assassinate":
+		if target != nil && target.Alive {
+			loseInfluence(game, target, "assassinate", events)
+		}
+
+	case "steal":
+		if target != nil {
+			stolen := min(2, target.Coins)
+			target.Coins -= stolen
+			actor.Coins += stolen
+		}
+
+	case "exchange":
+		startExchange(game, actor, pending.ClaimedRole, events)
+
+	case "examine":
+		startExamination(game, actor, target)
+
+	case "convert":
+		convertAllegiance(actor, target)
+
+	case "embezzle":
+		actor.Coins += game.TreasuryReserve
+		game.TreasuryReserve = 0
+	}
+
+	recordOutcome(game, pending.ID, "resolved")
+
+	events.add("action_resolved", map[string]any{
+		"actionId":   pending.ID,
+		"actionName": pending.ActionName,
+		"actorId":    pending.ActorID,
+		"targetId":   pending.TargetID,
+	})
+}
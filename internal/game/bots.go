@@ -0,0 +1,78 @@
+package game
+
+import (
+	"context"
+	"fmt"
+)
+
+/*
+AddBot seats a server-side bot in the lobby. The bot runner plays it through
+the same Store methods as a human, with session tokens it mints for the bot;
+the game only remembers which strategy to use.
+*/
+func (store *Store) AddBot(
+	gameID string,
+	strategy string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	playerID := session.PlayerID
+
+	bot := buildNewPlayer("", 0)
+	bot.IsBot = true
+	bot.Strategy = strategy
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		if game.Started {
+			return ErrAlreadyStarted
+		}
+		if game.AdminID != playerID {
+			return ErrOnlyAdminCanAddBots
+		}
+		if len(game.Players) >= game.Settings.MaxPlayers {
+			return ErrRoomFull
+		}
+
+		bot.Nickname = nextBotNickname(game)
+		bot.Coins = game.Settings.StartingCoins
+		game.Players = append(game.Players, bot)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	store.syncPublicLobby(ctx, game)
+
+	BroadcastEvent(
+		ProjectPublicGameState(game),
+		"player_joined",
+		map[string]any{
+			"newPlayer": bot,
+		},
+	)
+
+	return ProjectPublicGameState(game), nil
+}
+
+func nextBotNickname(game *Game) string {
+	taken := map[string]bool{}
+	for _, p := range game.Players {
+		taken[p.Nickname] = true
+	}
+
+	for n := 1; ; n++ {
+		nickname := fmt.Sprintf("bot-%d", n)
+		if !taken[nickname] {
+			return nickname
+		}
+	}
+}
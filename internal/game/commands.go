@@ -0,0 +1,35 @@
+package game
+
+import "time"
+
+const (
+	CommandDeclare          = "declare"
+	CommandRespond          = "respond"
+	CommandLoseInfluence    = "lose_influence"
+	CommandExchange         = "exchange"
+	CommandShowInfluence    = "show_influence"
+	CommandExamination      = "examination"
+	CommandSelectInfluences = "select_influences"
+)
+
+/*
+Command is one move a player made, as they made it. Together with the seed,
+the commands replay the game exactly; see Replay.
+*/
+type Command struct {
+	Type     string                `json:"type"`
+	PlayerID string                `json:"playerId"`
+	Action   *DeclareActionPayload `json:"action,omitempty"`
+	Response *ActionResponse       `json:"response,omitempty"`
+
+	// Roles kept in a draft or an exchange, or the one role given up or shown.
+	Roles     []string  `json:"roles,omitempty"`
+	ForceSwap bool      `json:"forceSwap,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// recordCommand logs a move once the rules have accepted it.
+func recordCommand(game *Game, command Command) {
+	command.At = time.Now().UTC()
+	game.Commands = append(game.Commands, command)
+}
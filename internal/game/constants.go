@@ -5,4 +5,38 @@ import "time"
 const (
 	SessionDuration = 24 * time.Hour
 	JoinCodeTTL     = 2 * time.Hour
+	RejoinCodeTTL   = 24 * time.Hour
+)
+
+// How long a game survives in Redis without being written to; see gameTTL.
+const (
+	LobbyTTL        = JoinCodeTTL
+	ActiveGameTTL   = 24 * time.Hour
+	FinishedGameTTL = time.Hour
+
+	JanitorInterval = 10 * time.Minute
+)
+
+// How long an instance trusts its copy of a player's saved language.
+const LanguageCacheTTL = time.Minute
+
+const (
+	MinPlayers           = 2
+	MaxPlayers           = 10
+	DefaultMaxPlayers    = 6
+	DefaultStartingCoins = 2
+	MaxStartingCoins     = 10
+	MaxTurnTimerSeconds  = 300
+	MinCopiesPerRole     = 3
+	MaxCopiesPerRole     = 5
+	MaxRolesPerPack      = 10
+	TwoPlayerDraftSize   = 5
+)
+
+const (
+	CoupCost         = 7
+	AssassinateCost  = 3
+	MustCoupCoins    = 10
+	ConvertSelfCost  = 1
+	ConvertOtherCost = 2
 )
@@ -0,0 +1,212 @@
+package game
+
+import "sort"
+
+type Decision string
+
+const (
+	DecisionNone          Decision = ""
+	DecisionDraft         Decision = "draft"
+	DecisionLoseInfluence Decision = "lose_influence"
+	DecisionExchange      Decision = "exchange"
+	DecisionShowInfluence Decision = "show_influence"
+	DecisionExamination   Decision = "examination"
+	DecisionRespond       Decision = "respond"
+	DecisionDeclare       Decision = "declare"
+)
+
+/*
+PlayerView is everything one player is allowed to know when it is their move:
+the public state, their own hand, any private cards on offer and the moves
+the rules currently accept. Bots decide from this and nothing else.
+*/
+type PlayerView struct {
+	PlayerID      string
+	Decision      Decision
+	State         *PublicGameState
+	Hand          []Influence
+	Roles         []RoleDefinition
+	CopiesPerRole int
+
+	// Cards on offer during a draft or an exchange, and how many to keep.
+	Options []Influence
+	Keep    int
+
+	// The card seen during an examination.
+	Examined *Influence
+
+	LegalActions   []DeclareActionPayload
+	LegalResponses []ActionResponse
+}
+
+/*
+Move answers whichever decision it names, as the server makes them on a
+seat's behalf: bots, and the turn timer for players who ran out of time.
+*/
+type Move struct {
+	Decision  Decision
+	Action    DeclareActionPayload
+	ActionID  string // the pending action a response answers
+	Response  ActionResponse
+	Roles     []string // draft picks, exchange keeps or the single role to lose or show
+	ForceSwap bool
+}
+
+/*
+NextDecision returns what the rules are waiting on from the player, if
+anything. Private choices come first since they block the table.
+*/
+func NextDecision(game *Game, playerID string) Decision {
+	if !game.Started || game.Finished {
+		return DecisionNone
+	}
+
+	player, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return DecisionNone
+	}
+
+	if _, ok := game.Drafts[playerID]; ok {
+		return DecisionDraft
+	}
+	if countPendingLosses(game, playerID) > 0 {
+		return DecisionLoseInfluence
+	}
+	if !player.Alive {
+		return DecisionNone
+	}
+	if game.PendingExchange != nil && game.PendingExchange.PlayerID == playerID {
+		return DecisionExchange
+	}
+	if examination := game.PendingExamination; examination != nil {
+		if !examination.Shown && examination.TargetID == playerID {
+			return DecisionShowInfluence
+		}
+		if examination.Shown && examination.ExaminerID == playerID {
+			return DecisionExamination
+		}
+	}
+	if contains(eligibleResponders(game), playerID) {
+		return DecisionRespond
+	}
+	if _, err := validateActionContext(game, playerID); err == nil {
+		return DecisionDeclare
+	}
+
+	return DecisionNone
+}
+
+func NewPlayerView(game *Game, playerID string) PlayerView {
+	pack := rolePackFor(game)
+
+	view := PlayerView{
+		PlayerID:      playerID,
+		Decision:      NextDecision(game, playerID),
+		State:         ProjectPublicGameState(game),
+		Roles:         append([]RoleDefinition{}, pack.Roles...),
+		CopiesPerRole: game.Settings.DeckCopies(len(game.Players)),
+	}
+
+	player, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return view
+	}
+	view.Hand = append([]Influence{}, player.Influences...)
+
+	switch view.Decision {
+	case DecisionDraft:
+		view.Options = append([]Influence{}, game.Drafts[playerID]...)
+		view.Keep = 2
+
+	case DecisionExchange:
+		view.Options = append([]Influence{}, game.PendingExchange.Drawn...)
+		view.Keep = countUnrevealed(player)
+
+	case DecisionExamination:
+		target, err := findPlayerByID(game, game.PendingExamination.TargetID)
+		if err == nil {
+			examined := target.Influences[game.PendingExamination.CardIndex]
+			view.Examined = &examined
+		}
+
+	case DecisionRespond:
+		view.LegalResponses = legalResponses(game, player)
+
+	case DecisionDeclare:
+		view.LegalActions = legalActions(game, player)
+	}
+
+	return view
+}
+
+// legalActions lists every declaration the actor could make right now.
+func legalActions(game *Game, actor *Player) []DeclareActionPayload {
+	names := make([]string, 0, len(actionTypes))
+	for name := range actionTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pack := rolePackFor(game)
+	actions := []DeclareActionPayload{}
+
+	for _, name := range names {
+		actionType := actionTypes[name]
+
+		claims := []string{""}
+		if actionType.isContestable && actionType.invertedClaimOf == "" {
+			claims = nil
+			for _, role := range pack.rolesGranting(name) {
+				claims = append(claims, role.Name)
+			}
+		}
+
+		targets := []*string{nil}
+		if actionType.requiresTarget || actionType.optionalTarget {
+			if actionType.requiresTarget {
+				targets = nil
+			}
+			for _, p := range game.Players {
+				if p.ID != actor.ID {
+					targetID := p.ID
+					targets = append(targets, &targetID)
+				}
+			}
+		}
+
+		for _, claim := range claims {
+			for _, target := range targets {
+				action := DeclareActionPayload{
+					ActionName:     name,
+					TargetPlayerID: target,
+					ClaimedRole:    claim,
+				}
+				if _, err := checkDeclaration(game, actor, action); err == nil {
+					actions = append(actions, action)
+				}
+			}
+		}
+	}
+
+	return actions
+}
+
+func legalResponses(game *Game, player *Player) []ActionResponse {
+	responses := []ActionResponse{{Response: ResponsePass}}
+
+	if canChallenge(game, player.ID) {
+		responses = append(responses, ActionResponse{Response: ResponseChallenge})
+	}
+
+	if canBlock(game, player.ID) {
+		actor, err := findPlayerByID(game, game.PendingAction.ActorID)
+		if err == nil && !violatesFaction(game, player, actor) {
+			actionType := actionTypes[game.PendingAction.ActionName]
+			for _, role := range blockingRolesFor(game, actionType) {
+				responses = append(responses, ActionResponse{Response: ResponseBlock, Role: role})
+			}
+		}
+	}
+
+	return responses
+}
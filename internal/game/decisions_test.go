@@ -0,0 +1,59 @@
+package game
+
+import "testing"
+
+func TestLegalActionsFollowCoinsAndTargets(t *testing.T) {
+	game := newTestGame(t, nil,
+		[]string{"Duke", "Captain"},
+		[]string{"Contessa", "Assassin"},
+		[]string{"Ambassador", "Duke"},
+	)
+	actor := game.Players[0]
+
+	view := NewPlayerView(game, actor.ID)
+	if view.Decision != DecisionDeclare {
+		t.Fatalf("expected declare decision, got %q", view.Decision)
+	}
+
+	counts := map[string]int{}
+	for _, action := range view.LegalActions {
+		counts[action.ActionName]++
+	}
+	if counts["income"] != 1 || counts["steal"] != 2 || counts["assassinate"] != 0 || counts["coup"] != 0 {
+		t.Fatalf("unexpected legal actions %v", counts)
+	}
+
+	actor.Coins = MustCoupCoins
+	view = NewPlayerView(game, actor.ID)
+	for _, action := range view.LegalActions {
+		if action.ActionName != "coup" {
+			t.Fatalf("expected only coups with %d coins, got %s", MustCoupCoins, action.ActionName)
+		}
+	}
+	if len(view.LegalActions) != 2 {
+		t.Fatalf("expected a coup per opponent, got %d", len(view.LegalActions))
+	}
+}
+
+func TestNextDecisionOpensResponsesToOthers(t *testing.T) {
+	game := newTestGame(t, nil,
+		[]string{"Duke", "Captain"},
+		[]string{"Contessa", "Assassin"},
+	)
+	actor, target := game.Players[0], game.Players[1]
+
+	declare(t, game, "steal", target)
+
+	if decision := NextDecision(game, actor.ID); decision != DecisionNone {
+		t.Fatalf("expected actor to wait, got %q", decision)
+	}
+
+	view := NewPlayerView(game, target.ID)
+	if view.Decision != DecisionRespond {
+		t.Fatalf("expected respond decision, got %q", view.Decision)
+	}
+	// pass, challenge and a block for each role blocking steal
+	if len(view.LegalResponses) != 4 {
+		t.Fatalf("unexpected responses %v", view.LegalResponses)
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+func selectDraftInfluences(game *Game, playerID string, roles []string) error {
+	if !game.Started || game.Finished {
+		return ErrNotStarted
+	}
+
+	dealt, ok := game.Drafts[playerID]
+	if !ok {
+		return ErrNoDraftPending
+	}
+
+	player, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return err
+	}
+
+	if len(roles) != 2 {
+		return ErrInvalidDraftSelection
+	}
+
+	remaining := append([]Influence{}, dealt...)
+	chosen := make([]Influence, 0, len(roles))
+
+	for _, role := range roles {
+		index := -1
+		for i, influence := range remaining {
+			if influence.Role == role {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return ErrInvalidDraftSelection
+		}
+
+		chosen = append(chosen, remaining[index])
+		remaining = append(remaining[:index], remaining[index+1:]...)
+	}
+
+	recordCommand(game, Command{
+		Type:     CommandSelectInfluences,
+		PlayerID: playerID,
+		Roles:    append([]string{}, roles...),
+	})
+
+	player.Influences = chosen
+	game.Deck = append(game.Deck, remaining...)
+	delete(game.Drafts, playerID)
+
+	if len(game.Drafts) == 0 {
+		game.Drafts = nil
+		shuffleDeck(game)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package game
+
+import "testing"
+
+func newTwoPlayerGame(t *testing.T) *Game {
+	t.Helper()
+
+	game := newLobby(2)
+	game.Settings.MaxPlayers = 2
+	game.Settings.Variants = []string{VariantTwoPlayer}
+
+	if err := SetupNewGame(game); err != nil {
+		t.Fatalf("unexpected setup error %v", err)
+	}
+
+	return game
+}
+
+func TestTwoPlayerSetupDealsDrafts(t *testing.T) {
+	game := newTwoPlayerGame(t)
+
+	for i, player := range game.Players {
+		if len(game.Drafts[player.ID]) != TwoPlayerDraftSize {
+			t.Fatalf("expected %d drafted cards, got %d", TwoPlayerDraftSize, len(game.Drafts[player.ID]))
+		}
+
+		wantCoins := DefaultStartingCoins
+		if i == game.TurnIndex {
+			wantCoins--
+		}
+		if player.Coins != wantCoins {
+			t.Fatalf("player %d: expected %d coins, got %d", i, wantCoins, player.Coins)
+		}
+	}
+
+	if _, err := validateActionContext(game, game.Players[game.TurnIndex].ID); err != ErrDraftInProgress {
+		t.Fatalf("expected %v while drafting, got %v", ErrDraftInProgress, err)
+	}
+}
+
+func TestDraftSelectionReturnsUnchosenCards(t *testing.T) {
+	game := newTwoPlayerGame(t)
+	deckBefore := len(game.Deck)
+
+	for _, player := range game.Players {
+		dealt := game.Drafts[player.ID]
+		roles := []string{dealt[0].Role, dealt[1].Role}
+
+		if err := selectDraftInfluences(game, player.ID, roles); err != nil {
+			t.Fatalf("unexpected selection error %v", err)
+		}
+		if len(player.Influences) != 2 {
+			t.Fatalf("expected 2 influences, got %d", len(player.Influences))
+		}
+	}
+
+	if game.Drafts != nil {
+		t.Fatalf("expected drafts to be cleared")
+	}
+	if len(game.Deck) != deckBefore+2*(TwoPlayerDraftSize-2) {
+		t.Fatalf("expected unchosen cards back in the deck, got %d cards", len(game.Deck))
+	}
+}
+
+func TestDraftSelectionRejectsCardsNotDealt(t *testing.T) {
+	game := newTwoPlayerGame(t)
+	player := game.Players[0]
+
+	roles := []string{"Jester", "Jester"}
+	if err := selectDraftInfluences(game, player.ID, roles); err != ErrInvalidDraftSelection {
+		t.Fatalf("expected %v, got %v", ErrInvalidDraftSelection, err)
+	}
+}
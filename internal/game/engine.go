@@ -0,0 +1,91 @@
+package game
+
+import "fmt"
+
+/*
+The functions below run the rules on an in-memory game, without Redis or
+sessions. The Store wraps the same rules in a lock per request; these are for
+callers that own the game outright, like the simulator. Each returns the
+events the move produced.
+*/
+
+// NewLocalGame seats the players and deals a game from the given seed.
+func NewLocalGame(playerCount int, settings RoomSettings, seed int64) (*Game, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	game := &Game{
+		ID:       fmt.Sprintf("local-%d", seed),
+		Settings: settings,
+		Deck:     []Influence{},
+		Seed:     seed,
+	}
+
+	for i := range playerCount {
+		player := buildNewPlayer(fmt.Sprintf("player-%d", i+1), settings.StartingCoins)
+		player.ID = fmt.Sprintf("p%d", i+1)
+		game.Players = append(game.Players, player)
+	}
+	game.AdminID = game.Players[0].ID
+
+	if err := SetupNewGame(game); err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+func Declare(game *Game, playerID string, action DeclareActionPayload) ([]GameEvent, error) {
+	var events eventLog
+
+	actor, err := validateActionContext(game, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := declareAction(game, actor, action, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func Respond(game *Game, playerID string, response ActionResponse) ([]GameEvent, error) {
+	var events eventLog
+
+	if game.PendingAction == nil {
+		return nil, ErrNoPendingAction
+	}
+
+	err := respondToAction(game, playerID, game.PendingAction.ID, response, &events)
+	return events, err
+}
+
+func LoseInfluence(game *Game, playerID string, role string) ([]GameEvent, error) {
+	var events eventLog
+	err := chooseInfluenceLoss(game, playerID, role, &events)
+	return events, err
+}
+
+func CompleteExchange(game *Game, playerID string, keep []string) ([]GameEvent, error) {
+	var events eventLog
+	err := completeExchange(game, playerID, keep, &events)
+	return events, err
+}
+
+func ShowInfluence(game *Game, playerID string, role string) ([]GameEvent, error) {
+	var events eventLog
+	err := showInfluence(game, playerID, role, &events)
+	return events, err
+}
+
+func CompleteExamination(game *Game, playerID string, forceSwap bool) ([]GameEvent, error) {
+	var events eventLog
+	err := completeExamination(game, playerID, forceSwap, &events)
+	return events, err
+}
+
+func SelectInfluences(game *Game, playerID string, roles []string) ([]GameEvent, error) {
+	err := selectDraftInfluences(game, playerID, roles)
+	return nil, err
+}
@@ -0,0 +1,250 @@
+package game
+
+import "testing"
+
+// newTestGame builds a started game with fixed hands so rule outcomes are
+// predictable. Each hand lists the roles dealt to one player.
+func newTestGame(t *testing.T, variants []string, hands ...[]string) *Game {
+	t.Helper()
+
+	game := newLobby(len(hands))
+	game.Settings.Variants = variants
+	game.Started = true
+	game.TurnIndex = 0
+	game.Deck = NewBaseDeck()
+
+	for i, hand := range hands {
+		player := game.Players[i]
+		player.Alive = true
+		player.Coins = DefaultStartingCoins
+		for _, role := range hand {
+			player.Influences = append(player.Influences, Influence{Role: role})
+		}
+	}
+
+	return game
+}
+
+func declare(t *testing.T, game *Game, actionName string, target *Player) DeclareActionPayload {
+	t.Helper()
+
+	actor, err := validateActionContext(game, game.Players[game.TurnIndex].ID)
+	if err != nil {
+		t.Fatalf("unexpected context error %v", err)
+	}
+
+	action := DeclareActionPayload{ActionName: actionName}
+	if target != nil {
+		action.TargetPlayerID = &target.ID
+	}
+
+	var events eventLog
+	payload, err := declareAction(game, actor, action, &events)
+	if err != nil {
+		t.Fatalf("unexpected declare error %v", err)
+	}
+	return payload
+}
+
+func respond(t *testing.T, game *Game, player *Player, response string, role string) {
+	t.Helper()
+
+	var events eventLog
+	err := respondToAction(game, player.ID, game.PendingAction.ID, ActionResponse{
+		Response: response,
+		Role:     role,
+	}, &events)
+	if err != nil {
+		t.Fatalf("unexpected %s error %v", response, err)
+	}
+}
+
+func TestUnchallengedTaxResolves(t *testing.T) {
+	game := newTestGame(t, nil, []string{"Captain", "Contessa"}, []string{"Duke", "Duke"}, []string{"Duke", "Captain"})
+	actor := game.Players[0]
+
+	declare(t, game, "tax", nil)
+	respond(t, game, game.Players[1], ResponsePass, "")
+	respond(t, game, game.Players[2], ResponsePass, "")
+
+	if actor.Coins != 5 {
+		t.Fatalf("expected 5 coins, got %d", actor.Coins)
+	}
+	if game.PendingAction != nil || game.TurnIndex != 1 {
+		t.Fatalf("expected the turn to move on")
+	}
+}
+
+func TestChallengedBluffCancelsActionAndCostsInfluence(t *testing.T) {
+	game := newTestGame(t, nil, []string{"Captain", "Contessa"}, []string{"Duke", "Duke"})
+	actor := game.Players[0]
+
+	declare(t, game, "tax", nil)
+	respond(t, game, game.Players[1], ResponseChallenge, "")
+
+	if actor.Coins != DefaultStartingCoins {
+		t.Fatalf("expected the tax to be canceled, got %d coins", actor.Coins)
+	}
+	if len(game.PendingLosses) != 1 || game.PendingLosses[0].PlayerID != actor.ID {
+		t.Fatalf("expected the bluffer to owe an influence, got %+v", game.PendingLosses)
+	}
+
+	var events eventLog
+	if err := chooseInfluenceLoss(game, actor.ID, "Contessa", &events); err != nil {
+		t.Fatalf("unexpected loss error %v", err)
+	}
+	if !actor.Influences[1].Revealed || len(game.PendingLosses) != 0 {
+		t.Fatalf("expected the chosen card to be revealed")
+	}
+}
+
+func TestBlockedForeignAidIsCanceled(t *testing.T) {
+	game := newTestGame(t, nil, []string{"Captain", "Contessa"}, []string{"Captain", "Captain"})
+	actor := game.Players[0]
+
+	declare(t, game, "foreign_aid", nil)
+	respond(t, game, game.Players[1], ResponseBlock, "Duke")
+	respond(t, game, actor, ResponsePass, "")
+
+	if actor.Coins != DefaultStartingCoins {
+		t.Fatalf("expected foreign aid to be blocked, got %d coins", actor.Coins)
+	}
+	if game.PendingAction != nil {
+		t.Fatalf("expected the action to be closed")
+	}
+}
+
+func TestAssassinationEliminatesLastInfluenceAndFinishesGame(t *testing.T) {
+	game := newTestGame(t, nil, []string{"Assassin", "Duke"}, []string{"Captain"})
+	game.Players[1].Influences = append(game.Players[1].Influences, Influence{Role: "Duke", Revealed: true})
+	game.Players[0].Coins = 3
+
+	declare(t, game, "assassinate", game.Players[1])
+	respond(t, game, game.Players[1], ResponsePass, "")
+
+	if game.Players[1].Alive {
+		t.Fatalf("expected the target to be eliminated")
+	}
+	if !game.Finished || game.WinnerID == nil || *game.WinnerID != game.Players[0].ID {
+		t.Fatalf("expected the assassin to win")
+	}
+}
+
+func TestMustCoupWithTenCoins(t *testing.T) {
+	game := newTestGame(t, nil, []string{"Duke", "Duke"}, []string{"Captain", "Captain"})
+	game.Players[0].Coins = MustCoupCoins
+
+	var events eventLog
+	_, err := declareAction(game, game.Players[0], DeclareActionPayload{ActionName: "income"}, &events)
+	if err != ErrMustCoup {
+		t.Fatalf("expected %v, got %v", ErrMustCoup, err)
+	}
+}
+
+func TestReformationProtectsSameFaction(t *testing.T) {
+	game := newTestGame(t, []string{VariantReformation}, []string{"Duke", "Duke"}, []string{"Captain", "Captain"}, []string{"Contessa", "Contessa"})
+	assignAllegiances(game)
+	game.Players[0].Coins = CoupCost
+
+	if game.Players[0].Allegiance != game.Players[2].Allegiance {
+		t.Fatalf("expected seats 0 and 2 to share a faction")
+	}
+
+	var events eventLog
+	_, err := declareAction(game, game.Players[0], DeclareActionPayload{
+		ActionName:     "coup",
+		TargetPlayerID: &game.Players[2].ID,
+	}, &events)
+	if err != ErrSameFactionTarget {
+		t.Fatalf("expected %v, got %v", ErrSameFactionTarget, err)
+	}
+}
+
+func TestReformationConvertFundsTreasuryAndEmbezzleDrainsIt(t *testing.T) {
+	game := newTestGame(t, []string{VariantReformation}, []string{"Captain", "Contessa"}, []string{"Duke", "Captain"})
+	assignAllegiances(game)
+	actor := game.Players[0]
+
+	declare(t, game, "convert", game.Players[1])
+	if game.TreasuryReserve != ConvertOtherCost || game.Players[1].Allegiance != actor.Allegiance {
+		t.Fatalf("expected the conversion to be paid into the treasury")
+	}
+
+	declare(t, game, "income", nil)
+	declare(t, game, "embezzle", nil)
+	respond(t, game, game.Players[1], ResponsePass, "")
+
+	if actor.Coins != DefaultStartingCoins || game.TreasuryReserve != 0 {
+		t.Fatalf("expected embezzle to take the treasury, got %d coins and %d in reserve", actor.Coins, game.TreasuryReserve)
+	}
+}
+
+func TestEmbezzleChallengeIsInverted(t *testing.T) {
+	game := newTestGame(t, []string{VariantReformation}, []string{"Duke", "Contessa"}, []string{"Captain"})
+	assignAllegiances(game)
+	game.TreasuryReserve = 4
+
+	declare(t, game, "embezzle", nil)
+	respond(t, game, game.Players[1], ResponseChallenge, "")
+
+	if game.TreasuryReserve != 4 {
+		t.Fatalf("expected the embezzlement to fail")
+	}
+	if len(game.PendingLosses) != 1 || game.PendingLosses[0].PlayerID != game.Players[0].ID {
+		t.Fatalf("expected the Duke holder to lose the challenge")
+	}
+}
+
+func TestLosingChallengeCanEndTheGame(t *testing.T) {
+	game := newTestGame(t, nil,
+		[]string{"Duke", "Captain"},
+		[]string{"Contessa", "Assassin"},
+	)
+	actor, challenger := game.Players[0], game.Players[1]
+	challenger.Influences[1].Revealed = true
+
+	declare(t, game, "tax", nil)
+	respond(t, game, challenger, ResponseChallenge, "")
+
+	if !game.Finished || game.WinnerID == nil || *game.WinnerID != actor.ID {
+		t.Fatalf("expected %s to win, got %+v", actor.ID, game.WinnerID)
+	}
+	if game.PendingAction != nil {
+		t.Fatal("expected no pending action after the game ended")
+	}
+}
+
+func TestChallengesThatEndTheGameCloseTheAction(t *testing.T) {
+	t.Run("caught bluff", func(t *testing.T) {
+		game := newTestGame(t, nil, []string{"Captain", "Duke"}, []string{"Contessa", "Assassin"})
+		actor, challenger := game.Players[0], game.Players[1]
+		actor.Influences[1].Revealed = true
+
+		declare(t, game, "tax", nil)
+		respond(t, game, challenger, ResponseChallenge, "")
+
+		if !game.Finished || game.WinnerID == nil || *game.WinnerID != challenger.ID {
+			t.Fatalf("expected %s to win, got %+v", challenger.ID, game.WinnerID)
+		}
+		if game.PendingAction != nil || actor.Coins != DefaultStartingCoins {
+			t.Fatalf("expected the tax to be dropped with the game over")
+		}
+	})
+
+	t.Run("upheld block", func(t *testing.T) {
+		game := newTestGame(t, nil, []string{"Captain", "Contessa"}, []string{"Duke", "Assassin"})
+		actor, blocker := game.Players[0], game.Players[1]
+		actor.Influences[1].Revealed = true
+
+		declare(t, game, "foreign_aid", nil)
+		respond(t, game, blocker, ResponseBlock, "Duke")
+		respond(t, game, actor, ResponseChallenge, "")
+
+		if !game.Finished || game.WinnerID == nil || *game.WinnerID != blocker.ID {
+			t.Fatalf("expected %s to win, got %+v", blocker.ID, game.WinnerID)
+		}
+		if game.PendingAction != nil || actor.Coins != DefaultStartingCoins {
+			t.Fatalf("expected foreign aid to be dropped with the game over")
+		}
+	})
+}
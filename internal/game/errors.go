@@ -1,18 +1,168 @@
 package game
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+	"sort"
+)
+
+/*
+Error is a failure reported to API clients. Code is stable, so clients can
+branch on it; Status is the HTTP status it is answered with; the message
+shown to people is looked up in locales/ as "error.<code>".
+*/
+type Error struct {
+	Code   string
+	Status int
+}
+
+func (err *Error) Error() string {
+	return err.Code
+}
+
+var catalogue = map[string]*Error{}
+
+/*
+NewError adds an error to the catalogue. Every package that answers API
+clients declares its errors with it, so codes stay unique across the API.
+*/
+func NewError(code string, status int) *Error {
+	if _, taken := catalogue[code]; taken {
+		panic("duplicate error code " + code)
+	}
+
+	err := &Error{Code: code, Status: status}
+	catalogue[code] = err
+	return err
+}
+
+// Catalogue lists every error declared so far, by code.
+func Catalogue() []*Error {
+	list := make([]*Error, 0, len(catalogue))
+	for _, err := range catalogue {
+		list = append(list, err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Code < list[j].Code
+	})
+	return list
+}
+
+// LookupError finds a catalogued error by its code.
+func LookupError(code string) (*Error, bool) {
+	err, ok := catalogue[code]
+	return err, ok
+}
+
+/*
+AsError finds the catalogued error behind err, wrapped or not. Anything else
+is an unexpected failure and comes back as ErrInternal.
+*/
+func AsError(err error) *Error {
+	var known *Error
+	if errors.As(err, &known) {
+		return known
+	}
+	return ErrInternal
+}
+
+// Requests the API could not read.
+var (
+	ErrInternal             = NewError("internal_error", http.StatusInternalServerError)
+	ErrInvalidJSON          = NewError("invalid_json", http.StatusBadRequest)
+	ErrInvalidRequest       = NewError("invalid_request", http.StatusBadRequest)
+	ErrRequestTooLarge      = NewError("request_too_large", http.StatusRequestEntityTooLarge)
+	ErrMissingAuthorization = NewError("missing_authorization", http.StatusUnauthorized)
+	ErrMissingSocketToken   = NewError("missing_socket_token", http.StatusUnauthorized)
+	ErrUnsupportedVersion   = NewError("unsupported_version", http.StatusBadRequest)
+)
+
+// Rooms, seats and sessions.
+var (
+	ErrGameNotFound          = NewError("game_not_found", http.StatusNotFound)
+	ErrPlayerNotFound        = NewError("player_not_found", http.StatusNotFound)
+	ErrInvalidSession        = NewError("invalid_session", http.StatusUnauthorized)
+	ErrInvalidRejoinCode     = NewError("invalid_rejoin_code", http.StatusUnauthorized)
+	ErrOnlyAdminCanStartGame = NewError("only_admin_can_start_game", http.StatusForbidden)
+	ErrOnlyAdminCanEdit      = NewError("only_admin_can_edit_settings", http.StatusForbidden)
+	ErrOnlyAdminCanAddBots   = NewError("only_admin_can_add_bots", http.StatusForbidden)
+	ErrOnlyAdminCanKick      = NewError("only_admin_can_kick", http.StatusForbidden)
+	ErrCannotKickSelf        = NewError("cannot_kick_self", http.StatusBadRequest)
+	ErrAlreadyStarted        = NewError("game_already_started", http.StatusConflict)
+	ErrNotStarted            = NewError("game_not_started", http.StatusConflict)
+	ErrGameAlreadyFinished   = NewError("game_already_finished", http.StatusConflict)
+	ErrGameNotFinished       = NewError("game_not_finished", http.StatusConflict)
+	ErrPlayerAlreadyJoined   = NewError("nickname_taken", http.StatusConflict)
+	ErrUserAlreadyJoined     = NewError("user_already_joined", http.StatusConflict)
+	ErrRoomFull              = NewError("room_full", http.StatusConflict)
+	ErrNeedAtLeastTwoPlayers = NewError("need_at_least_two_players", http.StatusConflict)
+	ErrTooManyPlayers        = NewError("too_many_players", http.StatusConflict)
+	ErrNotEnoughInfluences   = NewError("not_enough_influences", http.StatusBadRequest)
+	ErrNicknameRequired      = NewError("nickname_is_required", http.StatusBadRequest)
+	ErrRejoinCodeRequired    = NewError("rejoin_code_is_required", http.StatusBadRequest)
+	ErrUnsupportedLanguage   = NewError("unsupported_language", http.StatusBadRequest)
+)
+
+// Room settings and role packs.
+var (
+	ErrActionNotAllowed         = NewError("action_not_allowed", http.StatusBadRequest)
+	ErrInvalidMaxPlayers        = NewError("invalid_max_players", http.StatusBadRequest)
+	ErrInvalidTurnTimer         = NewError("invalid_turn_timer", http.StatusBadRequest)
+	ErrInvalidStartingCoins     = NewError("invalid_starting_coins", http.StatusBadRequest)
+	ErrUnknownAction            = NewError("unknown_action", http.StatusBadRequest)
+	ErrUnknownVariant           = NewError("unknown_variant", http.StatusBadRequest)
+	ErrIncomeMustBeAllowed      = NewError("income_must_be_allowed", http.StatusBadRequest)
+	ErrInvalidCopiesPerRole     = NewError("invalid_copies_per_role", http.StatusBadRequest)
+	ErrTwoPlayerVariantNeedsTwo = NewError("two_player_variant_needs_two_players", http.StatusBadRequest)
+	ErrUnknownRolePack          = NewError("unknown_role_pack", http.StatusBadRequest)
+	ErrInvalidRolePack          = NewError("invalid_role_pack", http.StatusBadRequest)
+	ErrDuplicateRolePack        = NewError("duplicate_role_pack", http.StatusConflict)
+)
+
+// Turns. Moves made at the wrong moment are conflicts with the game's state;
+// moves the rules never allow are bad requests.
+var (
+	ErrNotYourTurn            = NewError("not_your_turn", http.StatusConflict)
+	ErrDraftInProgress        = NewError("draft_in_progress", http.StatusConflict)
+	ErrNoDraftPending         = NewError("no_draft_pending", http.StatusConflict)
+	ErrActionPending          = NewError("action_pending", http.StatusConflict)
+	ErrNoPendingAction        = NewError("no_pending_action", http.StatusConflict)
+	ErrInfluenceLossPending   = NewError("influence_loss_pending", http.StatusConflict)
+	ErrNoInfluenceLossPending = NewError("no_influence_loss_pending", http.StatusConflict)
+	ErrExchangePending        = NewError("exchange_pending", http.StatusConflict)
+	ErrNoExchangePending      = NewError("no_exchange_pending", http.StatusConflict)
+	ErrExaminationPending     = NewError("examination_pending", http.StatusConflict)
+	ErrNoExaminationPending   = NewError("no_examination_pending", http.StatusConflict)
+	ErrCardNotShownYet        = NewError("card_not_shown_yet", http.StatusConflict)
+	ErrCannotRespond          = NewError("cannot_respond", http.StatusConflict)
+	ErrPlayerIsDead           = NewError("player_is_dead", http.StatusConflict)
+	ErrInvalidAction          = NewError("invalid_action", http.StatusBadRequest)
+	ErrInvalidActionName      = NewError("invalid_action_name", http.StatusBadRequest)
+	ErrInvalidDraftSelection  = NewError("invalid_draft_selection", http.StatusBadRequest)
+	ErrNotEnoughCoins         = NewError("not_enough_coins", http.StatusBadRequest)
+	ErrMustCoup               = NewError("must_coup", http.StatusBadRequest)
+	ErrTargetRequired         = NewError("target_required", http.StatusBadRequest)
+	ErrTargetPlayerIsDead     = NewError("target_player_is_dead", http.StatusBadRequest)
+	ErrCannotTargetSelf       = NewError("cannot_target_self", http.StatusBadRequest)
+	ErrSameFactionTarget      = NewError("cannot_target_same_faction", http.StatusBadRequest)
+	ErrSameFactionBlock       = NewError("cannot_block_same_faction", http.StatusBadRequest)
+	ErrInvalidResponse        = NewError("invalid_response", http.StatusBadRequest)
+	ErrCannotChallenge        = NewError("cannot_challenge", http.StatusBadRequest)
+	ErrCannotBlock            = NewError("cannot_block", http.StatusBadRequest)
+	ErrInvalidBlockingRole    = NewError("invalid_blocking_role", http.StatusBadRequest)
+	ErrInfluenceNotFound      = NewError("influence_not_found", http.StatusBadRequest)
+	ErrInvalidExchange        = NewError("invalid_exchange_selection", http.StatusBadRequest)
+	ErrActionRequired         = NewError("action_is_required", http.StatusBadRequest)
+	ErrBlockingRoleRequired   = NewError("blocking_role_is_required", http.StatusBadRequest)
+	ErrRoleRequired           = NewError("role_is_required", http.StatusBadRequest)
+	ErrTwoRolesRequired       = NewError("two_roles_are_required", http.StatusBadRequest)
+	ErrKeepRequired           = NewError("keep_is_required", http.StatusBadRequest)
+)
 
+// Replays.
 var (
-	ErrGameNotFound          = errors.New("game_not_found")
-	ErrAlreadyStarted        = errors.New("game_already_started")
-	ErrNotStarted            = errors.New("game_not_started")
-	ErrInvalidAction         = errors.New("invalid_action")
-	ErrPlayerAlreadyJoined   = errors.New("Player already joined with this nickname")
-	ErrGameAlreadyFinished   = errors.New("game_already_finished")
-	ErrOnlyAdminCanStartGame = errors.New("only_admin_can_start_game")
-	ErrNeedAtLeastTwoPlayers = errors.New("need_at_least_two_players")
-	ErrTooManyPlayers        = errors.New("too_many_players")
-	ErrInvalidSession        = errors.New("invalid_session")
-	ErrNotEnoughInfluences   = errors.New("not_enough_influences")
-	ErrPlayerNotFound        = errors.New("player_not_found")
+	ErrReplayUnavailable = NewError("replay_unavailable", http.StatusNotFound)
+	ErrReplayDiverged    = NewError("replay_diverged", http.StatusInternalServerError)
+	ErrInvalidCommand    = NewError("invalid_command", http.StatusBadRequest)
+	ErrInvalidStep       = NewError("invalid_step", http.StatusBadRequest)
 )
@@ -0,0 +1,29 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAsErrorMapsToStatuses(t *testing.T) {
+	cases := []struct {
+		err    error
+		code   string
+		status int
+	}{
+		{ErrGameNotFound, "game_not_found", http.StatusNotFound},
+		{ErrOnlyAdminCanStartGame, "only_admin_can_start_game", http.StatusForbidden},
+		{ErrRoomFull, "room_full", http.StatusConflict},
+		{fmt.Errorf("%w: step 3: %w", ErrReplayDiverged, ErrNotYourTurn), "replay_diverged", http.StatusInternalServerError},
+		{errors.New("connection refused"), "internal_error", http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		known := AsError(tc.err)
+		if known.Code != tc.code || known.Status != tc.status {
+			t.Fatalf("%v: expected %s (%d), got %s (%d)", tc.err, tc.code, tc.status, known.Code, known.Status)
+		}
+	}
+}
@@ -3,11 +3,42 @@ package game
 import (
 	"encoding/json"
 	"influence_game/internal/realtime"
+	"influence_game/internal/versions"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+type GameEvent struct {
+	Type    string         `json:"type"`
+	Payload map[string]any `json:"payload,omitempty"`
+
+	// PlayerID is set for private events, which only that player receives.
+	PlayerID string `json:"playerId,omitempty"`
+}
+
+// eventLog collects what happened while a command was applied to the game,
+// so the store can broadcast it once the change is committed.
+type eventLog []GameEvent
+
+func (events *eventLog) add(eventType string, payload map[string]any) {
+	*events = append(*events, GameEvent{Type: eventType, Payload: payload})
+}
+
+func (events *eventLog) addPrivate(playerID string, eventType string, payload map[string]any) {
+	*events = append(*events, GameEvent{Type: eventType, Payload: payload, PlayerID: playerID})
+}
+
+func broadcastEvents(state *PublicGameState, events eventLog) {
+	for _, event := range events {
+		if event.PlayerID != "" {
+			sendToPlayer(state.Players, event.PlayerID, event.Type, state.GameID, event.Payload)
+			continue
+		}
+		BroadcastEvent(state, event.Type, event.Payload)
+	}
+}
+
 // type TargetedEvent struct {
 // 	PlayerID string
 // 	Data     any
@@ -19,6 +50,36 @@ type ServerEvent struct {
 	Timestamp time.Time        `json:"timestamp"`
 	GameState *PublicGameState `json:"state,omitempty"`
 	Payload   map[string]any   `json:"payload,omitempty"`
+
+	// Message describes the event in the receiving client's language, for
+	// the game log.
+	Message string `json:"message,omitempty"`
+}
+
+/*
+ServerEventV2 is ServerEvent as v2 clients get it, named the way WSMessage
+and the matchmaking socket name things.
+*/
+type ServerEventV2 struct {
+	EventType string           `json:"type"`
+	GameID    string           `json:"gameId"`
+	Timestamp time.Time        `json:"timestamp"`
+	GameState *PublicGameState `json:"gameState,omitempty"`
+	Payload   map[string]any   `json:"payload,omitempty"`
+	Message   string           `json:"message,omitempty"`
+}
+
+// encode writes the event for a client of version.
+func (event ServerEvent) encode(version versions.Version) ([]byte, error) {
+	if version < versions.V2 {
+		return json.Marshal(event)
+	}
+
+	data, err := json.Marshal(ServerEventV2(event))
+	if err != nil {
+		return nil, err
+	}
+	return version.Upgrade(data), nil
 }
 
 func BroadcastEvent(
@@ -30,21 +91,25 @@ func BroadcastEvent(
 		return
 	}
 
-	ev := ServerEvent{
-		EventType: eventType,
-		GameID:    state.GameID,
-		Timestamp: time.Now().UTC(),
-		GameState: state,
-		Payload:   payload,
-	}
+	timestamp := time.Now().UTC()
 
-	data, err := json.Marshal(ev)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to marshal event.")
-		return
-	}
+	realtime.Manager.BroadcastRendered(state.GameID, func(audience realtime.Audience) []byte {
+		ev := ServerEvent{
+			EventType: eventType,
+			GameID:    state.GameID,
+			Timestamp: timestamp,
+			GameState: state,
+			Payload:   payload,
+			Message:   describeEvent(audience.Language, state.Players, eventType, payload),
+		}
 
-	realtime.Manager.Broadcast(state.GameID, data)
+		data, err := ev.encode(audience.Version)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to marshal event.")
+			return nil
+		}
+		return data
+	})
 }
 
 func SendToPlayer(
@@ -53,21 +118,36 @@ func SendToPlayer(
 	gameID string,
 	payload map[string]any,
 ) {
-	ev := ServerEvent{
-		EventType: eventType,
-		GameID:    gameID,
-		Timestamp: time.Now().UTC(),
-		GameState: nil,
-		Payload:   payload,
-	}
+	sendToPlayer(nil, playerID, eventType, gameID, payload)
+}
 
-	data, err := json.Marshal(ev)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to marshal private event.")
-		return
-	}
+// sendToPlayer is SendToPlayer with the players known, so the message can name them.
+func sendToPlayer(
+	players []PlayerPublicInfo,
+	playerID string,
+	eventType string,
+	gameID string,
+	payload map[string]any,
+) {
+	timestamp := time.Now().UTC()
+
+	realtime.Manager.SendToPlayerRendered(gameID, playerID, func(audience realtime.Audience) []byte {
+		ev := ServerEvent{
+			EventType: eventType,
+			GameID:    gameID,
+			Timestamp: timestamp,
+			GameState: nil,
+			Payload:   payload,
+			Message:   describeEvent(audience.Language, players, eventType, payload),
+		}
 
-	realtime.Manager.SendToPlayer(gameID, playerID, data)
+		data, err := ev.encode(audience.Version)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to marshal private event.")
+			return nil
+		}
+		return data
+	})
 }
 
 // func SendPrivateEvents(
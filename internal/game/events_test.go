@@ -0,0 +1,41 @@
+package game
+
+import (
+	"encoding/json"
+	"influence_game/internal/versions"
+	"testing"
+)
+
+func TestEventsAreEncodedPerVersion(t *testing.T) {
+	event := ServerEvent{
+		EventType: "game_started",
+		GameID:    "g1",
+		GameState: &PublicGameState{GameID: "g1", AdminID: "p1"},
+	}
+
+	decode := func(version versions.Version) map[string]any {
+		data, err := event.encode(version)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return decoded
+	}
+
+	v1 := decode(versions.V1)
+	if v1["eventType"] != "game_started" || v1["gameID"] != "g1" || v1["state"] == nil {
+		t.Fatalf("expected v1 names, got %v", v1)
+	}
+
+	v2 := decode(versions.V2)
+	if v2["type"] != "game_started" || v2["gameId"] != "g1" || v2["eventType"] != nil || v2["state"] != nil {
+		t.Fatalf("expected v2 names, got %v", v2)
+	}
+	state, _ := v2["gameState"].(map[string]any)
+	if state["gameId"] != "g1" || state["adminId"] != "p1" || state["gameID"] != nil {
+		t.Fatalf("expected the game state in v2 names, got %v", v2["gameState"])
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+/*
+startExchange draws cards for the actor, who then privately chooses which
+cards to keep. The number of cards kept always matches the unrevealed hand.
+*/
+func startExchange(game *Game, actor *Player, claimedRole string, events *eventLog) {
+	drawCount := min(exchangeDrawCount(game, claimedRole), len(game.Deck))
+
+	drawn := append([]Influence{}, game.Deck[:drawCount]...)
+	game.Deck = game.Deck[drawCount:]
+
+	game.PendingExchange = &PendingExchange{
+		PlayerID: actor.ID,
+		Drawn:    drawn,
+	}
+
+	events.addPrivate(actor.ID, "exchange_options", map[string]any{
+		"drawn": drawn,
+		"keep":  countUnrevealed(actor),
+	})
+}
+
+func completeExchange(game *Game, playerID string, keep []string, events *eventLog) error {
+	if !game.Started || game.Finished {
+		return ErrNotStarted
+	}
+
+	exchange := game.PendingExchange
+	if exchange == nil || exchange.PlayerID != playerID {
+		return ErrNoExchangePending
+	}
+
+	player, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return err
+	}
+
+	if len(keep) != countUnrevealed(player) {
+		return ErrInvalidExchange
+	}
+
+	pool := append([]Influence{}, exchange.Drawn...)
+	for _, influence := range player.Influences {
+		if !influence.Revealed {
+			pool = append(pool, influence)
+		}
+	}
+
+	kept := make([]Influence, 0, len(keep))
+	for _, role := range keep {
+		index := -1
+		for i, influence := range pool {
+			if influence.Role == role {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return ErrInvalidExchange
+		}
+
+		kept = append(kept, pool[index])
+		pool = append(pool[:index], pool[index+1:]...)
+	}
+
+	recordCommand(game, Command{
+		Type:     CommandExchange,
+		PlayerID: playerID,
+		Roles:    append([]string{}, keep...),
+	})
+
+	next := 0
+	for i := range player.Influences {
+		if !player.Influences[i].Revealed {
+			player.Influences[i] = kept[next]
+			next++
+		}
+	}
+
+	game.Deck = append(game.Deck, pool...)
+	shuffleDeck(game)
+	game.PendingExchange = nil
+
+	events.add("exchange_completed", map[string]any{
+		"playerId": playerID,
+	})
+
+	return nil
+}
+
+func shuffleDeck(game *Game) {
+	game.random().Shuffle(len(game.Deck), func(i, j int) {
+		game.Deck[i], game.Deck[j] = game.Deck[j], game.Deck[i]
+	})
+}
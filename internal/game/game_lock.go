@@ -8,6 +8,36 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+func (store *Store) loadGame(ctx context.Context, gameID string) (*Game, error) {
+	gameJSON, err := store.redis.Get(ctx, "game:"+gameID).Bytes()
+	if err == redis.Nil {
+		return nil, ErrGameNotFound
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get game from Redis.")
+		return nil, err
+	}
+
+	game, err := decodeGame(gameJSON)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal game.")
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// decodeGame reads a saved game, filling in settings it was saved without.
+func decodeGame(data []byte) (*Game, error) {
+	var game Game
+	if err := json.Unmarshal(data, &game); err != nil {
+		return nil, err
+	}
+
+	game.Settings = game.Settings.withDefaults()
+	return &game, nil
+}
+
 func (store *Store) withGameLock(
 	ctx context.Context,
 	gameID string,
@@ -16,6 +46,7 @@ func (store *Store) withGameLock(
 
 	gameKey := "game:" + gameID
 	var updatedGame *Game
+	var finishing bool
 
 	for {
 		err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
@@ -28,29 +59,33 @@ func (store *Store) withGameLock(
 				return err
 			}
 
-			var game Game
-			if err := json.Unmarshal(gameJSON, &game); err != nil {
+			game, err := decodeGame(gameJSON)
+			if err != nil {
 				log.Error().Err(err).Msg("Failed to unmarshal game.")
 				return err
 			}
 
-			if err := fn(&game); err != nil {
+			wasFinished := game.Finished
+			if err := fn(game); err != nil {
 				return err
 			}
 
-			updatedJSON, err := json.Marshal(&game)
+			updatedJSON, err := json.Marshal(game)
 			if err != nil {
 				log.Error().Err(err).Msg("Failed to marshal game.")
 				return err
 			}
 
 			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-				pipe.Set(ctx, gameKey, updatedJSON, 0)
+				ttl := gameTTL(game)
+				pipe.Set(ctx, gameKey, updatedJSON, ttl)
+				pipe.Expire(ctx, "joincode:"+game.JoinCode, ttl)
 				return nil
 			})
 
 			if err == nil {
-				updatedGame = &game
+				updatedGame = game
+				finishing = !wasFinished && game.Finished
 			}
 
 			return err
@@ -67,5 +102,10 @@ func (store *Store) withGameLock(
 		break
 	}
 
+	store.notifyGameUpdated(updatedGame)
+	if finishing {
+		store.notifyGameFinished(updatedGame)
+	}
+
 	return updatedGame, nil
 }
@@ -0,0 +1,38 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFinishHooksRunOnceWhenTheGameEnds(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	created, err := store.CreateGameRoom("ana", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	finished := 0
+	store.OnGameFinished(func(*Game) { finished++ })
+
+	ctx := context.Background()
+	writes := []func(*Game){
+		func(game *Game) { game.Started = true },
+		func(game *Game) { game.Finished = true },
+		func(game *Game) { game.RematchID = "next" },
+	}
+	for _, write := range writes {
+		_, err := store.withGameLock(ctx, created.Game.GameID, func(game *Game) error {
+			write(game)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	if finished != 1 {
+		t.Fatalf("expected the finish hooks to run once, ran %d times", finished)
+	}
+}
@@ -1,25 +1,45 @@
 package game
 
-import "math/rand"
-
 func SetupNewGame(game *Game) error {
-	if len(game.Players) < 2 {
+	if len(game.Players) < MinPlayers {
 		return ErrNeedAtLeastTwoPlayers
 	}
-	if len(game.Players) > 6 {
+	if len(game.Players) > game.Settings.MaxPlayers {
 		return ErrTooManyPlayers
 	}
 
+	pack := rolePackFor(game)
+	deck := buildDeck(pack.RoleNames(), game.Settings.DeckCopies(len(game.Players)))
+	if len(deck) < 2*len(game.Players)+1 {
+		return ErrNotEnoughInfluences
+	}
+
+	captureSetup(game)
+
 	game.Started = true
-	game.TurnIndex = rand.Intn(len(game.Players))
+	game.Roles = pack.Roles
+	game.TurnIndex = game.random().Intn(len(game.Players))
+	for i, p := range game.Players {
+		if p.ID == game.StartingPlayerID {
+			game.TurnIndex = i
+		}
+	}
+	game.StartingPlayerID = game.Players[game.TurnIndex].ID
 
-	deck := NewBaseDeck()
-	rand.Shuffle(len(deck), func(i, j int) {
+	game.random().Shuffle(len(deck), func(i, j int) {
 		deck[i], deck[j] = deck[j], deck[i]
 	})
 
+	if game.Settings.HasVariant(VariantReformation) {
+		assignAllegiances(game)
+	}
+
+	if game.Settings.HasVariant(VariantTwoPlayer) {
+		return setupTwoPlayerGame(game, deck)
+	}
+
 	for _, p := range game.Players {
-		p.Coins = 2
+		p.Coins = game.Settings.StartingCoins
 		p.Alive = true
 		p.Influences = []Influence{deck[0], deck[1]}
 		deck = deck[2:]
@@ -29,26 +49,67 @@ func SetupNewGame(game *Game) error {
 	return nil
 }
 
-func NewBaseDeck() []Influence {
-	return []Influence{
-		{Role: "Duke"},
-		{Role: "Duke"},
-		{Role: "Duke"},
+/*
+setupTwoPlayerGame applies the two-player variant: the starting player gets
+one coin less and each player drafts two influences from five dealt cards.
+*/
+func setupTwoPlayerGame(game *Game, deck []Influence) error {
+	if len(game.Players) != 2 {
+		return ErrTwoPlayerVariantNeedsTwo
+	}
+	if len(deck) < 2*TwoPlayerDraftSize {
+		return ErrNotEnoughInfluences
+	}
+
+	game.Drafts = make(map[string][]Influence, len(game.Players))
+
+	for i, p := range game.Players {
+		p.Coins = game.Settings.StartingCoins
+		if i == game.TurnIndex && p.Coins > 0 {
+			p.Coins--
+		}
+		p.Alive = true
+		p.Influences = []Influence{}
+
+		game.Drafts[p.ID] = append([]Influence{}, deck[:TwoPlayerDraftSize]...)
+		deck = deck[TwoPlayerDraftSize:]
+	}
+
+	game.Deck = deck
+	return nil
+}
 
-		{Role: "Assassin"},
-		{Role: "Assassin"},
-		{Role: "Assassin"},
+/*
+DeckCopiesForPlayers follows the expanded rules: 3 copies of each role up to
+6 players, 4 copies for 7-8 players and 5 copies for 9-10 players.
+*/
+func DeckCopiesForPlayers(playerCount int) int {
+	switch {
+	case playerCount <= 6:
+		return 3
+	case playerCount <= 8:
+		return 4
+	default:
+		return 5
+	}
+}
 
-		{Role: "Ambassador"},
-		{Role: "Ambassador"},
-		{Role: "Ambassador"},
+func NewBaseDeck() []Influence {
+	return NewDeck(3)
+}
 
-		{Role: "Captain"},
-		{Role: "Captain"},
-		{Role: "Captain"},
+func NewDeck(copiesPerRole int) []Influence {
+	return buildDeck(baseRolePack().RoleNames(), copiesPerRole)
+}
+
+func buildDeck(roles []string, copiesPerRole int) []Influence {
+	deck := make([]Influence, 0, copiesPerRole*len(roles))
 
-		{Role: "Contessa"},
-		{Role: "Contessa"},
-		{Role: "Contessa"},
+	for _, role := range roles {
+		for i := 0; i < copiesPerRole; i++ {
+			deck = append(deck, Influence{Role: role})
+		}
 	}
+
+	return deck
 }
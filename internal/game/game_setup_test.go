@@ -0,0 +1,68 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newLobby(playerCount int) *Game {
+	game := &Game{Settings: DefaultRoomSettings()}
+	game.Settings.MaxPlayers = MaxPlayers
+
+	for i := 0; i < playerCount; i++ {
+		game.Players = append(game.Players, buildNewPlayer(fmt.Sprintf("player%d", i), 0))
+	}
+
+	return game
+}
+
+func TestDeckScalesWithPlayerCount(t *testing.T) {
+	cases := map[int]int{2: 15, 6: 15, 7: 20, 8: 20, 9: 25, 10: 25}
+
+	for playerCount, deckSize := range cases {
+		game := newLobby(playerCount)
+
+		if err := SetupNewGame(game); err != nil {
+			t.Fatalf("%d players: unexpected error %v", playerCount, err)
+		}
+
+		remaining := deckSize - 2*playerCount
+		if len(game.Deck) != remaining {
+			t.Fatalf("%d players: expected %d cards left in deck, got %d", playerCount, remaining, len(game.Deck))
+		}
+	}
+}
+
+func TestSetupRejectsTooManyPlayers(t *testing.T) {
+	game := newLobby(MaxPlayers + 1)
+
+	if err := SetupNewGame(game); err != ErrTooManyPlayers {
+		t.Fatalf("expected %v, got %v", ErrTooManyPlayers, err)
+	}
+}
+
+func TestSetupHonorsCopiesOverride(t *testing.T) {
+	game := newLobby(4)
+	game.Settings.CopiesPerRole = 4
+
+	if err := SetupNewGame(game); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(game.Deck) != 20-8 {
+		t.Fatalf("expected 12 cards left in deck, got %d", len(game.Deck))
+	}
+}
+
+func TestNeighbouringSeedsDrawDifferently(t *testing.T) {
+	seen := map[int64]string{}
+	for seed := int64(1); seed <= 50; seed++ {
+		for draw := 0; draw < 50; draw++ {
+			derived := DeriveSeed(seed, draw)
+			if other, taken := seen[derived]; taken {
+				t.Fatalf("seed %d draw %d repeats %s", seed, draw, other)
+			}
+			seen[derived] = fmt.Sprintf("seed %d draw %d", seed, draw)
+		}
+	}
+}
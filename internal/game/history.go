@@ -0,0 +1,95 @@
+package game
+
+/*
+ActionRecord is what happened to one declared action: the claim behind it,
+whether the claim or a block was a bluff, the challenges it drew and how it
+ended. Records are kept on the game so a finished game can be archived.
+*/
+type ActionRecord struct {
+	ActionID    string  `json:"actionId"`
+	ActorID     string  `json:"actorId"`
+	ActionName  string  `json:"actionName"`
+	TargetID    *string `json:"targetId,omitempty"`
+	ClaimedRole string  `json:"claimedRole,omitempty"`
+	Bluff       bool    `json:"bluff,omitempty"`
+
+	BlockerID  string `json:"blockerId,omitempty"`
+	BlockRole  string `json:"blockRole,omitempty"`
+	BlockBluff bool   `json:"blockBluff,omitempty"`
+
+	Challenges []ChallengeRecord `json:"challenges,omitempty"`
+
+	// "resolved", "blocked", "block_upheld" or "challenge_won"; empty when
+	// the game ended before the action did.
+	Outcome string `json:"outcome,omitempty"`
+}
+
+type ChallengeRecord struct {
+	ChallengerID string `json:"challengerId"`
+	ClaimantID   string `json:"claimantId"`
+	Role         string `json:"role"`
+	ClaimHolds   bool   `json:"claimHolds"`
+}
+
+func recordDeclaration(game *Game, pending *PendingAction, actor *Player) {
+	game.History = append(game.History, ActionRecord{
+		ActionID:    pending.ID,
+		ActorID:     pending.ActorID,
+		ActionName:  pending.ActionName,
+		TargetID:    pending.TargetID,
+		ClaimedRole: pending.ClaimedRole,
+		Bluff:       pending.ClaimedRole != "" && hasUnrevealedRole(actor, pending.ClaimedRole) == pending.InvertedClaim,
+	})
+}
+
+func currentRecord(game *Game, actionID string) *ActionRecord {
+	if len(game.History) == 0 || game.History[len(game.History)-1].ActionID != actionID {
+		return nil
+	}
+	return &game.History[len(game.History)-1]
+}
+
+func recordBlock(game *Game, actionID string, blocker *Player, role string) {
+	if record := currentRecord(game, actionID); record != nil {
+		record.BlockerID = blocker.ID
+		record.BlockRole = role
+		record.BlockBluff = !hasUnrevealedRole(blocker, role)
+	}
+}
+
+func recordChallenge(game *Game, actionID string, challenge ChallengeRecord) {
+	if record := currentRecord(game, actionID); record != nil {
+		record.Challenges = append(record.Challenges, challenge)
+	}
+}
+
+func recordOutcome(game *Game, actionID string, outcome string) {
+	if record := currentRecord(game, actionID); record != nil {
+		record.Outcome = outcome
+	}
+}
+
+/*
+FinishingOrder lists the players of a finished game from first to last: the
+winner, then everyone else in reverse order of elimination.
+*/
+func FinishingOrder(game *Game) []*Player {
+	if !game.Finished || game.WinnerID == nil {
+		return nil
+	}
+
+	order := []*Player{}
+	if winner, err := findPlayerByID(game, *game.WinnerID); err == nil {
+		order = append(order, winner)
+	}
+	for i := len(game.EliminatedPlayerIDs) - 1; i >= 0; i-- {
+		if game.EliminatedPlayerIDs[i] == *game.WinnerID {
+			continue
+		}
+		if player, err := findPlayerByID(game, game.EliminatedPlayerIDs[i]); err == nil {
+			order = append(order, player)
+		}
+	}
+
+	return order
+}
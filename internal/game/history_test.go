@@ -0,0 +1,38 @@
+package game
+
+import "testing"
+
+func TestHistoryRecordsBluffsChallengesAndBlocks(t *testing.T) {
+	game := newTestGame(t, nil, []string{"Captain", "Contessa"}, []string{"Captain", "Captain"}, []string{"Duke", "Contessa"})
+
+	declare(t, game, "tax", nil)
+	respond(t, game, game.Players[1], ResponseChallenge, "")
+
+	var events eventLog
+	if err := chooseInfluenceLoss(game, game.Players[0].ID, "Contessa", &events); err != nil {
+		t.Fatalf("unexpected loss error %v", err)
+	}
+
+	declare(t, game, "foreign_aid", nil)
+	respond(t, game, game.Players[2], ResponsePass, "")
+	respond(t, game, game.Players[0], ResponseBlock, "Duke")
+	respond(t, game, game.Players[1], ResponsePass, "")
+	respond(t, game, game.Players[2], ResponsePass, "")
+
+	if len(game.History) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(game.History))
+	}
+
+	tax := game.History[0]
+	if !tax.Bluff || tax.Outcome != "challenge_won" {
+		t.Fatalf("expected a caught bluff, got %+v", tax)
+	}
+	if len(tax.Challenges) != 1 || tax.Challenges[0].ChallengerID != game.Players[1].ID || tax.Challenges[0].ClaimHolds {
+		t.Fatalf("expected the challenger to win, got %+v", tax.Challenges)
+	}
+
+	aid := game.History[1]
+	if aid.Bluff || aid.BlockerID != game.Players[0].ID || !aid.BlockBluff || aid.Outcome != "blocked" {
+		t.Fatalf("expected an unchallenged bluffed block, got %+v", aid)
+	}
+}
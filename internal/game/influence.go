@@ -0,0 +1,177 @@
+package game
+
+/*
+loseInfluence makes the player give up one influence. With a single card left
+(or every remaining card already owed) there is no choice to make, so the
+cards are revealed right away; otherwise the loss is queued until the player
+picks which card to reveal.
+*/
+func loseInfluence(game *Game, player *Player, reason string, events *eventLog) {
+	if player == nil || !player.Alive {
+		return
+	}
+
+	unrevealed := countUnrevealed(player)
+	owed := countPendingLosses(game, player.ID)
+
+	if unrevealed > owed+1 {
+		game.PendingLosses = append(game.PendingLosses, InfluenceLoss{
+			PlayerID: player.ID,
+			Reason:   reason,
+		})
+		return
+	}
+
+	removePendingLosses(game, player.ID)
+	for i := range player.Influences {
+		if !player.Influences[i].Revealed {
+			revealInfluence(game, player, i, reason, events)
+		}
+	}
+}
+
+func chooseInfluenceLoss(game *Game, playerID string, role string, events *eventLog) error {
+	if !game.Started || game.Finished {
+		return ErrNotStarted
+	}
+
+	lossIndex := -1
+	for i, loss := range game.PendingLosses {
+		if loss.PlayerID == playerID {
+			lossIndex = i
+			break
+		}
+	}
+	if lossIndex == -1 {
+		return ErrNoInfluenceLossPending
+	}
+
+	player, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return err
+	}
+
+	for i, influence := range player.Influences {
+		if influence.Revealed || influence.Role != role {
+			continue
+		}
+
+		recordCommand(game, Command{
+			Type:     CommandLoseInfluence,
+			PlayerID: playerID,
+			Roles:    []string{role},
+		})
+
+		reason := game.PendingLosses[lossIndex].Reason
+		game.PendingLosses = append(game.PendingLosses[:lossIndex], game.PendingLosses[lossIndex+1:]...)
+		revealInfluence(game, player, i, reason, events)
+		return nil
+	}
+
+	return ErrInfluenceNotFound
+}
+
+func revealInfluence(game *Game, player *Player, index int, reason string, events *eventLog) {
+	player.Influences[index].Revealed = true
+
+	events.add("influence_lost", map[string]any{
+		"playerId": player.ID,
+		"role":     player.Influences[index].Role,
+		"reason":   reason,
+	})
+
+	if countUnrevealed(player) > 0 {
+		return
+	}
+
+	player.Alive = false
+	removePendingLosses(game, player.ID)
+	game.EliminatedPlayerIDs = append(game.EliminatedPlayerIDs, player.ID)
+
+	events.add("player_eliminated", map[string]any{
+		"playerId": player.ID,
+	})
+
+	checkForWinner(game, events)
+}
+
+func checkForWinner(game *Game, events *eventLog) {
+	var survivor *Player
+	for _, p := range game.Players {
+		if !p.Alive {
+			continue
+		}
+		if survivor != nil {
+			return
+		}
+		survivor = p
+	}
+
+	if survivor == nil {
+		return
+	}
+
+	game.Finished = true
+	game.WinnerID = &survivor.ID
+	game.PendingAction = nil
+	game.PendingLosses = nil
+	game.PendingExchange = nil
+	game.PendingExamination = nil
+
+	events.add("game_finished", map[string]any{
+		"winnerId": survivor.ID,
+	})
+}
+
+/*
+finishTurn closes the current action and hands the turn to the next player
+still in the game.
+*/
+func finishTurn(game *Game) {
+	game.PendingAction = nil
+
+	if game.Finished {
+		return
+	}
+
+	advanceTurn(game)
+}
+
+func advanceTurn(game *Game) {
+	for range game.Players {
+		game.TurnIndex = (game.TurnIndex + 1) % len(game.Players)
+		if game.Players[game.TurnIndex].Alive {
+			return
+		}
+	}
+}
+
+func countUnrevealed(player *Player) int {
+	count := 0
+	for _, influence := range player.Influences {
+		if !influence.Revealed {
+			count++
+		}
+	}
+	return count
+}
+
+func countPendingLosses(game *Game, playerID string) int {
+	count := 0
+	for _, loss := range game.PendingLosses {
+		if loss.PlayerID == playerID {
+			count++
+		}
+	}
+	return count
+}
+
+func removePendingLosses(game *Game, playerID string) {
+	remaining := game.PendingLosses[:0]
+	for _, loss := range game.PendingLosses {
+		if loss.PlayerID != playerID {
+			remaining = append(remaining, loss)
+		}
+	}
+	game.PendingLosses = remaining
+}
@@ -0,0 +1,107 @@
+package game
+
+/*
+startExamination opens an examination. The target first chooses which of
+their unrevealed cards to show the examiner, see showInfluence, and the
+examiner then decides whether the target must swap it for a card from the
+deck.
+*/
+func startExamination(game *Game, examiner *Player, target *Player) {
+	if target == nil || !target.Alive {
+		return
+	}
+
+	game.PendingExamination = &PendingExamination{
+		ExaminerID: examiner.ID,
+		TargetID:   target.ID,
+	}
+}
+
+// showInfluence is the target privately showing the examiner one hidden card.
+func showInfluence(game *Game, playerID string, role string, events *eventLog) error {
+	if !game.Started || game.Finished {
+		return ErrNotStarted
+	}
+
+	examination := game.PendingExamination
+	if examination == nil || examination.Shown || examination.TargetID != playerID {
+		return ErrNoExaminationPending
+	}
+
+	target, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return err
+	}
+
+	for i, influence := range target.Influences {
+		if influence.Revealed || influence.Role != role {
+			continue
+		}
+
+		recordCommand(game, Command{
+			Type:     CommandShowInfluence,
+			PlayerID: playerID,
+			Roles:    []string{role},
+		})
+
+		examination.Shown = true
+		examination.CardIndex = i
+
+		events.addPrivate(examination.ExaminerID, "examine_result", map[string]any{
+			"targetId": target.ID,
+			"card":     influence,
+		})
+		return nil
+	}
+
+	return ErrInfluenceNotFound
+}
+
+func completeExamination(game *Game, playerID string, forceSwap bool, events *eventLog) error {
+	if !game.Started || game.Finished {
+		return ErrNotStarted
+	}
+
+	examination := game.PendingExamination
+	if examination == nil || examination.ExaminerID != playerID {
+		return ErrNoExaminationPending
+	}
+	if !examination.Shown {
+		return ErrCardNotShownYet
+	}
+
+	target, err := findPlayerByID(game, examination.TargetID)
+	if err != nil {
+		return err
+	}
+
+	recordCommand(game, Command{
+		Type:      CommandExamination,
+		PlayerID:  playerID,
+		ForceSwap: forceSwap,
+	})
+
+	game.PendingExamination = nil
+
+	if forceSwap && target.Alive && !target.Influences[examination.CardIndex].Revealed {
+		swapped := target.Influences[examination.CardIndex]
+		game.Deck = append(game.Deck, swapped)
+		shuffleDeck(game)
+
+		target.Influences[examination.CardIndex] = game.Deck[0]
+		game.Deck = game.Deck[1:]
+
+		events.addPrivate(target.ID, "influence_swapped", map[string]any{
+			"lost":     swapped,
+			"received": target.Influences[examination.CardIndex],
+		})
+	}
+
+	events.add("examination_completed", map[string]any{
+		"examinerId": examination.ExaminerID,
+		"targetId":   examination.TargetID,
+		"forcedSwap": forceSwap,
+	})
+
+	return nil
+}
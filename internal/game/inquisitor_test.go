@@ -0,0 +1,120 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func newInquisitorGame(t *testing.T, hands ...[]string) *Game {
+	t.Helper()
+
+	game := newTestGame(t, nil, hands...)
+	game.Settings.RolePack = RolePackInquisitor
+	game.Deck = buildDeck(inquisitorRolePack().RoleNames(), 3)
+
+	return game
+}
+
+func TestInquisitorGamesAreDealtWithoutAmbassadors(t *testing.T) {
+	settings := DefaultRoomSettings()
+	settings.RolePack = RolePackInquisitor
+	settings.CopiesPerRole = 3
+
+	game, err := NewLocalGame(3, settings, 1)
+	if err != nil {
+		t.Fatalf("unexpected setup error %v", err)
+	}
+
+	cards := append([]Influence{}, game.Deck...)
+	for _, player := range game.Players {
+		cards = append(cards, player.Influences...)
+	}
+	for _, influence := range cards {
+		if influence.Role == "Ambassador" {
+			t.Fatalf("expected no Ambassador in an Inquisitor game")
+		}
+	}
+	if len(cards) != 15 {
+		t.Fatalf("expected 15 cards, got %d", len(cards))
+	}
+}
+
+func TestInquisitorExchangeDrawsOneCard(t *testing.T) {
+	game := newInquisitorGame(t, []string{"Inquisitor", "Duke"}, []string{"Captain", "Captain"})
+	actor := game.Players[0]
+
+	payload := declare(t, game, "exchange", nil)
+	if payload.ClaimedRole != "Inquisitor" {
+		t.Fatalf("expected exchange to claim Inquisitor, got %s", payload.ClaimedRole)
+	}
+	respond(t, game, game.Players[1], ResponsePass, "")
+
+	if game.PendingExchange == nil || len(game.PendingExchange.Drawn) != 1 {
+		t.Fatalf("expected one drawn card, got %+v", game.PendingExchange)
+	}
+
+	var events eventLog
+	keep := []string{"Inquisitor", "Duke"}
+	if err := completeExchange(game, actor.ID, keep, &events); err != nil {
+		t.Fatalf("unexpected exchange error %v", err)
+	}
+	if game.PendingExchange != nil || len(game.Deck) != 15 {
+		t.Fatalf("expected the drawn card back in the deck")
+	}
+}
+
+func TestExamineShowsTheTargetsChoiceAndForcesSwap(t *testing.T) {
+	game := newInquisitorGame(t, []string{"Inquisitor", "Duke"}, []string{"Contessa", "Captain"})
+	examiner := game.Players[0]
+	target := game.Players[1]
+	game.Deck = []Influence{{Role: "Duke"}}
+	// Seeded so the swapped Captain is shuffled under the Duke.
+	game.Seed = 1
+
+	declare(t, game, "examine", target)
+	respond(t, game, target, ResponsePass, "")
+
+	if decision := NextDecision(game, target.ID); decision != DecisionShowInfluence {
+		t.Fatalf("expected the target to choose the card to show, got %q", decision)
+	}
+	var events eventLog
+	if err := completeExamination(game, examiner.ID, true, &events); !errors.Is(err, ErrCardNotShownYet) {
+		t.Fatalf("expected the examiner to wait for the card, got %v", err)
+	}
+
+	if err := showInfluence(game, target.ID, "Captain", &events); err != nil {
+		t.Fatalf("unexpected show error %v", err)
+	}
+	private := events[0]
+	if private.PlayerID != examiner.ID || private.Type != "examine_result" || private.Payload["card"] != (Influence{Role: "Captain"}) {
+		t.Fatalf("expected the examiner to privately see the Captain, got %+v", private)
+	}
+	if last := game.Commands[len(game.Commands)-1]; last.Type != CommandShowInfluence || last.Roles[0] != "Captain" {
+		t.Fatalf("expected the choice to be recorded for replays, got %+v", last)
+	}
+
+	events = nil
+	if err := completeExamination(game, examiner.ID, true, &events); err != nil {
+		t.Fatalf("unexpected examination error %v", err)
+	}
+	if events[0].PlayerID != target.ID || events[0].Type != "influence_swapped" || events[0].Payload["lost"] != (Influence{Role: "Captain"}) {
+		t.Fatalf("expected the target to be told about the swap, got %+v", events[0])
+	}
+	if !hasUnrevealedRole(target, "Duke") || !hasUnrevealedRole(target, "Contessa") {
+		t.Fatalf("expected the shown card to be replaced, got %+v", target.Influences)
+	}
+	if len(game.Deck) != 1 || game.Deck[0].Role != "Captain" || game.PendingExamination != nil {
+		t.Fatalf("expected the swapped card to go back to the deck, got %+v", game.Deck)
+	}
+}
+
+func TestInquisitorBlocksSteal(t *testing.T) {
+	game := newInquisitorGame(t, []string{"Captain", "Duke"}, []string{"Inquisitor", "Contessa"})
+
+	declare(t, game, "steal", game.Players[1])
+	respond(t, game, game.Players[1], ResponseBlock, "Inquisitor")
+
+	if game.PendingAction.Status != "blocked" {
+		t.Fatalf("expected the steal to be blocked")
+	}
+}
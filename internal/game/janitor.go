@@ -0,0 +1,229 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"influence_game/internal/realtime"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+/*
+gameTTL is how long a game is kept after its last write. Every write renews
+it, so only rooms nobody touches expire: abandoned lobbies after LobbyTTL,
+stalled games after ActiveGameTTL. Finished games are archived by the finish
+hooks when they end and linger for FinishedGameTTL so players can see the
+result.
+*/
+func gameTTL(game *Game) time.Duration {
+	switch {
+	case game.Finished:
+		return FinishedGameTTL
+	case game.Started:
+		return ActiveGameTTL
+	default:
+		return LobbyTTL
+	}
+}
+
+// janitorLockKey lets one instance at a time sweep the shared keys.
+const janitorLockKey = "janitor:lock"
+
+var releaseJanitorLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type SweepReport struct {
+	GamesExpired       int `json:"gamesExpired"` // keys written before TTLs existed
+	JoinCodesRemoved   int `json:"joinCodesRemoved"`
+	RejoinCodesRemoved int `json:"rejoinCodesRemoved"`
+	LobbiesRemoved     int `json:"lobbiesRemoved"`
+	SocketsClosed      int `json:"socketsClosed"` // games whose sockets were closed
+}
+
+// RunJanitor sweeps every JanitorInterval until ctx is done.
+func (store *Store) RunJanitor(ctx context.Context) {
+	ticker := time.NewTicker(JanitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := store.Sweep(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to sweep Redis.")
+				continue
+			}
+			log.Info().Interface("report", report).Msg("Swept Redis.")
+		}
+	}
+}
+
+/*
+Sweep removes whatever outlived its game: join and rejoin codes, public lobby
+entries and open sockets pointing at games that are gone. Games saved without
+an expiry get one, and if they had already finished they go through the
+finish hooks once more so the archive picks them up.
+
+Every instance runs the janitor, but only the one holding the lock sweeps
+Redis; the rest only close their own sockets.
+*/
+func (store *Store) Sweep(ctx context.Context) (SweepReport, error) {
+	report := SweepReport{}
+
+	lockID := uuid.NewString()
+	locked, err := store.redis.SetNX(ctx, janitorLockKey, lockID, JanitorInterval).Result()
+	if err != nil {
+		return report, err
+	}
+	if locked {
+		err := store.sweepRedis(ctx, &report)
+		releaseJanitorLock.Run(ctx, store.redis, []string{janitorLockKey}, lockID)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	for _, gameID := range realtime.Manager.GameIDs() {
+		// Other channels, such as the matchmaking queue, share the manager.
+		if uuid.Validate(gameID) != nil {
+			continue
+		}
+		exists, err := store.redis.Exists(ctx, "game:"+gameID).Result()
+		if err != nil {
+			return report, err
+		}
+		if exists == 0 {
+			realtime.Manager.DisconnectGame(gameID)
+			report.SocketsClosed++
+		}
+	}
+
+	store.sessions.ForgetExpired()
+
+	return report, nil
+}
+
+func (store *Store) sweepRedis(ctx context.Context, report *SweepReport) error {
+	err := store.scan(ctx, "game:*", func(key string) error {
+		ttl, err := store.redis.TTL(ctx, key).Result()
+		if err != nil || ttl != -1 {
+			return err
+		}
+
+		game, err := store.loadGame(ctx, strings.TrimPrefix(key, "game:"))
+		if err != nil {
+			return nil
+		}
+		if err := store.redis.Expire(ctx, key, gameTTL(game)).Err(); err != nil {
+			return err
+		}
+		if game.Finished {
+			store.notifyGameFinished(game)
+		}
+		report.GamesExpired++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = store.scan(ctx, "joincode:*", func(key string) error {
+		gameID, err := store.redis.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return store.removeIfOrphaned(ctx, gameID, &report.JoinCodesRemoved, func(pipe redis.Pipeliner) {
+			pipe.Del(ctx, key)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = store.scan(ctx, "rejoin:*", func(key string) error {
+		data, err := store.redis.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var seat rejoinSeat
+		if err := json.Unmarshal(data, &seat); err != nil {
+			return store.redis.Del(ctx, key).Err()
+		}
+		return store.removeIfOrphaned(ctx, seat.GameID, &report.RejoinCodesRemoved, func(pipe redis.Pipeliner) {
+			pipe.Del(ctx, key)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	lobbyIDs, err := store.redis.ZRange(ctx, publicLobbiesKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	for _, gameID := range lobbyIDs {
+		err := store.removeIfOrphaned(ctx, gameID, &report.LobbiesRemoved, func(pipe redis.Pipeliner) {
+			pipe.ZRem(ctx, publicLobbiesKey, gameID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *Store) scan(ctx context.Context, pattern string, fn func(key string) error) error {
+	iter := store.redis.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+/*
+removeIfOrphaned runs remove when the game is gone. The game key is watched,
+so a game created in the meantime keeps its entries.
+*/
+func (store *Store) removeIfOrphaned(ctx context.Context, gameID string, removed *int, remove func(redis.Pipeliner)) error {
+	gameKey := "game:" + gameID
+
+	err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
+		exists, err := tx.Exists(ctx, gameKey).Result()
+		if err != nil || exists > 0 {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			remove(pipe)
+			return nil
+		})
+		if err == nil {
+			*removed++
+		}
+		return err
+	}, gameKey)
+
+	if err == redis.TxFailedErr {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGameTTLFollowsTheGamePhase(t *testing.T) {
+	game := newLobby(3)
+	if ttl := gameTTL(game); ttl != LobbyTTL {
+		t.Fatalf("expected lobby TTL, got %v", ttl)
+	}
+
+	game.Started = true
+	if ttl := gameTTL(game); ttl != ActiveGameTTL {
+		t.Fatalf("expected active game TTL, got %v", ttl)
+	}
+
+	game.Finished = true
+	if ttl := gameTTL(game); ttl != FinishedGameTTL {
+		t.Fatalf("expected finished game TTL, got %v", ttl)
+	}
+}
+
+func TestSweepRemovesEntriesOfMissingGames(t *testing.T) {
+	store, server := newTestStore(t)
+
+	live, err := store.CreateGameRoom("ana", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_ = server.Set("joincode:GONE", "missing")
+	_ = server.Set("rejoin:gone", `{"gameId":"missing","playerId":"p1"}`)
+	_, _ = server.ZAdd(publicLobbiesKey, 1, "missing")
+
+	// Another instance is sweeping: the shared keys are left to it.
+	_ = server.Set(janitorLockKey, "other")
+	report, err := store.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if report.JoinCodesRemoved+report.RejoinCodesRemoved+report.LobbiesRemoved != 0 || !server.Exists("joincode:GONE") {
+		t.Fatalf("expected nothing swept without the lock, got %+v", report)
+	}
+
+	server.Del(janitorLockKey)
+	report, err = store.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if report.JoinCodesRemoved != 1 || report.RejoinCodesRemoved != 1 || report.LobbiesRemoved != 1 {
+		t.Fatalf("expected one orphan of each kind removed, got %+v", report)
+	}
+	if server.Exists("joincode:GONE") || server.Exists("rejoin:gone") {
+		t.Fatalf("expected the orphaned codes to be removed")
+	}
+	if !server.Exists("joincode:"+live.Game.JoinCode) || !server.Exists("rejoin:"+live.RejoinCode) {
+		t.Fatalf("expected the live game's codes to be kept")
+	}
+	if lobbies, _ := server.ZMembers(publicLobbiesKey); len(lobbies) != 1 || lobbies[0] != live.Game.GameID {
+		t.Fatalf("expected only the live lobby listed, got %v", lobbies)
+	}
+	if server.Exists(janitorLockKey) {
+		t.Fatalf("expected the janitor lock to be released")
+	}
+}
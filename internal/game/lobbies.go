@@ -0,0 +1,174 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	publicLobbiesKey = "lobbies:public"
+
+	// How many open rooms GET /rooms and quick play look at.
+	PublicLobbyPageSize = 50
+)
+
+type PublicLobby struct {
+	GameID        string       `json:"gameId"`
+	JoinCode      string       `json:"joinCode"`
+	AdminNickname string       `json:"adminNickname"`
+	PlayerCount   int          `json:"playerCount"`
+	MaxPlayers    int          `json:"maxPlayers"`
+	Settings      RoomSettings `json:"settings"`
+	CreatedAt     time.Time    `json:"createdAt"`
+}
+
+func isOpenPublicLobby(game *Game) bool {
+	return !game.Started &&
+		!game.Finished &&
+		!game.Settings.Private &&
+		len(game.Players) < game.Settings.MaxPlayers
+}
+
+/*
+syncPublicLobby keeps the lobbies:public sorted set in step with the room.
+Open public rooms are scored by player count, so the fullest come first;
+anything else is removed. Failures are logged only: the set is an index and
+ListPublicLobbies and the janitor clean up entries that drifted.
+*/
+func (store *Store) syncPublicLobby(ctx context.Context, game *Game) {
+	var err error
+	if isOpenPublicLobby(game) {
+		err = store.redis.ZAdd(ctx, publicLobbiesKey, redis.Z{
+			Score:  float64(len(game.Players)),
+			Member: game.ID,
+		}).Err()
+	} else {
+		err = store.redis.ZRem(ctx, publicLobbiesKey, game.ID).Err()
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update public lobby index.")
+	}
+}
+
+// ListPublicLobbies returns open public rooms, fullest first.
+func (store *Store) ListPublicLobbies() ([]PublicLobby, error) {
+	ctx := context.Background()
+
+	games, err := store.openPublicGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lobbies := make([]PublicLobby, 0, len(games))
+	for _, game := range games {
+		lobbies = append(lobbies, projectPublicLobby(game))
+	}
+
+	return lobbies, nil
+}
+
+/*
+QuickPlay seats the player in the fullest open public room that will take
+them, or opens a new public room with default settings when none will.
+*/
+func (store *Store) QuickPlay(nickname string, userID string) (*OnboardingResult, error) {
+	ctx := context.Background()
+
+	games, err := store.openPublicGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, game := range games {
+		result, err := store.joinGame(ctx, game.ID, nickname, userID)
+		if err == nil {
+			return result, nil
+		}
+
+		if !roomWontTakePlayer(err) {
+			return nil, err
+		}
+	}
+
+	return store.CreateGameRoom(nickname, nil, userID)
+}
+
+// roomWontTakePlayer reports the join errors after which QuickPlay tries the next room.
+func roomWontTakePlayer(err error) bool {
+	for _, skipped := range []error{ErrRoomFull, ErrAlreadyStarted, ErrPlayerAlreadyJoined, ErrUserAlreadyJoined, ErrGameNotFound} {
+		if errors.Is(err, skipped) {
+			return true
+		}
+	}
+	return false
+}
+
+func (store *Store) openPublicGames(ctx context.Context) ([]*Game, error) {
+	gameIDs, err := store.redis.ZRevRange(ctx, publicLobbiesKey, 0, PublicLobbyPageSize-1).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list public lobbies.")
+		return nil, err
+	}
+	if len(gameIDs) == 0 {
+		return []*Game{}, nil
+	}
+
+	keys := make([]string, 0, len(gameIDs))
+	for _, gameID := range gameIDs {
+		keys = append(keys, "game:"+gameID)
+	}
+
+	values, err := store.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load public lobbies.")
+		return nil, err
+	}
+
+	games := make([]*Game, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			_ = store.redis.ZRem(ctx, publicLobbiesKey, gameIDs[i]).Err()
+			continue
+		}
+
+		game, err := decodeGame([]byte(data))
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to unmarshal game.")
+			continue
+		}
+
+		if !isOpenPublicLobby(game) {
+			store.syncPublicLobby(ctx, game)
+			continue
+		}
+
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+func projectPublicLobby(game *Game) PublicLobby {
+	adminNickname := ""
+	for _, player := range game.Players {
+		if player.ID == game.AdminID {
+			adminNickname = player.Nickname
+		}
+	}
+
+	return PublicLobby{
+		GameID:        game.ID,
+		JoinCode:      game.JoinCode,
+		AdminNickname: adminNickname,
+		PlayerCount:   len(game.Players),
+		MaxPlayers:    game.Settings.MaxPlayers,
+		Settings:      game.Settings,
+		CreatedAt:     game.CreatedAt,
+	}
+}
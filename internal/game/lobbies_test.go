@@ -0,0 +1,42 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOnlyOpenPublicRoomsAreListed(t *testing.T) {
+	game := newLobby(3)
+	game.Settings.MaxPlayers = 4
+
+	if !isOpenPublicLobby(game) {
+		t.Fatal("expected a public room with free seats to be listed")
+	}
+
+	game.Settings.Private = true
+	if isOpenPublicLobby(game) {
+		t.Fatal("expected a private room to stay unlisted")
+	}
+
+	game.Settings.Private = false
+	game.Players = append(game.Players, buildNewPlayer("late", 0))
+	if isOpenPublicLobby(game) {
+		t.Fatal("expected a full room to be unlisted")
+	}
+
+	game.Players = game.Players[:3]
+	game.Started = true
+	if isOpenPublicLobby(game) {
+		t.Fatal("expected a started game to be unlisted")
+	}
+}
+
+func TestQuickPlaySkipsRoomsBehindWrappedErrors(t *testing.T) {
+	if !roomWontTakePlayer(fmt.Errorf("%w: table 3", ErrRoomFull)) {
+		t.Fatal("expected a wrapped full room to be skipped")
+	}
+	if roomWontTakePlayer(errors.New("redis down")) {
+		t.Fatal("expected other errors to stop quick play")
+	}
+}
@@ -0,0 +1,146 @@
+package game
+
+/*
+Translator renders the message with the given id from locales/ in language,
+filling it in with data. It returns the id itself when there is no such
+message, as go-i18n does.
+*/
+type Translator func(language string, id string, data map[string]any) string
+
+// translator is set up by the app; without one, events carry no message.
+var translator Translator
+
+func SetTranslator(translate Translator) {
+	translator = translate
+}
+
+func translate(language string, id string, data map[string]any) string {
+	if translator == nil {
+		return ""
+	}
+	if message := translator(language, id, data); message != id {
+		return message
+	}
+	return ""
+}
+
+/*
+describeEvent is the line a client shows for the event in its game log, as
+"event.<type>" in locales/. Players are named by nickname; events that read
+differently depending on how they went pick a variant, like
+"event.action_canceled.blocked".
+*/
+func describeEvent(
+	language string,
+	players []PlayerPublicInfo,
+	eventType string,
+	payload map[string]any,
+) string {
+	if translator == nil {
+		return ""
+	}
+
+	id := "event." + eventType
+	data := map[string]any{}
+
+	name := func(key string) string {
+		return nicknameOf(players, payload[key])
+	}
+	action := func(actionName string) string {
+		if message := translate(language, "action."+actionName, nil); message != "" {
+			return message
+		}
+		return actionName
+	}
+
+	switch eventType {
+	case "player_joined":
+		if player, ok := payload["newPlayer"].(*Player); ok {
+			data["player"] = player.Nickname
+		}
+	case "player_kicked":
+		data["player"], _ = payload["nickname"].(string)
+	case "player_rejoined", "influences_selected", "exchange_completed", "player_eliminated":
+		data["player"] = name("playerId")
+	case "influence_draft":
+		data["choose"] = payload["choose"]
+	case "action_declared":
+		declared, _ := payload["actionPayload"].(DeclareActionPayload)
+		data["actor"] = declared.ActorPlayerNickname
+		data["action"] = action(declared.ActionName)
+		if declared.TargetPlayerNickname != nil {
+			data["target"] = *declared.TargetPlayerNickname
+			id += ".targeted"
+		}
+	case "action_blocked":
+		data["blocker"] = name("blockerId")
+		data["role"] = payload["role"]
+	case "action_challenged":
+		data["challenger"] = name("challengerId")
+		data["claimant"] = name("claimantId")
+		data["role"] = payload["role"]
+		if holds, _ := payload["claimHolds"].(bool); holds {
+			id += ".held"
+		} else {
+			id += ".bluff"
+		}
+	case "action_canceled":
+		data["actor"] = name("actorId")
+		data["action"] = action(stringOf(payload["actionName"]))
+		id += "." + stringOf(payload["reason"])
+	case "action_resolved":
+		data["actor"] = name("actorId")
+		data["action"] = action(stringOf(payload["actionName"]))
+	case "influence_lost":
+		data["player"] = name("playerId")
+		data["role"] = payload["role"]
+	case "game_finished":
+		data["winner"] = name("winnerId")
+	case "examine_result":
+		data["target"] = name("targetId")
+		if card, ok := payload["card"].(Influence); ok {
+			data["role"] = card.Role
+		}
+	case "influence_swapped":
+		if lost, ok := payload["lost"].(Influence); ok {
+			data["lost"] = lost.Role
+		}
+		if received, ok := payload["received"].(Influence); ok {
+			data["received"] = received.Role
+		}
+	case "examination_completed":
+		data["examiner"] = name("examinerId")
+		data["target"] = name("targetId")
+		if swapped, _ := payload["forcedSwap"].(bool); swapped {
+			id += ".swapped"
+		} else {
+			id += ".kept"
+		}
+	case "exchange_options":
+		data["keep"] = payload["keep"]
+	}
+
+	return translate(language, id, data)
+}
+
+func nicknameOf(players []PlayerPublicInfo, playerID any) string {
+	id := stringOf(playerID)
+	for _, player := range players {
+		if player.ID == id {
+			return player.Nickname
+		}
+	}
+	return ""
+}
+
+func stringOf(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case *string:
+		if value != nil {
+			return *value
+		}
+	}
+	return ""
+}
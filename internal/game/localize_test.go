@@ -0,0 +1,72 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// A translator that spells out the message id and its data.
+func spellingTranslator(language string, id string, data map[string]any) string {
+	if strings.HasPrefix(id, "action.") {
+		return id
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, fmt.Sprintf("%s=%v", key, data[key]))
+	}
+	sort.Strings(keys)
+
+	return language + " " + id + " " + strings.Join(keys, " ")
+}
+
+func TestDescribeEventNamesPlayersAndPicksVariants(t *testing.T) {
+	SetTranslator(spellingTranslator)
+	defer SetTranslator(nil)
+
+	players := []PlayerPublicInfo{
+		{ID: "p1", Nickname: "ana"},
+		{ID: "p2", Nickname: "bia"},
+	}
+
+	cases := []struct {
+		eventType string
+		payload   map[string]any
+		expected  string
+	}{
+		{
+			"action_challenged",
+			map[string]any{"challengerId": "p2", "claimantId": "p1", "role": "Duke", "claimHolds": false},
+			"pt-BR event.action_challenged.bluff challenger=bia claimant=ana role=Duke",
+		},
+		{
+			"action_canceled",
+			map[string]any{"actorId": "p1", "actionName": "steal", "reason": "blocked"},
+			"pt-BR event.action_canceled.blocked action=steal actor=ana",
+		},
+		{
+			"game_finished",
+			map[string]any{"winnerId": "p2"},
+			"pt-BR event.game_finished winner=bia",
+		},
+	}
+
+	for _, c := range cases {
+		if got := describeEvent("pt-BR", players, c.eventType, c.payload); got != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.eventType, c.expected, got)
+		}
+	}
+}
+
+func TestDescribeEventWithoutTranslationIsEmpty(t *testing.T) {
+	SetTranslator(func(language string, id string, data map[string]any) string {
+		return id
+	})
+	defer SetTranslator(nil)
+
+	if got := describeEvent("es", nil, "game_started", nil); got != "" {
+		t.Fatalf("expected no message, got %q", got)
+	}
+}
@@ -3,13 +3,16 @@ package game
 import "time"
 
 type ActionType struct {
-	name            string
-	isImmediate     bool
-	isBlockable     bool
-	isContestable   bool
-	requiresTarget  bool
-	bloackableRoles []Influence
-	targetPlayerID  *string
+	name             string
+	cost             int
+	isImmediate      bool
+	isBlockable      bool
+	isContestable    bool
+	requiresTarget   bool
+	optionalTarget   bool
+	invertedClaimOf  string // the actor claims NOT to hold the role granting this action
+	onlyTargetBlocks bool
+	variant          string
 }
 
 type DeclareActionPayload struct {
@@ -23,6 +26,8 @@ type DeclareActionPayload struct {
 	IsImmediate          bool     `json:"isImmediate"`
 	BlockableRoles       []string `json:"blockableRoles"`
 	IsContestable        bool     `json:"isContestable"`
+	ClaimedRole          string   `json:"claimedRole,omitempty"`
+	InvertedClaim        bool     `json:"invertedClaim,omitempty"`
 }
 
 type Influence struct {
@@ -36,6 +41,11 @@ type Player struct {
 	Coins      int         `json:"coins"`
 	Alive      bool        `json:"alive"`
 	Influences []Influence `json:"influences"`
+	Allegiance string      `json:"allegiance,omitempty"`
+	IsBot      bool        `json:"isBot,omitempty"`
+	Strategy   string      `json:"strategy,omitempty"`
+	UserID     string      `json:"userId,omitempty"`   // set when the player is signed in to an account
+	Language   string      `json:"language,omitempty"` // preferred language for messages; "" follows Accept-Language
 }
 
 type Game struct {
@@ -47,13 +57,49 @@ type Game struct {
 	TurnIndex int
 	Started   bool
 	Finished  bool
+	Settings  RoomSettings
 
 	Deck []Influence `json:"deck"`
+
+	// Cards dealt privately to each player during the two-player draft.
+	Drafts map[string][]Influence `json:"drafts,omitempty"`
+
+	PendingAction       *PendingAction  `json:"pendingAction,omitempty"`
+	PendingLosses       []InfluenceLoss `json:"pendingLosses,omitempty"`
+	EliminatedPlayerIDs []string        `json:"eliminatedPlayerIds,omitempty"`
+	WinnerID            *string         `json:"winnerId,omitempty"`
+	TreasuryReserve     int             `json:"treasuryReserve"`
+
+	// Role definitions snapshotted from the room's role pack at setup.
+	Roles []RoleDefinition `json:"roles,omitempty"`
+
+	PendingExchange    *PendingExchange    `json:"pendingExchange,omitempty"`
+	PendingExamination *PendingExamination `json:"pendingExamination,omitempty"`
+
+	// One record per declared action, in order; see ActionRecord.
+	History []ActionRecord `json:"history,omitempty"`
+
+	// The seating before the deal and every move since, to replay the game.
+	Setup    *ReplaySetup `json:"setup,omitempty"`
+	Commands []Command    `json:"commands,omitempty"`
+
+	// Every shuffle and random pick is derived from Seed and the number of
+	// draws made so far; see Game.random.
+	Seed        int64 `json:"seed"`
+	RandomDraws int   `json:"randomDraws"`
+
+	// The player who took the first turn. Set before the deal to choose who
+	// starts, as rematches do; otherwise the seed decides.
+	StartingPlayerID string `json:"startingPlayerId,omitempty"`
+
+	// The game started from this one with POST /games/{gameID}/rematch.
+	RematchID string `json:"rematchId,omitempty"`
 }
 
 type PlayerSession struct {
 	PlayerID string `json:"playerId"`
 	GameID   string `json:"gameId"`
+	Role     string `json:"role"`
 }
 
 /*
@@ -71,30 +117,85 @@ type PlayerPublicInfo struct {
 	Coins      int               `json:"coins"`
 	Alive      bool              `json:"alive"`
 	Influences []PublicInfluence `json:"influences"`
+	Allegiance string            `json:"allegiance,omitempty"`
+	IsBot      bool              `json:"isBot,omitempty"`
+	UserID     string            `json:"userId,omitempty"`
 }
 
 type PublicGameState struct {
-	GameID     string             `json:"gameID"`
-	JoinCode   string             `json:"joinCode"`
-	Started    bool               `json:"started"`
-	AdminID    string             `json:"adminID"`
-	Finished   bool               `json:"finished"`
-	TurnIndex  int                `json:"turnIndex"`
-	Players    []PlayerPublicInfo `json:"players"`
-	DeckLength int                `json:"deckLength"`
+	GameID            string             `json:"gameID"`
+	JoinCode          string             `json:"joinCode"`
+	Started           bool               `json:"started"`
+	AdminID           string             `json:"adminID"`
+	Finished          bool               `json:"finished"`
+	TurnIndex         int                `json:"turnIndex"`
+	Players           []PlayerPublicInfo `json:"players"`
+	DeckLength        int                `json:"deckLength"`
+	Settings          RoomSettings       `json:"settings"`
+	DraftingPlayerIDs []string           `json:"draftingPlayerIds"`
+	PendingAction     *PendingAction     `json:"pendingAction,omitempty"`
+	PendingLosses     []InfluenceLoss    `json:"pendingLosses"`
+	WinnerID          *string            `json:"winnerId,omitempty"`
+	TreasuryReserve   int                `json:"treasuryReserve"`
+
+	ExchangingPlayerID *string            `json:"exchangingPlayerId,omitempty"`
+	Examination        *PublicExamination `json:"examination,omitempty"`
+
+	RematchID string `json:"rematchId,omitempty"`
 }
 
 type PendingAction struct {
-	ID        string     `json:"id"`
-	ActorID   string     `json:"actorId"`
-	Action    ActionType `json:"action"`
-	TargetID  *string    `json:"targetId,omitempty"`
-	CreatedAt time.Time  `json:"createdAt"`
-	Status    string     `json:"status"` // "declared", "resolved", "canceled"...
+	ID                string        `json:"id"`
+	ActorID           string        `json:"actorId"`
+	ActionName        string        `json:"actionName"`
+	TargetID          *string       `json:"targetId,omitempty"`
+	ClaimedRole       string        `json:"claimedRole,omitempty"`
+	InvertedClaim     bool          `json:"invertedClaim,omitempty"`
+	CreatedAt         time.Time     `json:"createdAt"`
+	Status            string        `json:"status"` // "declared", "blocked"
+	ChallengeResolved bool          `json:"challengeResolved"`
+	Block             *PendingBlock `json:"block,omitempty"`
+	PassedPlayerIDs   []string      `json:"passedPlayerIds"`
+}
+
+type PendingBlock struct {
+	BlockerID string `json:"blockerId"`
+	Role      string `json:"role"`
+}
+
+type PendingExchange struct {
+	PlayerID string      `json:"playerId"`
+	Drawn    []Influence `json:"drawn"`
+}
+
+type PendingExamination struct {
+	ExaminerID string `json:"examinerId"`
+	TargetID   string `json:"targetId"`
+	Shown      bool   `json:"shown"`     // the target has chosen the card
+	CardIndex  int    `json:"cardIndex"` // only set once shown
+}
+
+type PublicExamination struct {
+	ExaminerID string `json:"examinerId"`
+	TargetID   string `json:"targetId"`
+	Shown      bool   `json:"shown"`
+}
+
+type InfluenceLoss struct {
+	PlayerID string `json:"playerId"`
+	Reason   string `json:"reason"` // "coup", "assassinate", "challenge_lost"...
+}
+
+type SessionResult struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 type OnboardingResult struct {
 	Game   *PublicGameState `json:"game"`
 	Player *Player          `json:"player"`
 	Token  string           `json:"token"`
+
+	// Secret that gets the player back into this seat from another device.
+	RejoinCode string `json:"rejoinCode,omitempty"`
 }
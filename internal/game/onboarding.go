@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"influence_game/internal/realtime"
+	"influence_game/internal/sessions"
 	"math/rand"
 	"time"
 
@@ -12,21 +14,43 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func (store *Store) CreateGameRoom(adminNickname string) (*OnboardingResult, error) {
-	adminPlayer := buildNewPlayer(adminNickname)
+/*
+CreateGameRoom opens a lobby with the admin seated. userID is the admin's
+account, or empty for an anonymous player; the same goes for Join.
+*/
+func (store *Store) CreateGameRoom(
+	adminNickname string,
+	settingsPatch *RoomSettingsPatch,
+	userID string,
+) (*OnboardingResult, error) {
+	settings := settingsPatch.Apply(DefaultRoomSettings())
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	adminPlayer := buildNewPlayer(adminNickname, settings.StartingCoins)
+	adminPlayer.UserID = userID
 
-	newGame, err := store.buildNewGame(adminPlayer)
+	newGame, err := store.buildNewGame(adminPlayer, settings)
 	if err != nil {
 		return nil, err
 	}
 
+	ctx := context.Background()
+
 	if err := store.saveGameToRedis(newGame); err != nil {
-		ctx := context.Background()
 		_ = store.redis.Del(ctx, "joincode:"+newGame.JoinCode).Err()
 		return nil, err
 	}
 
-	sessionToken, err := store.CreatePlayerSession(newGame.ID, adminPlayer.ID)
+	store.syncPublicLobby(ctx, newGame)
+
+	sessionToken, err := store.CreatePlayerSession(newGame.ID, adminPlayer.ID, sessions.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	rejoinCode, err := store.createRejoinCode(newGame.ID, adminPlayer.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -34,23 +58,24 @@ func (store *Store) CreateGameRoom(adminNickname string) (*OnboardingResult, err
 	publicState := ProjectPublicGameState(newGame)
 
 	return &OnboardingResult{
-		Game:   publicState,
-		Player: adminPlayer,
-		Token:  sessionToken,
+		Game:       publicState,
+		Player:     adminPlayer,
+		Token:      sessionToken,
+		RejoinCode: rejoinCode,
 	}, nil
 }
 
-func buildNewPlayer(nickname string) *Player {
+func buildNewPlayer(nickname string, startingCoins int) *Player {
 	return &Player{
 		ID:         uuid.NewString(),
 		Nickname:   nickname,
-		Coins:      2,
+		Coins:      startingCoins,
 		Alive:      true,
 		Influences: []Influence{},
 	}
 }
 
-func (store *Store) buildNewGame(adminPlayer *Player) (*Game, error) {
+func (store *Store) buildNewGame(adminPlayer *Player, settings RoomSettings) (*Game, error) {
 	gameID := uuid.NewString()
 
 	joinCode, err := store.reserveJoinCode(gameID)
@@ -67,6 +92,7 @@ func (store *Store) buildNewGame(adminPlayer *Player) (*Game, error) {
 		TurnIndex: 0,
 		Started:   false,
 		Finished:  false,
+		Settings:  settings,
 		Deck:      []Influence{},
 	}
 
@@ -111,7 +137,7 @@ func (store *Store) saveGameToRedis(newGame *Game) error {
 	redisKey := "game:" + newGame.ID
 	ctx := context.Background()
 
-	if err := store.redis.Set(ctx, redisKey, serializedGame, 0).Err(); err != nil {
+	if err := store.redis.Set(ctx, redisKey, serializedGame, gameTTL(newGame)).Err(); err != nil {
 		log.Error().Err(err).Msg("Failed to save game to Redis.")
 		return err
 	}
@@ -119,7 +145,7 @@ func (store *Store) saveGameToRedis(newGame *Game) error {
 	return nil
 }
 
-func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
+func (store *Store) Join(joinCode, nickname, userID string) (*OnboardingResult, error) {
 	ctx := context.Background()
 
 	joinKey := "joincode:" + joinCode
@@ -131,6 +157,15 @@ func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
 		return nil, err
 	}
 
+	return store.joinGame(ctx, gameID, nickname, userID)
+}
+
+func (store *Store) joinGame(
+	ctx context.Context,
+	gameID string,
+	nickname string,
+	userID string,
+) (*OnboardingResult, error) {
 	var joinedPlayer *Player
 
 	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
@@ -139,13 +174,21 @@ func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
 			return ErrAlreadyStarted
 		}
 
+		if len(game.Players) >= game.Settings.MaxPlayers {
+			return ErrRoomFull
+		}
+
 		for _, p := range game.Players {
 			if p.Nickname == nickname {
 				return ErrPlayerAlreadyJoined
 			}
+			if userID != "" && p.UserID == userID {
+				return ErrUserAlreadyJoined
+			}
 		}
 
-		joinedPlayer = buildNewPlayer(nickname)
+		joinedPlayer = buildNewPlayer(nickname, game.Settings.StartingCoins)
+		joinedPlayer.UserID = userID
 		game.Players = append(game.Players, joinedPlayer)
 
 		return nil
@@ -155,11 +198,18 @@ func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
 		return nil, err
 	}
 
-	sessionToken, err := store.CreatePlayerSession(game.ID, joinedPlayer.ID)
+	sessionToken, err := store.CreatePlayerSession(game.ID, joinedPlayer.ID, sessions.RolePlayer)
 	if err != nil {
 		return nil, err
 	}
 
+	rejoinCode, err := store.createRejoinCode(game.ID, joinedPlayer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	store.syncPublicLobby(ctx, game)
+
 	BroadcastEvent(
 		ProjectPublicGameState(game),
 		"player_joined",
@@ -169,8 +219,102 @@ func (store *Store) Join(joinCode, nickname string) (*OnboardingResult, error) {
 	)
 
 	return &OnboardingResult{
-		Game:   ProjectPublicGameState(game),
-		Player: joinedPlayer,
-		Token:  sessionToken,
+		Game:       ProjectPublicGameState(game),
+		Player:     joinedPlayer,
+		Token:      sessionToken,
+		RejoinCode: rejoinCode,
 	}, nil
 }
+
+/*
+KickPlayer removes a player from the lobby and revokes every session token
+issued for their seat, so their client is locked out right away.
+*/
+func (store *Store) KickPlayer(
+	gameID string,
+	kickedPlayerID string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	playerID := session.PlayerID
+	var kickedNickname string
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		if game.Started {
+			return ErrAlreadyStarted
+		}
+		if game.AdminID != playerID {
+			return ErrOnlyAdminCanKick
+		}
+		if kickedPlayerID == playerID {
+			return ErrCannotKickSelf
+		}
+
+		for i, p := range game.Players {
+			if p.ID == kickedPlayerID {
+				kickedNickname = p.Nickname
+				game.Players = append(game.Players[:i], game.Players[i+1:]...)
+				return nil
+			}
+		}
+
+		return ErrPlayerNotFound
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.sessions.RevokeSeat(ctx, gameID, kickedPlayerID, SessionDuration); err != nil {
+		return nil, err
+	}
+
+	store.syncPublicLobby(ctx, game)
+
+	BroadcastEvent(
+		ProjectPublicGameState(game),
+		"player_kicked",
+		map[string]any{
+			"playerId": kickedPlayerID,
+			"nickname": kickedNickname,
+		},
+	)
+
+	realtime.Manager.DisconnectPlayer(gameID, kickedPlayerID)
+
+	return ProjectPublicGameState(game), nil
+}
+
+/*
+DiscardRoom deletes a room that was opened on players' behalf and could not be
+set up, along with its join code and public lobby entry. Its rejoin codes are
+left for the janitor.
+*/
+func (store *Store) DiscardRoom(gameID string) error {
+	ctx := context.Background()
+
+	game, err := store.loadGame(ctx, gameID)
+	if err == ErrGameNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = store.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, "game:"+game.ID)
+		pipe.Del(ctx, "joincode:"+game.JoinCode)
+		pipe.ZRem(ctx, publicLobbiesKey, game.ID)
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to discard game room.")
+	}
+	return err
+}
@@ -10,6 +10,20 @@ func ProjectPublicGameState(game *Game) *PublicGameState {
 		)
 	}
 
+	var exchangingPlayerID *string
+	if game.PendingExchange != nil {
+		exchangingPlayerID = &game.PendingExchange.PlayerID
+	}
+
+	var examination *PublicExamination
+	if game.PendingExamination != nil {
+		examination = &PublicExamination{
+			ExaminerID: game.PendingExamination.ExaminerID,
+			TargetID:   game.PendingExamination.TargetID,
+			Shown:      game.PendingExamination.Shown,
+		}
+	}
+
 	return &PublicGameState{
 		GameID:     game.ID,
 		JoinCode:   game.JoinCode,
@@ -19,7 +33,36 @@ func ProjectPublicGameState(game *Game) *PublicGameState {
 		Players:    playersPublicInfo,
 		AdminID:    game.AdminID,
 		DeckLength: len(game.Deck),
+		Settings:   game.Settings,
+
+		DraftingPlayerIDs: draftingPlayerIDs(game),
+		PendingAction:     game.PendingAction,
+		PendingLosses:     pendingLosses(game),
+		WinnerID:          game.WinnerID,
+		TreasuryReserve:   game.TreasuryReserve,
+
+		ExchangingPlayerID: exchangingPlayerID,
+		Examination:        examination,
+
+		RematchID: game.RematchID,
+	}
+}
+
+func pendingLosses(game *Game) []InfluenceLoss {
+	if game.PendingLosses == nil {
+		return []InfluenceLoss{}
+	}
+	return game.PendingLosses
+}
+
+func draftingPlayerIDs(game *Game) []string {
+	ids := make([]string, 0, len(game.Drafts))
+	for _, player := range game.Players {
+		if _, ok := game.Drafts[player.ID]; ok {
+			ids = append(ids, player.ID)
+		}
 	}
+	return ids
 }
 
 func projectPublicPlayerInfo(player *Player) PlayerPublicInfo {
@@ -45,5 +88,8 @@ func projectPublicPlayerInfo(player *Player) PlayerPublicInfo {
 		Coins:      player.Coins,
 		Alive:      player.Alive,
 		Influences: influences,
+		Allegiance: player.Allegiance,
+		IsBot:      player.IsBot,
+		UserID:     player.UserID,
 	}
 }
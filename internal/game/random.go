@@ -0,0 +1,35 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+/*
+random returns the source for the game's next random draw. Each draw is
+seeded from the game seed and a counter stored with the game, so a game
+replays identically from the same seed no matter how often it was saved and
+loaded in between. The two are hashed together rather than added, so the
+draws of neighbouring seeds do not overlap.
+*/
+func (game *Game) random() *rand.Rand {
+	if game.Seed == 0 {
+		game.Seed = time.Now().UnixNano()
+	}
+
+	source := rand.NewSource(DeriveSeed(game.Seed, game.RandomDraws))
+	game.RandomDraws++
+
+	return rand.New(source)
+}
+
+/*
+DeriveSeed hashes a seed and an index into a new seed with splitmix64, for
+sequences of seeds that must not be shifted copies of one another.
+*/
+func DeriveSeed(seed int64, index int) int64 {
+	z := uint64(seed) + uint64(index+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
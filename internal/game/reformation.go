@@ -0,0 +1,73 @@
+package game
+
+const (
+	AllegianceLoyalist  = "loyalist"
+	AllegianceReformist = "reformist"
+)
+
+/*
+assignAllegiances alternates factions around the table, starting with the
+first player to act, as the Reformation rules suggest.
+*/
+func assignAllegiances(game *Game) {
+	for offset := range game.Players {
+		player := game.Players[(game.TurnIndex+offset)%len(game.Players)]
+		if offset%2 == 0 {
+			player.Allegiance = AllegianceLoyalist
+		} else {
+			player.Allegiance = AllegianceReformist
+		}
+	}
+	game.TreasuryReserve = 0
+}
+
+/*
+violatesFaction reports whether the Reformation rules forbid the actor from
+acting against the other player. Same-faction players are protected until
+every player still alive shares one allegiance.
+*/
+func violatesFaction(game *Game, actor *Player, other *Player) bool {
+	if !game.Settings.HasVariant(VariantReformation) {
+		return false
+	}
+	if actor.Allegiance != other.Allegiance {
+		return false
+	}
+	return !allAliveShareAllegiance(game)
+}
+
+func allAliveShareAllegiance(game *Game) bool {
+	allegiance := ""
+	for _, p := range game.Players {
+		if !p.Alive {
+			continue
+		}
+		if allegiance == "" {
+			allegiance = p.Allegiance
+			continue
+		}
+		if p.Allegiance != allegiance {
+			return false
+		}
+	}
+	return true
+}
+
+func convertCost(actor *Player, target *Player) int {
+	if target == nil || target.ID == actor.ID {
+		return ConvertSelfCost
+	}
+	return ConvertOtherCost
+}
+
+func convertAllegiance(actor *Player, target *Player) {
+	if target == nil {
+		target = actor
+	}
+
+	if target.Allegiance == AllegianceLoyalist {
+		target.Allegiance = AllegianceReformist
+	} else {
+		target.Allegiance = AllegianceLoyalist
+	}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"influence_game/internal/realtime"
+	"influence_game/internal/sessions"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const rejoinCodeLength = 12
+
+type rejoinSeat struct {
+	GameID   string `json:"gameId"`
+	PlayerID string `json:"playerId"`
+}
+
+/*
+createRejoinCode reserves a secret that lets a player take their seat back
+from another device. Unlike join codes it is drawn from crypto/rand, since
+knowing it is enough to play as that player.
+*/
+func (store *Store) createRejoinCode(gameID string, playerID string) (string, error) {
+	ctx := context.Background()
+
+	data, err := json.Marshal(rejoinSeat{GameID: gameID, PlayerID: playerID})
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		b := make([]byte, rejoinCodeLength)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		for i := range b {
+			b[i] = letters[int(b[i])%len(letters)]
+		}
+		code := string(b)
+
+		ok, err := store.redis.SetNX(ctx, "rejoin:"+code, data, RejoinCodeTTL).Result()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to save rejoin code to Redis.")
+			return "", err
+		}
+
+		if ok {
+			return code, nil
+		}
+	}
+}
+
+/*
+Rejoin hands a seat back to whoever holds its rejoin code. Every token issued
+for the seat so far is revoked and its sockets closed, so a lost or stolen
+device stops working as soon as the player is back. The code is used up on the
+way: the player gets a new one with their session, so a code that leaked with
+an old device cannot take the seat again.
+*/
+func (store *Store) Rejoin(rejoinCode string) (*OnboardingResult, error) {
+	ctx := context.Background()
+
+	data, err := store.redis.GetDel(ctx, "rejoin:"+rejoinCode).Bytes()
+	if err == redis.Nil {
+		return nil, ErrInvalidRejoinCode
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get rejoin code from Redis.")
+		return nil, err
+	}
+
+	var seat rejoinSeat
+	if err := json.Unmarshal(data, &seat); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal rejoin code.")
+		return nil, err
+	}
+
+	game, err := store.loadGame(ctx, seat.GameID)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := findPlayerByID(game, seat.PlayerID)
+	if err != nil {
+		return nil, ErrInvalidRejoinCode
+	}
+
+	role := sessions.RolePlayer
+	if game.AdminID == player.ID {
+		role = sessions.RoleAdmin
+	}
+
+	if err := store.sessions.RevokeSeat(ctx, game.ID, player.ID, SessionDuration); err != nil {
+		return nil, err
+	}
+	realtime.Manager.DisconnectPlayer(game.ID, player.ID)
+
+	sessionToken, err := store.CreatePlayerSession(game.ID, player.ID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	newRejoinCode, err := store.createRejoinCode(game.ID, player.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	BroadcastEvent(
+		ProjectPublicGameState(game),
+		"player_rejoined",
+		map[string]any{
+			"playerId": player.ID,
+		},
+	)
+
+	return &OnboardingResult{
+		Game:       ProjectPublicGameState(game),
+		Player:     player,
+		Token:      sessionToken,
+		RejoinCode: newRejoinCode,
+	}, nil
+}
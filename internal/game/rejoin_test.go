@@ -0,0 +1,48 @@
+package game
+
+import (
+	"errors"
+	"influence_game/internal/sessions"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestStore builds a store on an in-memory Redis that lives as long as the test.
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewStore(client, sessions.NewVerifier([]byte("secret"), client)), server
+}
+
+func TestRejoinCodeWorksOnce(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	created, err := store.CreateGameRoom("ana", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	rejoined, err := store.Rejoin(created.RejoinCode)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if rejoined.Player.ID != created.Player.ID {
+		t.Fatalf("expected the admin's seat back, got %s", rejoined.Player.ID)
+	}
+	if rejoined.RejoinCode == "" || rejoined.RejoinCode == created.RejoinCode {
+		t.Fatalf("expected a new rejoin code, got %q", rejoined.RejoinCode)
+	}
+
+	if _, err := store.Rejoin(created.RejoinCode); !errors.Is(err, ErrInvalidRejoinCode) {
+		t.Fatalf("expected the used code to be rejected, got %v", err)
+	}
+	if _, err := store.Rejoin(rejoined.RejoinCode); err != nil {
+		t.Fatalf("expected the new code to work, got %v", err)
+	}
+}
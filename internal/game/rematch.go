@@ -0,0 +1,275 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"influence_game/internal/realtime"
+	"influence_game/internal/sessions"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+var errRematchTaken = errors.New("rematch_taken")
+
+/*
+Rematch deals a finished game again with the same players, seating and
+settings, the first turn passing to the next seat. The first player to ask
+creates it; everyone after is seated in that same rematch. Sockets still open
+on the finished game move over, and each player is sent a session for the
+new game. Once a player has been handed their seat in the rematch, their
+tokens for the finished game are revoked.
+*/
+func (store *Store) Rematch(gameID string, sessionToken string) (*OnboardingResult, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := store.loadGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !previous.Finished {
+		return nil, ErrGameNotFinished
+	}
+
+	if previous.RematchID == "" {
+		if err := store.createRematch(ctx, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	rematch, err := store.loadGame(ctx, previous.RematchID)
+	if err != nil {
+		return nil, err
+	}
+
+	seat, err := store.seatInRematch(ctx, rematch, session.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.sessions.RevokeSeat(ctx, previous.ID, session.PlayerID, SessionDuration); err != nil {
+		return nil, err
+	}
+
+	return seat, nil
+}
+
+// createRematch deals the rematch and links it from the finished game.
+func (store *Store) createRematch(ctx context.Context, previous *Game) error {
+	rematch, err := newRematchGame(previous)
+	if err != nil {
+		return err
+	}
+
+	joinCode, err := store.reserveJoinCode(rematch.ID)
+	if err != nil {
+		return err
+	}
+	rematch.JoinCode = joinCode
+
+	if err := store.saveGameToRedis(rematch); err != nil {
+		_ = store.redis.Del(ctx, "joincode:"+joinCode).Err()
+		return err
+	}
+
+	_, err = store.withGameLock(ctx, previous.ID, func(game *Game) error {
+		if game.RematchID != "" {
+			previous.RematchID = game.RematchID
+			return errRematchTaken
+		}
+		game.RematchID = rematch.ID
+		return nil
+	})
+
+	if errors.Is(err, errRematchTaken) {
+		_ = store.redis.Del(ctx, "game:"+rematch.ID, "joincode:"+joinCode).Err()
+		return nil
+	}
+	if err != nil {
+		_ = store.redis.Del(ctx, "game:"+rematch.ID, "joincode:"+joinCode).Err()
+		return err
+	}
+	previous.RematchID = rematch.ID
+
+	store.notifyGameUpdated(rematch)
+
+	moved := realtime.Manager.MoveGame(previous.ID, rematch.ID)
+
+	BroadcastEvent(
+		ProjectPublicGameState(rematch),
+		"rematch_created",
+		map[string]any{
+			"previousGameId": previous.ID,
+		},
+	)
+
+	for _, player := range rematch.Players {
+		if player.IsBot {
+			continue
+		}
+
+		seat, err := store.seatInRematch(ctx, rematch, player.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to migrate session to rematch.")
+			continue
+		}
+
+		SendToPlayer(
+			player.ID,
+			"session_migrated",
+			rematch.ID,
+			map[string]any{
+				"token":      seat.Token,
+				"rejoinCode": seat.RejoinCode,
+			},
+		)
+
+		// Players whose sockets moved have their seat now; the others
+		// collect it through Rematch with their old token.
+		if contains(moved, player.ID) {
+			if err := store.sessions.RevokeSeat(ctx, previous.ID, player.ID, SessionDuration); err != nil {
+				log.Error().Err(err).Msg("Failed to revoke finished game session.")
+			}
+		}
+	}
+
+	for draftingPlayerID, dealt := range rematch.Drafts {
+		SendToPlayer(
+			draftingPlayerID,
+			"influence_draft",
+			rematch.ID,
+			map[string]any{
+				"dealt":  dealt,
+				"choose": 2,
+			},
+		)
+	}
+
+	return nil
+}
+
+type rematchSeat struct {
+	Token      string `json:"token"`
+	RejoinCode string `json:"rejoinCode"`
+}
+
+/*
+seatInRematch hands out the player's session and rejoin code for the rematch.
+They are issued the first time they are asked for and kept, so every later
+request gets the same seat rather than another one.
+*/
+func (store *Store) seatInRematch(ctx context.Context, rematch *Game, playerID string) (*OnboardingResult, error) {
+	player, err := findPlayerByID(rematch, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	seat, err := store.rematchSeatFor(ctx, rematch, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OnboardingResult{
+		Game:       ProjectPublicGameState(rematch),
+		Player:     player,
+		Token:      seat.Token,
+		RejoinCode: seat.RejoinCode,
+	}, nil
+}
+
+// rematchSeatFor returns the player's stored seat, issuing it if there is none yet.
+func (store *Store) rematchSeatFor(ctx context.Context, rematch *Game, playerID string) (*rematchSeat, error) {
+	key := rematchSeatKey(rematch.ID, playerID)
+
+	var seat rematchSeat
+	data, err := store.redis.Get(ctx, key).Bytes()
+	if err == nil {
+		err = json.Unmarshal(data, &seat)
+		return &seat, err
+	}
+	if err != redis.Nil {
+		return nil, err
+	}
+
+	role := sessions.RolePlayer
+	if rematch.AdminID == playerID {
+		role = sessions.RoleAdmin
+	}
+
+	seat.Token, err = store.CreatePlayerSession(rematch.ID, playerID, role)
+	if err != nil {
+		return nil, err
+	}
+	seat.RejoinCode, err = store.createRejoinCode(rematch.ID, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(seat)
+	if err != nil {
+		return nil, err
+	}
+	issued, err := store.redis.SetNX(ctx, key, data, SessionDuration).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !issued {
+		// Someone else seated the player first; theirs is the seat.
+		_ = store.redis.Del(ctx, "rejoin:"+seat.RejoinCode).Err()
+		return store.rematchSeatFor(ctx, rematch, playerID)
+	}
+
+	return &seat, nil
+}
+
+func rematchSeatKey(rematchID string, playerID string) string {
+	return "rematch:seat:" + rematchID + ":" + playerID
+}
+
+/*
+newRematchGame seats the finished game's players in the same order, under
+the same IDs so their sockets carry over, and deals. The first turn goes to
+the seat after the one that started the finished game.
+*/
+func newRematchGame(previous *Game) (*Game, error) {
+	rematch := &Game{
+		ID:        uuid.NewString(),
+		CreatedAt: time.Now(),
+		AdminID:   previous.AdminID,
+		Settings:  previous.Settings,
+		Deck:      []Influence{},
+	}
+
+	starting := 0
+	for i, player := range previous.Players {
+		if player.ID == previous.StartingPlayerID {
+			starting = (i + 1) % len(previous.Players)
+		}
+
+		seated := buildNewPlayer(player.Nickname, previous.Settings.StartingCoins)
+		seated.ID = player.ID
+		seated.UserID = player.UserID
+		seated.IsBot = player.IsBot
+		seated.Strategy = player.Strategy
+		seated.Language = player.Language
+		rematch.Players = append(rematch.Players, seated)
+	}
+	if len(rematch.Players) == 0 {
+		return nil, ErrNeedAtLeastTwoPlayers
+	}
+	rematch.StartingPlayerID = rematch.Players[starting].ID
+
+	if err := SetupNewGame(rematch); err != nil {
+		return nil, err
+	}
+
+	return rematch, nil
+}
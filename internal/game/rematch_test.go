@@ -0,0 +1,104 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"influence_game/internal/sessions"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRematchKeepsSeatsAndRotatesTheStart(t *testing.T) {
+	previous, err := NewLocalGame(4, DefaultRoomSettings(), 42)
+	if err != nil {
+		t.Fatalf("unexpected setup error %v", err)
+	}
+	playRandomly(t, previous, rand.New(rand.NewSource(7)), 500)
+
+	rematch, err := newRematchGame(previous)
+	if err != nil {
+		t.Fatalf("unexpected rematch error %v", err)
+	}
+
+	if !rematch.Started || rematch.Finished {
+		t.Fatalf("expected the rematch to be dealt and under way")
+	}
+	for i, player := range rematch.Players {
+		if player.ID != previous.Players[i].ID || player.Nickname != previous.Players[i].Nickname {
+			t.Fatalf("seat %d: expected %s, got %s", i, previous.Players[i].Nickname, player.Nickname)
+		}
+		if !player.Alive || len(player.Influences) != 2 || player.Coins != rematch.Settings.StartingCoins {
+			t.Fatalf("seat %d: expected a fresh hand, got %+v", i, player)
+		}
+	}
+
+	first := 0
+	for i, player := range previous.Players {
+		if player.ID == previous.StartingPlayerID {
+			first = i
+		}
+	}
+	if want := (first + 1) % len(previous.Players); rematch.TurnIndex != want {
+		t.Fatalf("expected seat %d to start, got %d", want, rematch.TurnIndex)
+	}
+
+	// The chosen start survives a replay of the rematch.
+	replayLog, _ := NewReplayLog(rematch)
+	steps, err := Replay(replayLog)
+	if err != nil || steps[0].State.TurnIndex != rematch.TurnIndex {
+		t.Fatalf("expected the replay to start at seat %d (%v)", rematch.TurnIndex, err)
+	}
+}
+
+func TestRematchSeatsAreIssuedOnce(t *testing.T) {
+	store, server := newTestStore(t)
+
+	previous, err := NewLocalGame(3, DefaultRoomSettings(), 42)
+	if err != nil {
+		t.Fatalf("unexpected setup error %v", err)
+	}
+	playRandomly(t, previous, rand.New(rand.NewSource(7)), 500)
+	if err := store.saveGameToRedis(previous); err != nil {
+		t.Fatalf("unexpected save error %v", err)
+	}
+
+	tokens := map[string]string{}
+	for _, player := range previous.Players {
+		tokens[player.ID], _ = store.CreatePlayerSession(previous.ID, player.ID, sessions.RolePlayer)
+	}
+
+	first, err := store.Rematch(previous.ID, tokens["p1"])
+	if err != nil {
+		t.Fatalf("unexpected rematch error %v", err)
+	}
+	second, err := store.Rematch(previous.ID, tokens["p2"])
+	if err != nil {
+		t.Fatalf("unexpected rematch error %v", err)
+	}
+	if first.Game.GameID != second.Game.GameID || first.Token == second.Token {
+		t.Fatalf("expected both players seated in one rematch with their own seats")
+	}
+
+	rejoinCodes := 0
+	for _, key := range server.Keys() {
+		if strings.HasPrefix(key, "rejoin:") {
+			rejoinCodes++
+		}
+	}
+	if rejoinCodes != len(previous.Players) {
+		t.Fatalf("expected one rejoin code per seat, got %d", rejoinCodes)
+	}
+
+	if _, err := store.Rematch(previous.ID, tokens["p1"]); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("expected the finished game's token to be revoked, got %v", err)
+	}
+	third, err := store.Rematch(previous.ID, tokens["p3"])
+	if err != nil {
+		t.Fatalf("unexpected rematch error %v", err)
+	}
+	seat, _ := store.rematchSeatFor(context.Background(), &Game{ID: third.Game.GameID}, "p3")
+	if third.Token != seat.Token || third.RejoinCode != seat.RejoinCode {
+		t.Fatalf("expected p3 to get the seat issued with the rematch")
+	}
+}
@@ -0,0 +1,212 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/*
+ReplaySetup is the table just before the deal: who sat where, the settings,
+the role definitions and the seed every shuffle is drawn from.
+*/
+type ReplaySetup struct {
+	AdminID  string           `json:"adminId"`
+	Players  []Player         `json:"players"`
+	Settings RoomSettings     `json:"settings"`
+	Roles    []RoleDefinition `json:"roles"`
+	Seed     int64            `json:"seed"`
+
+	// Set when the starting player was chosen rather than drawn.
+	StartingPlayerID string `json:"startingPlayerId,omitempty"`
+}
+
+// ReplayLog is everything needed to play a game again.
+type ReplayLog struct {
+	GameID   string       `json:"gameId"`
+	Setup    *ReplaySetup `json:"setup"`
+	Commands []Command    `json:"commands"`
+}
+
+/*
+ReplayStep is the game right after one step. Step 0 is the deal; step n is
+the game after the n-th command. State is the full game, hidden cards and
+deck included, so replays are only served once the game is over.
+*/
+type ReplayStep struct {
+	Step    int         `json:"step"`
+	Command *Command    `json:"command,omitempty"`
+	Events  []GameEvent `json:"events"`
+	State   *Game       `json:"state"`
+}
+
+// captureSetup snapshots the table right before SetupNewGame deals.
+func captureSetup(game *Game) {
+	if game.Seed == 0 {
+		game.Seed = time.Now().UnixNano()
+	}
+
+	players := make([]Player, 0, len(game.Players))
+	for _, player := range game.Players {
+		seated := *player
+		seated.Influences = []Influence{}
+		players = append(players, seated)
+	}
+
+	game.Setup = &ReplaySetup{
+		AdminID:  game.AdminID,
+		Players:  players,
+		Settings: game.Settings,
+		Seed:     game.Seed,
+
+		StartingPlayerID: game.StartingPlayerID,
+	}
+}
+
+func NewReplayLog(game *Game) (*ReplayLog, error) {
+	if game.Setup == nil {
+		return nil, ErrReplayUnavailable
+	}
+
+	setup := *game.Setup
+	setup.Roles = game.Roles
+
+	return &ReplayLog{
+		GameID:   game.ID,
+		Setup:    &setup,
+		Commands: game.Commands,
+	}, nil
+}
+
+/*
+Replay deals the game again from its seed and applies each command in turn,
+recording the events and the state after every step. Shuffles are drawn from
+the seed and a draw counter, so the replay matches the original game move
+for move.
+*/
+func Replay(replayLog *ReplayLog) ([]ReplayStep, error) {
+	if replayLog.Setup == nil {
+		return nil, ErrReplayUnavailable
+	}
+	setup := replayLog.Setup
+
+	game := &Game{
+		ID:       replayLog.GameID,
+		AdminID:  setup.AdminID,
+		Settings: setup.Settings,
+		Roles:    setup.Roles,
+		Deck:     []Influence{},
+		Seed:     setup.Seed,
+
+		StartingPlayerID: setup.StartingPlayerID,
+	}
+	for _, player := range setup.Players {
+		seated := player
+		game.Players = append(game.Players, &seated)
+	}
+
+	if err := SetupNewGame(game); err != nil {
+		return nil, err
+	}
+
+	steps := []ReplayStep{}
+	snapshot := func(command *Command, events []GameEvent) error {
+		state, err := snapshotForReplay(game)
+		if err != nil {
+			return err
+		}
+		if events == nil {
+			events = []GameEvent{}
+		}
+		steps = append(steps, ReplayStep{
+			Step:    len(steps),
+			Command: command,
+			Events:  events,
+			State:   state,
+		})
+		return nil
+	}
+
+	if err := snapshot(nil, nil); err != nil {
+		return nil, err
+	}
+
+	for i := range replayLog.Commands {
+		command := replayLog.Commands[i]
+
+		events, err := applyCommand(game, command)
+		if err != nil {
+			return nil, fmt.Errorf("%w: step %d: %w", ErrReplayDiverged, i+1, err)
+		}
+		if err := snapshot(&command, events); err != nil {
+			return nil, err
+		}
+	}
+
+	return steps, nil
+}
+
+func applyCommand(game *Game, command Command) ([]GameEvent, error) {
+	switch command.Type {
+	case CommandDeclare:
+		if command.Action == nil {
+			return nil, ErrInvalidCommand
+		}
+		return Declare(game, command.PlayerID, *command.Action)
+	case CommandRespond:
+		if command.Response == nil {
+			return nil, ErrInvalidCommand
+		}
+		return Respond(game, command.PlayerID, *command.Response)
+	case CommandLoseInfluence:
+		if len(command.Roles) != 1 {
+			return nil, ErrInvalidCommand
+		}
+		return LoseInfluence(game, command.PlayerID, command.Roles[0])
+	case CommandExchange:
+		return CompleteExchange(game, command.PlayerID, command.Roles)
+	case CommandShowInfluence:
+		if len(command.Roles) != 1 {
+			return nil, ErrInvalidCommand
+		}
+		return ShowInfluence(game, command.PlayerID, command.Roles[0])
+	case CommandExamination:
+		return CompleteExamination(game, command.PlayerID, command.ForceSwap)
+	case CommandSelectInfluences:
+		return SelectInfluences(game, command.PlayerID, command.Roles)
+	default:
+		return nil, ErrInvalidCommand
+	}
+}
+
+// snapshotForReplay copies the game without its logs, which every step would repeat.
+func snapshotForReplay(game *Game) (*Game, error) {
+	data, err := json.Marshal(game)
+	if err != nil {
+		return nil, err
+	}
+
+	var state Game
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	state.Setup = nil
+	state.Commands = nil
+	state.History = nil
+
+	return &state, nil
+}
+
+// GetReplayLog returns the replay log of a finished game still held in Redis.
+func (store *Store) GetReplayLog(gameID string) (*ReplayLog, error) {
+	game, err := store.loadGame(context.Background(), gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !game.Finished {
+		return nil, ErrGameNotFinished
+	}
+
+	return NewReplayLog(game)
+}
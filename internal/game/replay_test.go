@@ -0,0 +1,103 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+// playRandomly drives a local game with random legal moves.
+func playRandomly(t *testing.T, game *Game, rng *rand.Rand, maxMoves int) {
+	t.Helper()
+
+	for moves := 0; moves < maxMoves && !game.Finished; moves++ {
+		acted := false
+		for _, player := range game.Players {
+			view := NewPlayerView(game, player.ID)
+
+			var err error
+			switch view.Decision {
+			case DecisionNone:
+				continue
+			case DecisionDeclare:
+				_, err = Declare(game, player.ID, view.LegalActions[rng.Intn(len(view.LegalActions))])
+			case DecisionRespond:
+				_, err = Respond(game, player.ID, view.LegalResponses[rng.Intn(len(view.LegalResponses))])
+			case DecisionLoseInfluence:
+				for _, influence := range view.Hand {
+					if !influence.Revealed {
+						_, err = LoseInfluence(game, player.ID, influence.Role)
+						break
+					}
+				}
+			case DecisionExchange:
+				pool := []string{}
+				for _, influence := range append(view.Options, view.Hand...) {
+					if !influence.Revealed {
+						pool = append(pool, influence.Role)
+					}
+				}
+				_, err = CompleteExchange(game, player.ID, pool[:view.Keep])
+			case DecisionShowInfluence:
+				for _, influence := range view.Hand {
+					if !influence.Revealed {
+						_, err = ShowInfluence(game, player.ID, influence.Role)
+						break
+					}
+				}
+			case DecisionExamination:
+				_, err = CompleteExamination(game, player.ID, rng.Intn(2) == 0)
+			case DecisionDraft:
+				_, err = SelectInfluences(game, player.ID, []string{view.Options[0].Role, view.Options[1].Role})
+			}
+			if err != nil {
+				t.Fatalf("unexpected %s error %v", view.Decision, err)
+			}
+			acted = true
+			break
+		}
+		if !acted {
+			t.Fatalf("game stalled")
+		}
+	}
+}
+
+func TestReplayReproducesTheGame(t *testing.T) {
+	for _, pack := range []string{RolePackBase, RolePackInquisitor} {
+		t.Run(pack, func(t *testing.T) {
+			settings := DefaultRoomSettings()
+			settings.RolePack = pack
+			assertReplayReproduces(t, settings)
+		})
+	}
+}
+
+func assertReplayReproduces(t *testing.T, settings RoomSettings) {
+	t.Helper()
+
+	game, err := NewLocalGame(4, settings, 42)
+	if err != nil {
+		t.Fatalf("unexpected setup error %v", err)
+	}
+	playRandomly(t, game, rand.New(rand.NewSource(7)), 500)
+
+	replayLog, err := NewReplayLog(game)
+	if err != nil {
+		t.Fatalf("unexpected replay log error %v", err)
+	}
+	steps, err := Replay(replayLog)
+	if err != nil {
+		t.Fatalf("unexpected replay error %v", err)
+	}
+
+	if len(steps) != len(game.Commands)+1 {
+		t.Fatalf("expected %d steps, got %d", len(game.Commands)+1, len(steps))
+	}
+
+	final := steps[len(steps)-1].State
+	want, _ := json.Marshal(map[string]any{"players": game.Players, "deck": game.Deck, "winner": game.WinnerID})
+	got, _ := json.Marshal(map[string]any{"players": final.Players, "deck": final.Deck, "winner": final.WinnerID})
+	if string(got) != string(want) {
+		t.Fatalf("replay diverged:\n got %s\nwant %s", got, want)
+	}
+}
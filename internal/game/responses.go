@@ -0,0 +1,319 @@
+package game
+
+const (
+	ResponsePass      = "pass"
+	ResponseChallenge = "challenge"
+	ResponseBlock     = "block"
+)
+
+type ActionResponse struct {
+	Response string `json:"response"`
+	Role     string `json:"role,omitempty"`
+}
+
+func respondToAction(
+	game *Game,
+	playerID string,
+	actionID string,
+	response ActionResponse,
+	events *eventLog,
+) error {
+
+	if !game.Started || game.Finished {
+		return ErrNotStarted
+	}
+
+	pending := game.PendingAction
+	if pending == nil || pending.ID != actionID {
+		return ErrNoPendingAction
+	}
+
+	player, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return err
+	}
+	if !player.Alive {
+		return ErrPlayerIsDead
+	}
+
+	switch response.Response {
+	case ResponsePass:
+		err = passAction(game, player, events)
+	case ResponseChallenge:
+		err = challengeAction(game, player, events)
+	case ResponseBlock:
+		err = blockAction(game, player, response.Role, events)
+	default:
+		err = ErrInvalidResponse
+	}
+	if err != nil {
+		return err
+	}
+
+	recordCommand(game, Command{
+		Type:     CommandRespond,
+		PlayerID: playerID,
+		Response: &response,
+	})
+
+	return nil
+}
+
+func passAction(game *Game, player *Player, events *eventLog) error {
+	pending := game.PendingAction
+
+	if !contains(eligibleResponders(game), player.ID) {
+		return ErrCannotRespond
+	}
+
+	pending.PassedPlayerIDs = append(pending.PassedPlayerIDs, player.ID)
+
+	if len(eligibleResponders(game)) == 0 {
+		closeResponseWindow(game, events)
+	}
+
+	return nil
+}
+
+func challengeAction(game *Game, challenger *Player, events *eventLog) error {
+	pending := game.PendingAction
+
+	if contains(pending.PassedPlayerIDs, challenger.ID) || !canChallenge(game, challenger.ID) {
+		return ErrCannotChallenge
+	}
+
+	if pending.Status == "blocked" {
+		blocker, err := findPlayerByID(game, pending.Block.BlockerID)
+		if err != nil {
+			return err
+		}
+
+		blockHolds := resolveChallenge(game, blocker, challenger, pending.Block.Role, false, events)
+		if game.Finished {
+			return nil
+		}
+		if !blockHolds {
+			resolveAction(game, pending, events)
+		} else {
+			cancelAction(game, pending, "block_upheld", events)
+		}
+		finishTurn(game)
+		return nil
+	}
+
+	actor, err := findPlayerByID(game, pending.ActorID)
+	if err != nil {
+		return err
+	}
+
+	claimHolds := resolveChallenge(game, actor, challenger, pending.ClaimedRole, pending.InvertedClaim, events)
+	if game.Finished {
+		return nil
+	}
+	if !claimHolds {
+		cancelAction(game, pending, "challenge_won", events)
+		finishTurn(game)
+		return nil
+	}
+
+	pending.ChallengeResolved = true
+	pending.PassedPlayerIDs = []string{}
+
+	if len(eligibleResponders(game)) == 0 {
+		closeResponseWindow(game, events)
+	}
+
+	return nil
+}
+
+func blockAction(game *Game, blocker *Player, role string, events *eventLog) error {
+	pending := game.PendingAction
+
+	if contains(pending.PassedPlayerIDs, blocker.ID) || !canBlock(game, blocker.ID) {
+		return ErrCannotBlock
+	}
+
+	actionType := actionTypes[pending.ActionName]
+	if !contains(blockingRolesFor(game, actionType), role) {
+		return ErrInvalidBlockingRole
+	}
+
+	actor, err := findPlayerByID(game, pending.ActorID)
+	if err != nil {
+		return err
+	}
+	if violatesFaction(game, blocker, actor) {
+		return ErrSameFactionBlock
+	}
+
+	pending.Status = "blocked"
+	pending.Block = &PendingBlock{
+		BlockerID: blocker.ID,
+		Role:      role,
+	}
+	pending.PassedPlayerIDs = []string{}
+	recordBlock(game, pending.ID, blocker, role)
+
+	events.add("action_blocked", map[string]any{
+		"actionId":  pending.ID,
+		"blockerId": blocker.ID,
+		"role":      role,
+	})
+
+	return nil
+}
+
+/*
+resolveChallenge checks the claimant's hand and punishes whoever was wrong.
+It returns true when the claim holds. A proven role is shuffled back into the
+deck and replaced, as in the base rules.
+*/
+func resolveChallenge(
+	game *Game,
+	claimant *Player,
+	challenger *Player,
+	role string,
+	inverted bool,
+	events *eventLog,
+) bool {
+	holdsRole := hasUnrevealedRole(claimant, role)
+	claimHolds := holdsRole != inverted
+
+	recordChallenge(game, game.PendingAction.ID, ChallengeRecord{
+		ChallengerID: challenger.ID,
+		ClaimantID:   claimant.ID,
+		Role:         role,
+		ClaimHolds:   claimHolds,
+	})
+
+	events.add("action_challenged", map[string]any{
+		"actionId":     game.PendingAction.ID,
+		"claimantId":   claimant.ID,
+		"challengerId": challenger.ID,
+		"role":         role,
+		"claimHolds":   claimHolds,
+	})
+
+	if claimHolds {
+		if holdsRole {
+			replaceInfluence(game, claimant, role)
+		}
+		loseInfluence(game, challenger, "challenge_lost", events)
+	} else {
+		loseInfluence(game, claimant, "challenge_lost", events)
+	}
+
+	return claimHolds
+}
+
+func closeResponseWindow(game *Game, events *eventLog) {
+	pending := game.PendingAction
+
+	if pending.Status == "blocked" {
+		cancelAction(game, pending, "blocked", events)
+	} else {
+		resolveAction(game, pending, events)
+	}
+
+	finishTurn(game)
+}
+
+func cancelAction(game *Game, pending *PendingAction, reason string, events *eventLog) {
+	recordOutcome(game, pending.ID, reason)
+
+	events.add("action_canceled", map[string]any{
+		"actionId":   pending.ID,
+		"actionName": pending.ActionName,
+		"actorId":    pending.ActorID,
+		"reason":     reason,
+	})
+}
+
+/*
+eligibleResponders lists the players who still have to answer the pending
+action before its response window closes.
+*/
+func eligibleResponders(game *Game) []string {
+	pending := game.PendingAction
+	if pending == nil {
+		return nil
+	}
+
+	responders := []string{}
+	for _, p := range game.Players {
+		if !p.Alive || contains(pending.PassedPlayerIDs, p.ID) {
+			continue
+		}
+		if canChallenge(game, p.ID) || canBlock(game, p.ID) {
+			responders = append(responders, p.ID)
+		}
+	}
+
+	return responders
+}
+
+func canChallenge(game *Game, playerID string) bool {
+	pending := game.PendingAction
+
+	if pending.Status == "blocked" {
+		return playerID != pending.Block.BlockerID
+	}
+
+	actionType := actionTypes[pending.ActionName]
+	return actionType.isContestable && !pending.ChallengeResolved && playerID != pending.ActorID
+}
+
+func canBlock(game *Game, playerID string) bool {
+	pending := game.PendingAction
+	if pending.Status != "declared" || playerID == pending.ActorID {
+		return false
+	}
+
+	actionType := actionTypes[pending.ActionName]
+	if !actionType.isBlockable {
+		return false
+	}
+
+	if actionType.onlyTargetBlocks {
+		if pending.TargetID == nil || *pending.TargetID != playerID {
+			return false
+		}
+		target, err := findPlayerByID(game, playerID)
+		return err == nil && target.Alive
+	}
+
+	blocker, err := findPlayerByID(game, playerID)
+	if err != nil {
+		return false
+	}
+	actor, err := findPlayerByID(game, pending.ActorID)
+	if err != nil {
+		return false
+	}
+
+	return !violatesFaction(game, blocker, actor)
+}
+
+func hasUnrevealedRole(player *Player, role string) bool {
+	for _, influence := range player.Influences {
+		if !influence.Revealed && influence.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func replaceInfluence(game *Game, player *Player, role string) {
+	for i, influence := range player.Influences {
+		if influence.Revealed || influence.Role != role {
+			continue
+		}
+
+		game.Deck = append(game.Deck, influence)
+		shuffleDeck(game)
+
+		player.Influences[i] = game.Deck[0]
+		game.Deck = game.Deck[1:]
+		return
+	}
+}
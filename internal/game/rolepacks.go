@@ -0,0 +1,281 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	RolePackBase       = "base"
+	RolePackInquisitor = "inquisitor"
+
+	DefaultExchangeDraws = 2
+)
+
+// Actions a role may grant: these are the ones the engine resolves by claim.
+var characterActions = []string{
+	"tax",
+	"assassinate",
+	"steal",
+	"exchange",
+	"examine",
+}
+
+// Actions a role may block.
+var blockableActions = []string{
+	"foreign_aid",
+	"assassinate",
+	"steal",
+}
+
+type RoleDefinition struct {
+	Name    string   `json:"name" toml:"name"`
+	Actions []string `json:"actions" toml:"actions"`
+	Blocks  []string `json:"blocks" toml:"blocks"`
+	Draws   int      `json:"draws,omitempty" toml:"draws"` // cards drawn when exchanging
+}
+
+type RolePack struct {
+	Name  string           `json:"name" toml:"name"`
+	Roles []RoleDefinition `json:"roles" toml:"role"`
+}
+
+type rolePackFile struct {
+	Packs []RolePack `json:"packs" toml:"pack"`
+}
+
+var (
+	rolePacksMu sync.RWMutex
+	rolePacks   = map[string]RolePack{
+		RolePackBase:       baseRolePack(),
+		RolePackInquisitor: inquisitorRolePack(),
+	}
+)
+
+func baseRolePack() RolePack {
+	return RolePack{
+		Name: RolePackBase,
+		Roles: []RoleDefinition{
+			{Name: "Duke", Actions: []string{"tax"}, Blocks: []string{"foreign_aid"}},
+			{Name: "Assassin", Actions: []string{"assassinate"}},
+			{Name: "Ambassador", Actions: []string{"exchange"}, Blocks: []string{"steal"}, Draws: 2},
+			{Name: "Captain", Actions: []string{"steal"}, Blocks: []string{"steal"}},
+			{Name: "Contessa", Blocks: []string{"assassinate"}},
+		},
+	}
+}
+
+/*
+inquisitorRolePack is the alternative composition from the expansion, where
+the Inquisitor takes the Ambassador's place.
+*/
+func inquisitorRolePack() RolePack {
+	return RolePack{
+		Name: RolePackInquisitor,
+		Roles: []RoleDefinition{
+			{Name: "Duke", Actions: []string{"tax"}, Blocks: []string{"foreign_aid"}},
+			{Name: "Assassin", Actions: []string{"assassinate"}},
+			{Name: "Inquisitor", Actions: []string{"exchange", "examine"}, Blocks: []string{"steal"}, Draws: 1},
+			{Name: "Captain", Actions: []string{"steal"}, Blocks: []string{"steal"}},
+			{Name: "Contessa", Blocks: []string{"assassinate"}},
+		},
+	}
+}
+
+/*
+LoadRolePacks reads custom role packs from a TOML or JSON file and registers
+them next to the built-in ones. Every pack is validated before any of them is
+registered, so a bad file leaves the registry untouched.
+*/
+func LoadRolePacks(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file rolePackFile
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		err = toml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse role packs: %w", err)
+	}
+
+	loaded := make(map[string]RolePack, len(file.Packs))
+	for _, pack := range file.Packs {
+		if err := pack.Validate(); err != nil {
+			return fmt.Errorf("role pack %q: %w", pack.Name, err)
+		}
+		if _, exists := loaded[pack.Name]; exists {
+			return fmt.Errorf("role pack %q: %w", pack.Name, ErrDuplicateRolePack)
+		}
+		if pack.Name == RolePackBase || pack.Name == RolePackInquisitor {
+			return fmt.Errorf("role pack %q: %w", pack.Name, ErrDuplicateRolePack)
+		}
+		loaded[pack.Name] = pack
+	}
+
+	rolePacksMu.Lock()
+	defer rolePacksMu.Unlock()
+
+	for name, pack := range loaded {
+		rolePacks[name] = pack
+	}
+
+	return nil
+}
+
+func (pack RolePack) Validate() error {
+	if pack.Name == "" || len(pack.Roles) == 0 || len(pack.Roles) > MaxRolesPerPack {
+		return ErrInvalidRolePack
+	}
+
+	names := map[string]bool{}
+	for _, role := range pack.Roles {
+		if role.Name == "" || names[role.Name] {
+			return ErrInvalidRolePack
+		}
+		names[role.Name] = true
+
+		for _, action := range role.Actions {
+			if !contains(characterActions, action) {
+				return ErrUnknownAction
+			}
+		}
+		for _, action := range role.Blocks {
+			if !contains(blockableActions, action) {
+				return ErrUnknownAction
+			}
+		}
+		if role.Draws < 0 || role.Draws > 2 {
+			return ErrInvalidRolePack
+		}
+	}
+
+	return nil
+}
+
+func LookupRolePack(name string) (RolePack, bool) {
+	rolePacksMu.RLock()
+	defer rolePacksMu.RUnlock()
+
+	pack, ok := rolePacks[name]
+	return pack, ok
+}
+
+func ListRolePacks() []RolePack {
+	rolePacksMu.RLock()
+	defer rolePacksMu.RUnlock()
+
+	packs := make([]RolePack, 0, len(rolePacks))
+	for _, pack := range rolePacks {
+		packs = append(packs, pack)
+	}
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Name < packs[j].Name
+	})
+
+	return packs
+}
+
+func (pack RolePack) RoleNames() []string {
+	names := make([]string, 0, len(pack.Roles))
+	for _, role := range pack.Roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func (pack RolePack) rolesGranting(actionName string) []RoleDefinition {
+	roles := []RoleDefinition{}
+	for _, role := range pack.Roles {
+		if contains(role.Actions, actionName) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func (pack RolePack) rolesBlocking(actionName string) []string {
+	roles := []string{}
+	for _, role := range pack.Roles {
+		if contains(role.Blocks, actionName) {
+			roles = append(roles, role.Name)
+		}
+	}
+	return roles
+}
+
+func (pack RolePack) role(name string) (RoleDefinition, bool) {
+	for _, role := range pack.Roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return RoleDefinition{}, false
+}
+
+/*
+rolePackFor returns the roles in play. Started games keep the snapshot taken
+at setup, so editing the role pack file never changes a game in progress.
+*/
+func rolePackFor(game *Game) RolePack {
+	if len(game.Roles) > 0 {
+		return RolePack{Name: game.Settings.RolePack, Roles: game.Roles}
+	}
+
+	if pack, ok := LookupRolePack(game.Settings.RolePack); ok {
+		return pack
+	}
+	return baseRolePack()
+}
+
+/*
+claimedRoleFor returns the role an action claims in this game. When several
+roles grant the action the player may pick one; otherwise the first wins.
+Embezzle claims NOT to hold the role granting tax.
+*/
+func claimedRoleFor(game *Game, actionType ActionType, requested string) string {
+	pack := rolePackFor(game)
+
+	if actionType.invertedClaimOf != "" {
+		roles := pack.rolesGranting(actionType.invertedClaimOf)
+		if len(roles) == 0 {
+			return ""
+		}
+		return roles[0].Name
+	}
+
+	roles := pack.rolesGranting(actionType.name)
+	if len(roles) == 0 {
+		return ""
+	}
+
+	for _, role := range roles {
+		if role.Name == requested {
+			return role.Name
+		}
+	}
+	return roles[0].Name
+}
+
+func blockingRolesFor(game *Game, actionType ActionType) []string {
+	return rolePackFor(game).rolesBlocking(actionType.name)
+}
+
+func exchangeDrawCount(game *Game, claimedRole string) int {
+	role, ok := rolePackFor(game).role(claimedRole)
+	if !ok || role.Draws == 0 {
+		return DefaultExchangeDraws
+	}
+	return role.Draws
+}
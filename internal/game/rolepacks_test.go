@@ -0,0 +1,112 @@
+package game
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRolePacks(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// keepRolePacks restores the registry after the test, so loaded packs do not leak.
+func keepRolePacks(t *testing.T) {
+	t.Helper()
+
+	rolePacksMu.RLock()
+	saved := maps.Clone(rolePacks)
+	rolePacksMu.RUnlock()
+
+	t.Cleanup(func() {
+		rolePacksMu.Lock()
+		defer rolePacksMu.Unlock()
+		rolePacks = saved
+	})
+}
+
+func TestLoadRolePacksFromTOML(t *testing.T) {
+	keepRolePacks(t)
+
+	path := writeRolePacks(t, "packs.toml", `
+[[pack]]
+name = "bureaucrats"
+
+  [[pack.role]]
+  name = "Clerk"
+  actions = ["exchange"]
+  draws = 1
+  blocks = ["steal"]
+
+  [[pack.role]]
+  name = "Guard"
+  blocks = ["assassinate"]
+`)
+
+	if err := LoadRolePacks(path); err != nil {
+		t.Fatalf("unexpected load error %v", err)
+	}
+
+	pack, ok := LookupRolePack("bureaucrats")
+	if !ok || len(pack.Roles) != 2 {
+		t.Fatalf("expected the pack to be registered, got %+v", pack)
+	}
+
+	game := newTestGame(t, nil, []string{"Clerk", "Guard"}, []string{"Guard", "Guard"})
+	game.Settings.RolePack = "bureaucrats"
+
+	if claimedRoleFor(game, actionTypes["exchange"], "") != "Clerk" {
+		t.Fatalf("expected exchange to claim the Clerk")
+	}
+	if exchangeDrawCount(game, "Clerk") != 1 {
+		t.Fatalf("expected the Clerk to draw one card")
+	}
+	if claimedRoleFor(game, actionTypes["tax"], "") != "" {
+		t.Fatalf("expected tax to be unavailable")
+	}
+}
+
+func TestLoadRolePacksRejectsUnknownActions(t *testing.T) {
+	keepRolePacks(t)
+
+	path := writeRolePacks(t, "packs.json", `{"packs": [{"name": "broken", "roles": [{"name": "Wizard", "actions": ["fireball"]}]}]}`)
+
+	if err := LoadRolePacks(path); !errors.Is(err, ErrUnknownAction) {
+		t.Fatalf("expected %v, got %v", ErrUnknownAction, err)
+	}
+	if _, ok := LookupRolePack("broken"); ok {
+		t.Fatalf("expected the broken pack to be skipped")
+	}
+}
+
+func TestLoadRolePacksRejectsBuiltInNames(t *testing.T) {
+	keepRolePacks(t)
+
+	path := writeRolePacks(t, "packs.json", `{"packs": [{"name": "base", "roles": [{"name": "Duke"}]}]}`)
+
+	if err := LoadRolePacks(path); !errors.Is(err, ErrDuplicateRolePack) {
+		t.Fatalf("expected %v, got %v", ErrDuplicateRolePack, err)
+	}
+}
+
+func TestShippedRolePacksLoad(t *testing.T) {
+	keepRolePacks(t)
+
+	if err := LoadRolePacks(filepath.Join("..", "..", "config", "role_packs.toml")); err != nil {
+		t.Fatalf("unexpected load error %v", err)
+	}
+
+	settings := DefaultRoomSettings()
+	settings.RolePack = "bureaucrats"
+	if _, err := NewLocalGame(settings.MaxPlayers, settings, 1); err != nil {
+		t.Fatalf("expected a full table to be dealt from the shipped pack, got %v", err)
+	}
+}
@@ -2,14 +2,16 @@ package game
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
+	"influence_game/internal/realtime"
+	"time"
 
-	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
+/*
+resolveSession checks a signed session token. Tokens carry the game and
+player, so no Redis lookup is needed; see sessions.Verifier.
+*/
 func (store *Store) resolveSession(
 	ctx context.Context,
 	gameID string,
@@ -19,55 +21,209 @@ func (store *Store) resolveSession(
 		return nil, ErrInvalidSession
 	}
 
-	data, err := store.redis.Get(ctx, "session:"+sessionToken).Bytes()
-	if err == redis.Nil {
+	claims, err := store.sessions.Verify(sessionToken)
+	if err != nil {
+		log.Debug().Err(err).Msg("Rejected session token.")
+		return nil, ErrInvalidSession
+	}
+
+	if claims.GameID != gameID {
 		return nil, ErrInvalidSession
 	}
+
+	return &PlayerSession{
+		PlayerID: claims.PlayerID,
+		GameID:   claims.GameID,
+		Role:     claims.Role,
+	}, nil
+}
+
+// ResolveSession is resolveSession for callers outside the store, like the
+// WebSocket handler.
+func (store *Store) ResolveSession(gameID string, sessionToken string) (*PlayerSession, error) {
+	return store.resolveSession(context.Background(), gameID, sessionToken)
+}
+
+type cachedLanguage struct {
+	language string
+	expires  time.Time
+}
+
+/*
+PlayerLanguage is the language the session's player chose for messages, or ""
+when they have not chosen one or the token is not a valid session. It is
+asked on every request, so each seat's answer is kept for LanguageCacheTTL:
+a change made through another instance shows up here within that time.
+*/
+func (store *Store) PlayerLanguage(sessionToken string) string {
+	claims, err := store.sessions.Verify(sessionToken)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get session from Redis.")
-		return nil, err
+		return ""
 	}
 
-	var session PlayerSession
-	if err := json.Unmarshal(data, &session); err != nil {
-		log.Error().Err(err).Msg("Failed to unmarshal session.")
-		return nil, err
+	key := claims.GameID + ":" + claims.PlayerID
+	now := time.Now()
+
+	store.languagesMu.Lock()
+	cached, ok := store.languages[key]
+	store.languagesMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.language
 	}
 
-	if session.GameID != gameID {
-		return nil, ErrInvalidSession
+	language := ""
+	if game, err := store.loadGame(context.Background(), claims.GameID); err == nil {
+		if player, err := findPlayerByID(game, claims.PlayerID); err == nil {
+			language = player.Language
+		}
 	}
 
-	return &session, nil
+	store.cacheLanguage(key, language, now)
+	return language
 }
 
-func (store *Store) CreatePlayerSession(gameID string, playerID string) (string, error) {
-	if store.redis == nil {
-		return "", errors.New("redis_not_configured")
+func (store *Store) cacheLanguage(key string, language string, now time.Time) {
+	store.languagesMu.Lock()
+	defer store.languagesMu.Unlock()
+
+	if now.Sub(store.languagesSwept) >= LanguageCacheTTL {
+		for seat, cached := range store.languages {
+			if !now.Before(cached.expires) {
+				delete(store.languages, seat)
+			}
+		}
+		store.languagesSwept = now
 	}
+	store.languages[key] = cachedLanguage{language: language, expires: now.Add(LanguageCacheTTL)}
+}
 
+/*
+SetPlayerLanguage saves the language the player wants messages in and
+switches their open sockets over to it. An empty language goes back to the
+one their client asks for.
+*/
+func (store *Store) SetPlayerLanguage(gameID string, language string, sessionToken string) error {
 	ctx := context.Background()
 
-	sessionToken := uuid.NewString()
-
-	session := PlayerSession{
-		PlayerID: playerID,
-		GameID:   gameID,
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return err
 	}
 
-	data, err := json.Marshal(session)
+	_, err = store.withGameLock(ctx, gameID, func(game *Game) error {
+		player, err := findPlayerByID(game, session.PlayerID)
+		if err != nil {
+			return err
+		}
+
+		player.Language = language
+		return nil
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to serialize session.")
-		return "", err
+		return err
 	}
 
-	redisKey := "session:" + sessionToken
+	store.cacheLanguage(gameID+":"+session.PlayerID, language, time.Now())
+	realtime.Manager.SetLanguage(gameID, session.PlayerID, language)
+
+	return nil
+}
 
-	err = store.redis.Set(ctx, redisKey, data, SessionDuration).Err()
+func (store *Store) CreatePlayerSession(gameID string, playerID string, role string) (string, error) {
+	sessionToken, _, err := store.sessions.Issue(gameID, playerID, role, SessionDuration)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to save session to Redis.")
+		log.Error().Err(err).Msg("Failed to sign session token.")
 		return "", err
 	}
 
 	return sessionToken, nil
 }
+
+/*
+RefreshSession rotates a session: the player gets a new token with a full
+lifetime and the old one is revoked.
+*/
+func (store *Store) RefreshSession(sessionToken string) (*SessionResult, error) {
+	ctx := context.Background()
+
+	claims, err := store.sessions.Verify(sessionToken)
+	if err != nil {
+		return nil, ErrInvalidSession
+	}
+
+	newToken, newClaims, err := store.sessions.Issue(claims.GameID, claims.PlayerID, claims.Role, SessionDuration)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign session token.")
+		return nil, err
+	}
+
+	if err := store.sessions.RevokeToken(ctx, *claims); err != nil {
+		return nil, err
+	}
+
+	return &SessionResult{
+		Token:     newToken,
+		ExpiresAt: newClaims.Expiry(),
+	}, nil
+}
+
+/*
+Logout ends the session and closes the player's sockets in that game. The
+whole seat is revoked, not just this token, since sliding may have handed
+the player newer tokens that are still valid.
+*/
+func (store *Store) Logout(sessionToken string) error {
+	ctx := context.Background()
+
+	claims, err := store.sessions.Verify(sessionToken)
+	if err != nil {
+		return ErrInvalidSession
+	}
+
+	if err := store.sessions.RevokeSeat(ctx, claims.GameID, claims.PlayerID, SessionDuration); err != nil {
+		return err
+	}
+
+	realtime.Manager.DisconnectPlayer(claims.GameID, claims.PlayerID)
+
+	return nil
+}
+
+/*
+SlideSession gives active players a fresh token once theirs has used up half
+of its lifetime. The old token keeps working until it expires, so requests
+already in flight are not cut off, but each token is renewed only once:
+otherwise every request it still authorizes would mint another.
+*/
+func (store *Store) SlideSession(sessionToken string) (*SessionResult, bool) {
+	ctx := context.Background()
+
+	claims, err := store.sessions.Verify(sessionToken)
+	if err != nil {
+		return nil, false
+	}
+
+	if time.Until(claims.Expiry()) > SessionDuration/2 {
+		return nil, false
+	}
+
+	claimed, err := store.redis.SetNX(ctx, "session:slid:"+claims.ID, 1, time.Until(claims.Expiry())).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to claim session slide.")
+		return nil, false
+	}
+	if !claimed {
+		return nil, false
+	}
+
+	newToken, newClaims, err := store.sessions.Issue(claims.GameID, claims.PlayerID, claims.Role, SessionDuration)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign session token.")
+		return nil, false
+	}
+
+	return &SessionResult{
+		Token:     newToken,
+		ExpiresAt: newClaims.Expiry(),
+	}, true
+}
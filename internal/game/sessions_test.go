@@ -0,0 +1,101 @@
+package game
+
+import (
+	"influence_game/internal/sessions"
+	"testing"
+	"time"
+)
+
+func newSessionStore() *Store {
+	return NewStore(nil, sessions.NewVerifier([]byte("secret"), nil))
+}
+
+func TestRefreshSessionRotatesToken(t *testing.T) {
+	store := newSessionStore()
+
+	token, err := store.CreatePlayerSession("game", "player", sessions.RolePlayer)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	refreshed, err := store.RefreshSession(token)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if _, err := store.ResolveSession("game", token); err != ErrInvalidSession {
+		t.Fatalf("expected the old token to be revoked, got %v", err)
+	}
+	session, err := store.ResolveSession("game", refreshed.Token)
+	if err != nil || session.PlayerID != "player" {
+		t.Fatalf("expected the new token to resolve, got %+v %v", session, err)
+	}
+}
+
+func TestSlideSessionOnlyRenewsAgingTokens(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	fresh, _ := store.CreatePlayerSession("game", "player", sessions.RolePlayer)
+	if _, ok := store.SlideSession(fresh); ok {
+		t.Fatal("expected a fresh token to be left alone")
+	}
+
+	aging, _, _ := store.sessions.Issue("game", "player", sessions.RolePlayer, time.Hour)
+	renewed, ok := store.SlideSession(aging)
+	if !ok || time.Until(renewed.ExpiresAt) < SessionDuration-time.Minute {
+		t.Fatalf("expected a renewed token with a full lifetime, got %+v", renewed)
+	}
+
+	// Requests still carrying the aging token do not mint more.
+	if _, ok := store.SlideSession(aging); ok {
+		t.Fatal("expected an aging token to be renewed only once")
+	}
+}
+
+func TestLogoutEndsTokensHandedOutBySliding(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	aging, _, _ := store.sessions.Issue("game", "player", sessions.RolePlayer, time.Hour)
+	renewed, ok := store.SlideSession(aging)
+	if !ok {
+		t.Fatal("expected the aging token to be renewed")
+	}
+
+	if err := store.Logout(aging); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, token := range []string{aging, renewed.Token} {
+		if _, err := store.ResolveSession("game", token); err != ErrInvalidSession {
+			t.Fatalf("expected every token of the seat to be revoked, got %v", err)
+		}
+	}
+}
+
+func TestPlayerLanguageIsCachedPerSeat(t *testing.T) {
+	store, server := newTestStore(t)
+
+	created, err := store.CreateGameRoom("ana", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	gameID := created.Game.GameID
+
+	if language := store.PlayerLanguage(created.Token); language != "" {
+		t.Fatalf("expected no saved language, got %q", language)
+	}
+	if err := store.SetPlayerLanguage(gameID, "pt", created.Token); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// With the game gone only the cache can answer.
+	server.Del("game:" + gameID)
+	if language := store.PlayerLanguage(created.Token); language != "pt" {
+		t.Fatalf("expected the saved language from the cache, got %q", language)
+	}
+
+	key := gameID + ":" + created.Player.ID
+	store.languages[key] = cachedLanguage{language: "pt", expires: time.Now()}
+	if language := store.PlayerLanguage(created.Token); language != "" {
+		t.Fatalf("expected an expired entry to be looked up again, got %q", language)
+	}
+}
@@ -0,0 +1,184 @@
+package game
+
+var knownActions = []string{
+	"income",
+	"foreign_aid",
+	"coup",
+	"tax",
+	"assassinate",
+	"steal",
+	"convert",
+	"embezzle",
+	"exchange",
+	"examine",
+}
+
+const (
+	VariantTwoPlayer   = "two_player"
+	VariantReformation = "reformation"
+)
+
+var knownVariants = []string{
+	VariantTwoPlayer,
+	VariantReformation,
+}
+
+type RoomSettings struct {
+	MaxPlayers       int      `json:"maxPlayers"`
+	TurnTimerSeconds int      `json:"turnTimerSeconds"`
+	StartingCoins    int      `json:"startingCoins"`
+	AllowedActions   []string `json:"allowedActions"`
+	Private          bool     `json:"private"`
+	Variants         []string `json:"variants"`
+	CopiesPerRole    int      `json:"copiesPerRole"` // 0 = scale with player count
+	RolePack         string   `json:"rolePack"`
+}
+
+/*
+RoomSettingsPatch carries the fields sent by the client. Nil fields keep the
+current value, so the same type serves room creation and later edits.
+*/
+type RoomSettingsPatch struct {
+	MaxPlayers       *int      `json:"maxPlayers,omitempty"`
+	TurnTimerSeconds *int      `json:"turnTimerSeconds,omitempty"`
+	StartingCoins    *int      `json:"startingCoins,omitempty"`
+	AllowedActions   *[]string `json:"allowedActions,omitempty"`
+	Private          *bool     `json:"private,omitempty"`
+	Variants         *[]string `json:"variants,omitempty"`
+	CopiesPerRole    *int      `json:"copiesPerRole,omitempty"`
+	RolePack         *string   `json:"rolePack,omitempty"`
+}
+
+func DefaultRoomSettings() RoomSettings {
+	return RoomSettings{
+		MaxPlayers:       DefaultMaxPlayers,
+		TurnTimerSeconds: 0,
+		StartingCoins:    DefaultStartingCoins,
+		AllowedActions:   append([]string{}, knownActions...),
+		Private:          false,
+		Variants:         []string{},
+		CopiesPerRole:    0,
+		RolePack:         RolePackBase,
+	}
+}
+
+/*
+withDefaults fills in settings a game was saved without. Games saved before
+rooms had settings were played by the defaults, and those saved before role
+packs with the base pack.
+*/
+func (settings RoomSettings) withDefaults() RoomSettings {
+	if settings.MaxPlayers == 0 {
+		return DefaultRoomSettings()
+	}
+	if settings.RolePack == "" {
+		settings.RolePack = RolePackBase
+	}
+	return settings
+}
+
+func (patch *RoomSettingsPatch) Apply(settings RoomSettings) RoomSettings {
+	if patch == nil {
+		return settings
+	}
+
+	if patch.MaxPlayers != nil {
+		settings.MaxPlayers = *patch.MaxPlayers
+	}
+	if patch.TurnTimerSeconds != nil {
+		settings.TurnTimerSeconds = *patch.TurnTimerSeconds
+	}
+	if patch.StartingCoins != nil {
+		settings.StartingCoins = *patch.StartingCoins
+	}
+	if patch.AllowedActions != nil {
+		settings.AllowedActions = append([]string{}, *patch.AllowedActions...)
+	}
+	if patch.Private != nil {
+		settings.Private = *patch.Private
+	}
+	if patch.Variants != nil {
+		settings.Variants = append([]string{}, *patch.Variants...)
+	}
+	if patch.CopiesPerRole != nil {
+		settings.CopiesPerRole = *patch.CopiesPerRole
+	}
+	if patch.RolePack != nil {
+		settings.RolePack = *patch.RolePack
+	}
+
+	return settings
+}
+
+func (settings RoomSettings) Validate() error {
+	if settings.MaxPlayers < MinPlayers || settings.MaxPlayers > MaxPlayers {
+		return ErrInvalidMaxPlayers
+	}
+	if settings.TurnTimerSeconds < 0 || settings.TurnTimerSeconds > MaxTurnTimerSeconds {
+		return ErrInvalidTurnTimer
+	}
+	if settings.StartingCoins < 0 || settings.StartingCoins > MaxStartingCoins {
+		return ErrInvalidStartingCoins
+	}
+	pack, ok := LookupRolePack(settings.RolePack)
+	if !ok {
+		return ErrUnknownRolePack
+	}
+
+	if settings.CopiesPerRole != 0 {
+		if settings.CopiesPerRole < MinCopiesPerRole || settings.CopiesPerRole > MaxCopiesPerRole {
+			return ErrInvalidCopiesPerRole
+		}
+		if settings.CopiesPerRole*len(pack.Roles) < 2*settings.MaxPlayers+1 {
+			return ErrNotEnoughInfluences
+		}
+	}
+
+	for _, action := range settings.AllowedActions {
+		if !contains(knownActions, action) {
+			return ErrUnknownAction
+		}
+	}
+	if !contains(settings.AllowedActions, "income") {
+		return ErrIncomeMustBeAllowed
+	}
+
+	for _, variant := range settings.Variants {
+		if !contains(knownVariants, variant) {
+			return ErrUnknownVariant
+		}
+	}
+	if settings.HasVariant(VariantTwoPlayer) && settings.MaxPlayers != 2 {
+		return ErrTwoPlayerVariantNeedsTwo
+	}
+
+	return nil
+}
+
+/*
+DeckCopies returns how many copies of each role go into the deck for the
+given number of players, honoring an explicit room override.
+*/
+func (settings RoomSettings) DeckCopies(playerCount int) int {
+	if settings.CopiesPerRole != 0 {
+		return settings.CopiesPerRole
+	}
+	return DeckCopiesForPlayers(playerCount)
+}
+
+func (settings RoomSettings) AllowsAction(actionName string) bool {
+	return contains(settings.AllowedActions, actionName)
+}
+
+func (settings RoomSettings) HasVariant(variant string) bool {
+	return contains(settings.Variants, variant)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultRoomSettingsAreValid(t *testing.T) {
+	if err := DefaultRoomSettings().Validate(); err != nil {
+		t.Fatalf("default settings should be valid, got %v", err)
+	}
+}
+
+func TestRoomSettingsPatchKeepsUnsetFields(t *testing.T) {
+	maxPlayers := 4
+	patch := &RoomSettingsPatch{MaxPlayers: &maxPlayers}
+
+	settings := patch.Apply(DefaultRoomSettings())
+
+	if settings.MaxPlayers != 4 {
+		t.Fatalf("expected max players 4, got %d", settings.MaxPlayers)
+	}
+	if settings.StartingCoins != DefaultStartingCoins {
+		t.Fatalf("expected starting coins to stay %d, got %d", DefaultStartingCoins, settings.StartingCoins)
+	}
+}
+
+func TestRoomSettingsValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(*RoomSettings)
+		want   error
+	}{
+		{"too few players", func(s *RoomSettings) { s.MaxPlayers = 1 }, ErrInvalidMaxPlayers},
+		{"too many players", func(s *RoomSettings) { s.MaxPlayers = MaxPlayers + 1 }, ErrInvalidMaxPlayers},
+		{"negative timer", func(s *RoomSettings) { s.TurnTimerSeconds = -1 }, ErrInvalidTurnTimer},
+		{"too many coins", func(s *RoomSettings) { s.StartingCoins = MaxStartingCoins + 1 }, ErrInvalidStartingCoins},
+		{"unknown action", func(s *RoomSettings) { s.AllowedActions = []string{"income", "teleport"} }, ErrUnknownAction},
+		{"income missing", func(s *RoomSettings) { s.AllowedActions = []string{"coup"} }, ErrIncomeMustBeAllowed},
+		{"copies out of range", func(s *RoomSettings) { s.CopiesPerRole = MaxCopiesPerRole + 1 }, ErrInvalidCopiesPerRole},
+		{"deck too small", func(s *RoomSettings) { s.MaxPlayers, s.CopiesPerRole = 10, 3 }, ErrNotEnoughInfluences},
+		{"unknown variant", func(s *RoomSettings) { s.Variants = []string{"chaos"} }, ErrUnknownVariant},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := DefaultRoomSettings()
+			tc.mutate(&settings)
+
+			if err := settings.Validate(); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestGamesSavedWithoutSettingsLoadWithDefaults(t *testing.T) {
+	game, err := decodeGame([]byte(`{"id":"g1","players":[]}`))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(game.Settings, DefaultRoomSettings()) {
+		t.Fatalf("expected default settings, got %+v", game.Settings)
+	}
+
+	saved := DefaultRoomSettings()
+	saved.MaxPlayers = 4
+	saved.RolePack = ""
+	data, err := json.Marshal(&Game{ID: "g2", Settings: saved})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	game, err = decodeGame(data)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if game.Settings.MaxPlayers != 4 || game.Settings.RolePack != RolePackBase {
+		t.Fatalf("expected saved settings with the base role pack, got %+v", game.Settings)
+	}
+}
@@ -2,22 +2,32 @@ package game
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
+	"influence_game/internal/sessions"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
 type Store struct {
-	redis *redis.Client
+	redis    *redis.Client
+	sessions *sessions.Verifier
+
+	updateHooks []func(*Game)
+	finishHooks []func(*Game)
+
+	languagesMu    sync.Mutex
+	languages      map[string]cachedLanguage // seat -> saved language, see PlayerLanguage
+	languagesSwept time.Time
 }
 
-func NewStore(redisClient *redis.Client) *Store {
+func NewStore(redisClient *redis.Client, verifier *sessions.Verifier) *Store {
 	return &Store{
-		redis: redisClient,
+		redis:     redisClient,
+		sessions:  verifier,
+		languages: map[string]cachedLanguage{},
 	}
 }
 
@@ -25,6 +35,54 @@ func (store *Store) GetRedis() *redis.Client {
 	return store.redis
 }
 
+/*
+OnGameUpdated registers a hook that runs after every committed change to a
+game. Hooks run on the caller's goroutine, so they must not block; register
+them at startup, before the store serves requests.
+*/
+func (store *Store) OnGameUpdated(hook func(*Game)) {
+	store.updateHooks = append(store.updateHooks, hook)
+}
+
+func (store *Store) notifyGameUpdated(game *Game) {
+	for _, hook := range store.updateHooks {
+		hook(game)
+	}
+}
+
+/*
+OnGameFinished registers a hook that runs once, after the change that
+finished a game, rather than on every later write to it. The same rules as
+OnGameUpdated apply.
+*/
+func (store *Store) OnGameFinished(hook func(*Game)) {
+	store.finishHooks = append(store.finishHooks, hook)
+}
+
+func (store *Store) notifyGameFinished(game *Game) {
+	for _, hook := range store.finishHooks {
+		hook(game)
+	}
+}
+
+func (store *Store) GetGame(gameID string) (*Game, error) {
+	return store.loadGame(context.Background(), gameID)
+}
+
+// EachGameInPlay calls fn for every game started and not yet finished.
+func (store *Store) EachGameInPlay(ctx context.Context, fn func(*Game)) error {
+	return store.scan(ctx, "game:*", func(key string) error {
+		game, err := store.loadGame(ctx, strings.TrimPrefix(key, "game:"))
+		if err != nil {
+			return nil
+		}
+		if game.Started && !game.Finished {
+			fn(game)
+		}
+		return nil
+	})
+}
+
 func (store *Store) StartGame(gameID string, sessionToken string) (*PublicGameState, error) {
 	ctx := context.Background()
 
@@ -57,16 +115,78 @@ func (store *Store) StartGame(gameID string, sessionToken string) (*PublicGameSt
 		return nil, err
 	}
 
+	store.syncPublicLobby(ctx, game)
+
 	BroadcastEvent(
 		ProjectPublicGameState(game),
 		"game_started",
 		nil,
 	)
 
+	for draftingPlayerID, dealt := range game.Drafts {
+		SendToPlayer(
+			draftingPlayerID,
+			"influence_draft",
+			game.ID,
+			map[string]any{
+				"dealt":  dealt,
+				"choose": 2,
+			},
+		)
+	}
+
 	return ProjectPublicGameState(game), nil
 }
 
-func (store *Store) GetPlayerInfluences(
+func (store *Store) SelectInfluences(
+	gameID string,
+	roles []string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.selectInfluencesAs(ctx, gameID, session.PlayerID, roles)
+}
+
+func (store *Store) selectInfluencesAs(
+	ctx context.Context,
+	gameID string,
+	playerID string,
+	roles []string,
+) (*PublicGameState, error) {
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		return selectDraftInfluences(game, playerID, roles)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	BroadcastEvent(
+		ProjectPublicGameState(game),
+		"influences_selected",
+		map[string]any{
+			"playerId": playerID,
+		},
+	)
+
+	if len(game.Drafts) == 0 {
+		BroadcastEvent(
+			ProjectPublicGameState(game),
+			"draft_completed",
+			nil,
+		)
+	}
+
+	return ProjectPublicGameState(game), nil
+}
+
+func (store *Store) GetPlayerDraft(
 	gameID string,
 	sessionToken string,
 ) ([]Influence, error) {
@@ -74,23 +194,87 @@ func (store *Store) GetPlayerInfluences(
 
 	session, err := store.resolveSession(ctx, gameID, sessionToken)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to resolve session.")
 		return nil, err
 	}
 
-	actingPlayerID := session.PlayerID
-	gameKey := "game:" + gameID
+	game, err := store.loadGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
 
-	gameJSON, err := store.redis.Get(ctx, gameKey).Bytes()
-	if err == redis.Nil {
-		return nil, ErrGameNotFound
+	dealt, ok := game.Drafts[session.PlayerID]
+	if !ok {
+		return nil, ErrNoDraftPending
 	}
+
+	return dealt, nil
+}
+
+func (store *Store) UpdateRoomSettings(
+	gameID string,
+	settingsPatch RoomSettingsPatch,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
 	if err != nil {
 		return nil, err
 	}
 
-	var game Game
-	if err := json.Unmarshal(gameJSON, &game); err != nil {
+	playerID := session.PlayerID
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		if game.Started {
+			return ErrAlreadyStarted
+		}
+		if game.AdminID != playerID {
+			return ErrOnlyAdminCanEdit
+		}
+
+		settings := settingsPatch.Apply(game.Settings)
+		if err := settings.Validate(); err != nil {
+			return err
+		}
+		if len(game.Players) > settings.MaxPlayers {
+			return ErrInvalidMaxPlayers
+		}
+
+		game.Settings = settings
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	store.syncPublicLobby(ctx, game)
+
+	BroadcastEvent(
+		ProjectPublicGameState(game),
+		"settings_updated",
+		nil,
+	)
+
+	return ProjectPublicGameState(game), nil
+}
+
+func (store *Store) GetPlayerInfluences(
+	gameID string,
+	sessionToken string,
+) ([]Influence, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve session.")
+		return nil, err
+	}
+
+	actingPlayerID := session.PlayerID
+
+	game, err := store.loadGame(ctx, gameID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -110,7 +294,7 @@ func (store *Store) GetPlayerInfluences(
 func getTurnPlayer(game *Game, actingPlayerID string) (*Player, error) {
 	turnPlayer := game.Players[game.TurnIndex]
 	if turnPlayer.ID != actingPlayerID {
-		return nil, fmt.Errorf("not_your_turn")
+		return nil, ErrNotYourTurn
 	}
 	return turnPlayer, nil
 }
@@ -124,10 +308,6 @@ func findPlayerByID(game *Game, playerID string) (*Player, error) {
 	return nil, ErrPlayerNotFound
 }
 
-func advanceTurn(game *Game) {
-	game.TurnIndex = (game.TurnIndex + 1) % len(game.Players)
-}
-
 func validateActionContext(
 	game *Game,
 	actingPlayerID string,
@@ -137,6 +317,26 @@ func validateActionContext(
 		return nil, ErrNotStarted
 	}
 
+	if len(game.Drafts) > 0 {
+		return nil, ErrDraftInProgress
+	}
+
+	if game.PendingAction != nil {
+		return nil, ErrActionPending
+	}
+
+	if len(game.PendingLosses) > 0 {
+		return nil, ErrInfluenceLossPending
+	}
+
+	if game.PendingExchange != nil {
+		return nil, ErrExchangePending
+	}
+
+	if game.PendingExamination != nil {
+		return nil, ErrExaminationPending
+	}
+
 	return getTurnPlayer(game, actingPlayerID)
 }
 
@@ -153,19 +353,27 @@ func (store *Store) DeclareAction(
 		return nil, err
 	}
 
-	actingPlayerID := session.PlayerID
+	return store.declareActionAs(ctx, gameID, session.PlayerID, action)
+}
 
-	var resultGame *Game
+func (store *Store) declareActionAs(
+	ctx context.Context,
+	gameID string,
+	actingPlayerID string,
+	action DeclareActionPayload,
+) (*PublicGameState, error) {
 	var actionPayload DeclareActionPayload
+	var events eventLog
 
-	resultGame, err = store.withGameLock(ctx, gameID, func(game *Game) error {
+	resultGame, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		events = nil
 
 		turnPlayer, err := validateActionContext(game, actingPlayerID)
 		if err != nil {
 			return err
 		}
 
-		actionPayload, err = applyAction(game, turnPlayer, action)
+		actionPayload, err = declareAction(game, turnPlayer, action, &events)
 		if err != nil {
 			return err
 		}
@@ -184,129 +392,284 @@ func (store *Store) DeclareAction(
 			"actionPayload": actionPayload,
 		},
 	)
+	broadcastEvents(ProjectPublicGameState(resultGame), events)
 
 	return ProjectPublicGameState(resultGame), nil
 }
 
-func applyAction(
-	game *Game,
-	actor *Player,
-	action DeclareActionPayload,
-) (DeclareActionPayload, error) {
+func (store *Store) BlockAction(
+	gameID string,
+	actionID string,
+	blockingRole string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	return store.RespondToAction(gameID, actionID, ActionResponse{
+		Response: ResponseBlock,
+		Role:     blockingRole,
+	}, sessionToken)
+}
 
-	switch action.ActionName {
-	case "income":
-		return declareIncome(game, actor), nil
+func (store *Store) ChallengeAction(
+	gameID string,
+	actionID string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	return store.RespondToAction(gameID, actionID, ActionResponse{
+		Response: ResponseChallenge,
+	}, sessionToken)
+}
 
-	case "foreign_aid":
-		return declareForeignAid(actor), nil
+func (store *Store) PassAction(
+	gameID string,
+	actionID string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	return store.RespondToAction(gameID, actionID, ActionResponse{
+		Response: ResponsePass,
+	}, sessionToken)
+}
 
-	case "coup":
-		if action.TargetPlayerID == nil {
-			return DeclareActionPayload{}, ErrPlayerNotFound
-		}
+func (store *Store) RespondToAction(
+	gameID string,
+	actionID string,
+	response ActionResponse,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
 
-		target, err := findPlayerByID(game, *action.TargetPlayerID)
-		if err != nil {
-			return DeclareActionPayload{}, err
-		}
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to resolve session.")
+		return nil, err
+	}
+
+	return store.respondToActionAs(ctx, gameID, session.PlayerID, actionID, response)
+}
+
+func (store *Store) respondToActionAs(
+	ctx context.Context,
+	gameID string,
+	respondingPlayerID string,
+	actionID string,
+	response ActionResponse,
+) (*PublicGameState, error) {
+	var events eventLog
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		events = nil
+		return respondToAction(game, respondingPlayerID, actionID, response, &events)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastEvents(ProjectPublicGameState(game), events)
+
+	return ProjectPublicGameState(game), nil
+}
+
+func (store *Store) LoseInfluence(
+	gameID string,
+	role string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.loseInfluenceAs(ctx, gameID, session.PlayerID, role)
+}
+
+func (store *Store) loseInfluenceAs(
+	ctx context.Context,
+	gameID string,
+	playerID string,
+	role string,
+) (*PublicGameState, error) {
+	var events eventLog
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		events = nil
+		return chooseInfluenceLoss(game, playerID, role, &events)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastEvents(ProjectPublicGameState(game), events)
+
+	return ProjectPublicGameState(game), nil
+}
+
+func (store *Store) GetPendingExchange(
+	gameID string,
+	sessionToken string,
+) (*PendingExchange, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	game, err := store.loadGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	if game.PendingExchange == nil || game.PendingExchange.PlayerID != session.PlayerID {
+		return nil, ErrNoExchangePending
+	}
+
+	return game.PendingExchange, nil
+}
+
+func (store *Store) CompleteExchange(
+	gameID string,
+	keep []string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.completeExchangeAs(ctx, gameID, session.PlayerID, keep)
+}
+
+func (store *Store) completeExchangeAs(
+	ctx context.Context,
+	gameID string,
+	playerID string,
+	keep []string,
+) (*PublicGameState, error) {
+	var events eventLog
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		events = nil
+		return completeExchange(game, playerID, keep, &events)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastEvents(ProjectPublicGameState(game), events)
+
+	return ProjectPublicGameState(game), nil
+}
+
+func (store *Store) ShowInfluence(
+	gameID string,
+	role string,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
 
-		return declareCoup(game, actor, target)
+	return store.showInfluenceAs(ctx, gameID, session.PlayerID, role)
+}
 
+func (store *Store) showInfluenceAs(
+	ctx context.Context,
+	gameID string,
+	playerID string,
+	role string,
+) (*PublicGameState, error) {
+	var events eventLog
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		events = nil
+		return showInfluence(game, playerID, role, &events)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastEvents(ProjectPublicGameState(game), events)
+
+	return ProjectPublicGameState(game), nil
+}
+
+/*
+PlayForSeat makes a move for a player without a session token, for moves the
+server makes itself. It goes through the same rules and broadcasts as the
+player's own request would, so nobody has to be handed a token for it.
+*/
+func (store *Store) PlayForSeat(gameID string, playerID string, move Move) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	switch move.Decision {
+	case DecisionDeclare:
+		return store.declareActionAs(ctx, gameID, playerID, move.Action)
+	case DecisionRespond:
+		return store.respondToActionAs(ctx, gameID, playerID, move.ActionID, move.Response)
+	case DecisionLoseInfluence:
+		if len(move.Roles) != 1 {
+			return nil, ErrInvalidCommand
+		}
+		return store.loseInfluenceAs(ctx, gameID, playerID, move.Roles[0])
+	case DecisionExchange:
+		return store.completeExchangeAs(ctx, gameID, playerID, move.Roles)
+	case DecisionShowInfluence:
+		if len(move.Roles) != 1 {
+			return nil, ErrInvalidCommand
+		}
+		return store.showInfluenceAs(ctx, gameID, playerID, move.Roles[0])
+	case DecisionExamination:
+		return store.completeExaminationAs(ctx, gameID, playerID, move.ForceSwap)
+	case DecisionDraft:
+		return store.selectInfluencesAs(ctx, gameID, playerID, move.Roles)
 	default:
-		return DeclareActionPayload{}, errors.New("invalid_action_name")
-	}
-}
-
-func declareIncome(game *Game, player *Player) DeclareActionPayload {
-	player.Coins++
-	advanceTurn(game)
-
-	return DeclareActionPayload{
-		ID:                  uuid.New().String(),
-		ActionName:          "income",
-		ActorPlayerID:       player.ID,
-		ActorPlayerNickname: player.Nickname,
-		RequiresTarget:      false,
-		IsImmediate:         true,
-		BlockableRoles:      []string{},
-		IsContestable:       false,
-	}
-}
-
-func declareForeignAid(player *Player) DeclareActionPayload {
-	return DeclareActionPayload{
-		ID:                  uuid.New().String(),
-		ActionName:          "foreign_aid",
-		ActorPlayerID:       player.ID,
-		ActorPlayerNickname: player.Nickname,
-		RequiresTarget:      false,
-		IsImmediate:         false,
-		BlockableRoles:      []string{"Duke"},
-		IsContestable:       false,
-	}
-}
-
-func declareCoup(game *Game, actor *Player, target *Player) (DeclareActionPayload, error) {
-	if actor.Coins < 7 {
-		return DeclareActionPayload{}, fmt.Errorf("not_enough_coins")
-	}
-
-	if !target.Alive {
-		return DeclareActionPayload{}, fmt.Errorf("target_player_is_dead")
-	}
-
-	actor.Coins -= 7
-	advanceTurn(game)
-
-	return DeclareActionPayload{
-		ID:                   uuid.New().String(),
-		ActionName:           "coup",
-		ActorPlayerID:        actor.ID,
-		ActorPlayerNickname:  actor.Nickname,
-		RequiresTarget:       true,
-		TargetPlayerID:       &target.ID,
-		TargetPlayerNickname: &target.Nickname,
-		IsImmediate:          true,
-		BlockableRoles:       []string{},
-		IsContestable:        false,
-	}, nil
-}
-
-// func (store *Store) BlockAction(
-// 	gameID string,
-// 	actionID string,
-// 	blockingRole string,
-// 	sessionToken string,
-// ) (*PublicGameState, error) {
-// 	ctx := context.Background()
-
-// 	session, err := store.resolveSession(ctx, gameID, sessionToken)
-// 	if err != nil {
-// 		log.Error().Err(err).Msg("Failed to resolve session.")
-// 		return nil, err
-// 	}
-
-// 	blockingPlayerID := session.PlayerID
-// 	gameKey := "game:" + gameID
-
-// 	var game Game
-// 	if err := json.Unmarshal(gameJSON, &game); err != nil {
-// 		return nil, err
-// 	}
-
-// 	if !game.Started || game.Finished {
-// 		return nil, ErrNotStarted
-// 	}
-
-// 	// TODO:
-// 	// Procurar PendingAction com o actionID
-// 	// Se o PendingAction não existir, retornar erro
-// 	// Se o PendingAction existir, verificar se o blockingRole é válido
-// 	// Se o blockingRole for válido, criar pending action de bloqueio
-// 	// Se o blockingRole for inválido, retornar erro
-// 	// Atualizar o game com o novo PendingAction
-// 	// Retornar o novo game
-
-// 	return game.GetPublicGameState(), nil
-// }
+		return nil, ErrInvalidCommand
+	}
+}
+
+func (store *Store) CompleteExamination(
+	gameID string,
+	forceSwap bool,
+	sessionToken string,
+) (*PublicGameState, error) {
+	ctx := context.Background()
+
+	session, err := store.resolveSession(ctx, gameID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.completeExaminationAs(ctx, gameID, session.PlayerID, forceSwap)
+}
+
+func (store *Store) completeExaminationAs(
+	ctx context.Context,
+	gameID string,
+	playerID string,
+	forceSwap bool,
+) (*PublicGameState, error) {
+	var events eventLog
+
+	game, err := store.withGameLock(ctx, gameID, func(game *Game) error {
+		events = nil
+		return completeExamination(game, playerID, forceSwap, &events)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	broadcastEvents(ProjectPublicGameState(game), events)
+
+	return ProjectPublicGameState(game), nil
+}
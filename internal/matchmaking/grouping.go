@@ -0,0 +1,73 @@
+package matchmaking
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	MinGroupSize = 4
+	MaxGroupSize = 6
+
+	// Rating spread allowed in a group: it starts narrow and widens the longer
+	// its longest-waiting member has been queued.
+	BaseRatingSpread      = 100.0
+	RatingSpreadPerSecond = 5.0
+	MaxRatingSpread       = 600.0
+)
+
+type Entry struct {
+	UserID   string    `json:"userId"`
+	Nickname string    `json:"nickname"`
+	Rating   float64   `json:"rating"`
+	QueuedAt time.Time `json:"queuedAt"`
+
+	stored string // the entry as read from Redis, see claim
+}
+
+func allowedSpread(longestWait time.Duration) float64 {
+	return min(BaseRatingSpread+RatingSpreadPerSecond*longestWait.Seconds(), MaxRatingSpread)
+}
+
+/*
+formGroups splits the queue into groups of MinGroupSize to MaxGroupSize
+players whose ratings fit within the allowed spread. Players are taken in
+rating order; whoever cannot be placed stays queued for the next round.
+*/
+func formGroups(entries []Entry, now time.Time) [][]Entry {
+	sorted := append([]Entry{}, entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Rating < sorted[j].Rating
+	})
+
+	groups := [][]Entry{}
+
+	for start := 0; start+MinGroupSize <= len(sorted); {
+		end := start
+		oldest := sorted[start].QueuedAt
+
+		for end < len(sorted) && end-start < MaxGroupSize {
+			candidateOldest := oldest
+			if sorted[end].QueuedAt.Before(candidateOldest) {
+				candidateOldest = sorted[end].QueuedAt
+			}
+
+			spread := sorted[end].Rating - sorted[start].Rating
+			if spread > allowedSpread(now.Sub(candidateOldest)) {
+				break
+			}
+
+			oldest = candidateOldest
+			end++
+		}
+
+		if end-start >= MinGroupSize {
+			groups = append(groups, sorted[start:end])
+			start = end
+		} else {
+			start++
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,58 @@
+package matchmaking
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func queued(now time.Time, wait time.Duration, ratings ...float64) []Entry {
+	entries := []Entry{}
+	for i, rating := range ratings {
+		entries = append(entries, Entry{
+			UserID:   fmt.Sprintf("user-%d", i),
+			Rating:   rating,
+			QueuedAt: now.Add(-wait),
+		})
+	}
+	return entries
+}
+
+func TestGroupsKeepRatingsClose(t *testing.T) {
+	now := time.Now()
+	entries := queued(now, 0, 1500, 1510, 1520, 1530, 2000, 2010, 2020, 2030)
+
+	groups := formGroups(entries, now)
+	if len(groups) != 2 {
+		t.Fatalf("expected two groups, got %v", groups)
+	}
+	for _, group := range groups {
+		if spread := group[len(group)-1].Rating - group[0].Rating; spread > BaseRatingSpread {
+			t.Fatalf("expected a tight group, got spread %.0f", spread)
+		}
+	}
+}
+
+func TestToleranceGrowsWithWaitTime(t *testing.T) {
+	now := time.Now()
+	entries := queued(now, 0, 1300, 1450, 1600, 1750)
+
+	if groups := formGroups(entries, now); len(groups) != 0 {
+		t.Fatalf("expected no group for fresh entries, got %v", groups)
+	}
+
+	entries = queued(now, 2*time.Minute, 1300, 1450, 1600, 1750)
+	if groups := formGroups(entries, now); len(groups) != 1 || len(groups[0]) != 4 {
+		t.Fatalf("expected one group after waiting, got %v", groups)
+	}
+}
+
+func TestGroupsAreCappedAtMaxSize(t *testing.T) {
+	now := time.Now()
+	entries := queued(now, 0, 1500, 1501, 1502, 1503, 1504, 1505, 1506, 1507)
+
+	groups := formGroups(entries, now)
+	if len(groups) != 1 || len(groups[0]) != MaxGroupSize {
+		t.Fatalf("expected one full group, got %v", groups)
+	}
+}
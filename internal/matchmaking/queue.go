@@ -0,0 +1,399 @@
+package matchmaking
+
+import (
+	"context"
+	"encoding/json"
+	"influence_game/internal/game"
+	"influence_game/internal/realtime"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultRating = 1500.0
+
+	MatchInterval  = 2 * time.Second
+	MatchResultTTL = 10 * time.Minute
+
+	// Realtime channel the queue-status sockets are registered under.
+	RealtimeChannel = "matchmaking"
+
+	queueKey    = "matchmaking:queue"   // sorted set of user IDs by rating
+	entriesKey  = "matchmaking:entries" // hash of user ID -> Entry
+	lockKey     = "matchmaking:lock"
+	eventsKey   = "matchmaking:events"
+	matchPrefix = "matchmaking:match:"
+	lockTTL     = 10 * time.Second
+)
+
+var (
+	ErrAlreadyQueued = game.NewError("already_queued", http.StatusConflict)
+	ErrNotQueued     = game.NewError("not_queued", http.StatusConflict)
+)
+
+// RatingSource looks up a player's skill rating by account ID.
+type RatingSource interface {
+	Rating(ctx context.Context, userID string) (float64, error)
+}
+
+type Match struct {
+	GameID     string `json:"gameId"`
+	JoinCode   string `json:"joinCode"`
+	Token      string `json:"token"`
+	RejoinCode string `json:"rejoinCode"`
+}
+
+type Status struct {
+	State       string    `json:"state"` // "idle", "queued" or "matched"
+	Rating      float64   `json:"rating,omitempty"`
+	QueuedAt    time.Time `json:"queuedAt,omitzero"`
+	WaitSeconds int       `json:"waitSeconds,omitempty"`
+	QueueSize   int       `json:"queueSize"`
+	Match       *Match    `json:"match,omitempty"`
+}
+
+type notification struct {
+	UserID string         `json:"userId"`
+	Type   string         `json:"type"`
+	Data   map[string]any `json:"data"`
+}
+
+/*
+Queue is the ranked matchmaking queue. Entries live in Redis so any instance
+can take them; a short Redis lock, extended while matches are being set up,
+makes sure only one instance runs the matcher at a time. Should it still
+expire, each player is claimed out of the queue on their own, so nobody is
+matched twice. Matches are stored for MatchResultTTL and announced over
+pub/sub to whichever instance holds the player's queue socket.
+*/
+type Queue struct {
+	redis      *redis.Client
+	games      *game.Store
+	ratings    RatingSource
+	instanceID string
+}
+
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+var extendLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// claimEntry takes an entry out of the queue, unless it changed since it was read.
+var claimEntry = redis.NewScript(`
+if redis.call("HGET", KEYS[1], ARGV[1]) == ARGV[2] then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+	redis.call("ZREM", KEYS[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// NewQueue builds the queue. With no rating source everyone plays at
+// DefaultRating.
+func NewQueue(redisClient *redis.Client, games *game.Store, ratings RatingSource) *Queue {
+	return &Queue{
+		redis:      redisClient,
+		games:      games,
+		ratings:    ratings,
+		instanceID: uuid.NewString(),
+	}
+}
+
+func (queue *Queue) Enqueue(userID string, nickname string) (*Status, error) {
+	ctx := context.Background()
+
+	rating := DefaultRating
+	if queue.ratings != nil {
+		var err error
+		if rating, err = queue.ratings.Rating(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := Entry{
+		UserID:   userID,
+		Nickname: nickname,
+		Rating:   rating,
+		QueuedAt: time.Now().UTC(),
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := queue.redis.HSetNX(ctx, entriesKey, userID, data).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to enqueue player.")
+		return nil, err
+	}
+	if !added {
+		return nil, ErrAlreadyQueued
+	}
+
+	_, err = queue.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, queueKey, redis.Z{Score: rating, Member: userID})
+		pipe.Del(ctx, matchPrefix+userID)
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to enqueue player.")
+		return nil, err
+	}
+
+	return queue.Status(userID)
+}
+
+func (queue *Queue) Leave(userID string) error {
+	ctx := context.Background()
+
+	removed, err := queue.redis.HDel(ctx, entriesKey, userID).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to remove player from queue.")
+		return err
+	}
+	if removed == 0 {
+		return ErrNotQueued
+	}
+
+	return queue.redis.ZRem(ctx, queueKey, userID).Err()
+}
+
+func (queue *Queue) Status(userID string) (*Status, error) {
+	ctx := context.Background()
+
+	queueSize, err := queue.redis.ZCard(ctx, queueKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := queue.redis.HGet(ctx, entriesKey, userID).Bytes()
+	if err == nil {
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+
+		return &Status{
+			State:       "queued",
+			Rating:      entry.Rating,
+			QueuedAt:    entry.QueuedAt,
+			WaitSeconds: int(time.Since(entry.QueuedAt).Seconds()),
+			QueueSize:   int(queueSize),
+		}, nil
+	}
+	if err != redis.Nil {
+		return nil, err
+	}
+
+	matchData, err := queue.redis.Get(ctx, matchPrefix+userID).Bytes()
+	if err == redis.Nil {
+		return &Status{State: "idle", QueueSize: int(queueSize)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var match Match
+	if err := json.Unmarshal(matchData, &match); err != nil {
+		return nil, err
+	}
+
+	return &Status{State: "matched", QueueSize: int(queueSize), Match: &match}, nil
+}
+
+// Run matches players every MatchInterval until ctx is done.
+func (queue *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(MatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			queue.tick(ctx)
+		}
+	}
+}
+
+func (queue *Queue) tick(ctx context.Context) {
+	locked, err := queue.redis.SetNX(ctx, lockKey, queue.instanceID, lockTTL).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to take matchmaking lock.")
+		return
+	}
+	if !locked {
+		return
+	}
+	defer releaseLock.Run(ctx, queue.redis, []string{lockKey}, queue.instanceID)
+
+	entries, err := queue.entries(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load matchmaking queue.")
+		return
+	}
+
+	for _, group := range formGroups(entries, time.Now()) {
+		held, err := extendLock.Run(ctx, queue.redis, []string{lockKey}, queue.instanceID, lockTTL.Milliseconds()).Int()
+		if err != nil || held == 0 {
+			log.Warn().Err(err).Msg("Lost the matchmaking lock; leaving the rest to the next tick.")
+			return
+		}
+		queue.startMatch(ctx, group)
+	}
+
+	for _, entry := range entries {
+		queue.notify(ctx, entry.UserID, "queue_status", map[string]any{
+			"waitSeconds": int(time.Since(entry.QueuedAt).Seconds()),
+			"queueSize":   len(entries),
+		})
+	}
+}
+
+func (queue *Queue) entries(ctx context.Context) ([]Entry, error) {
+	values, err := queue.redis.HGetAll(ctx, entriesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		var entry Entry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			log.Error().Err(err).Msg("Failed to unmarshal queue entry.")
+			continue
+		}
+		entry.stored = value
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+/*
+startMatch takes the group out of the queue and seats it in a private room,
+the first player as admin. Anyone who left the queue in the meantime, or
+was matched and queued again, is skipped; if that leaves too few players the
+rest go back in line.
+*/
+func (queue *Queue) startMatch(ctx context.Context, group []Entry) {
+	players := []Entry{}
+	for _, entry := range group {
+		claimed, err := claimEntry.Run(ctx, queue.redis, []string{entriesKey, queueKey}, entry.UserID, entry.stored).Int()
+		if err != nil || claimed == 0 {
+			continue
+		}
+		players = append(players, entry)
+	}
+
+	if len(players) < MinGroupSize {
+		queue.requeue(ctx, players)
+		return
+	}
+
+	maxPlayers := len(players)
+	private := true
+	admin, err := queue.games.CreateGameRoom(players[0].Nickname, &game.RoomSettingsPatch{
+		MaxPlayers: &maxPlayers,
+		Private:    &private,
+	}, players[0].UserID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create matchmaking room.")
+		queue.requeue(ctx, players)
+		return
+	}
+
+	results := map[string]*game.OnboardingResult{players[0].UserID: admin}
+	for _, entry := range players[1:] {
+		result, err := queue.games.Join(admin.Game.JoinCode, entry.Nickname, entry.UserID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to seat matched player.")
+			queue.requeue(ctx, []Entry{entry})
+			continue
+		}
+		results[entry.UserID] = result
+	}
+
+	for userID, result := range results {
+		match := Match{
+			GameID:     result.Game.GameID,
+			JoinCode:   result.Game.JoinCode,
+			Token:      result.Token,
+			RejoinCode: result.RejoinCode,
+		}
+
+		data, err := json.Marshal(match)
+		if err == nil {
+			_ = queue.redis.Set(ctx, matchPrefix+userID, data, MatchResultTTL).Err()
+		}
+
+		queue.notify(ctx, userID, "match_found", map[string]any{
+			"match": match,
+		})
+	}
+
+	log.Info().Int("players", len(results)).Msg("Matchmaking created a room.")
+}
+
+func (queue *Queue) requeue(ctx context.Context, entries []Entry) {
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		_ = queue.redis.HSet(ctx, entriesKey, entry.UserID, data).Err()
+		_ = queue.redis.ZAdd(ctx, queueKey, redis.Z{Score: entry.Rating, Member: entry.UserID}).Err()
+	}
+}
+
+func (queue *Queue) notify(ctx context.Context, userID string, eventType string, data map[string]any) {
+	message, err := json.Marshal(notification{UserID: userID, Type: eventType, Data: data})
+	if err != nil {
+		return
+	}
+
+	if err := queue.redis.Publish(ctx, eventsKey, message).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to publish matchmaking event.")
+	}
+}
+
+/*
+Listen forwards matchmaking events to the queue sockets connected to this
+instance, until ctx is done.
+*/
+func (queue *Queue) Listen(ctx context.Context) {
+	pubsub := queue.redis.Subscribe(ctx, eventsKey)
+	defer pubsub.Close()
+
+	for message := range pubsub.Channel() {
+		var event notification
+		if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+			log.Error().Err(err).Msg("Failed to decode matchmaking event.")
+			continue
+		}
+
+		payload, err := json.Marshal(map[string]any{
+			"type":      event.Type,
+			"timestamp": time.Now().UTC(),
+			"payload":   event.Data,
+		})
+		if err != nil {
+			continue
+		}
+
+		realtime.Manager.SendToPlayer(RealtimeChannel, event.UserID, payload)
+	}
+}
@@ -0,0 +1,87 @@
+package matchmaking
+
+import (
+	"context"
+	"fmt"
+	"influence_game/internal/game"
+	"influence_game/internal/sessions"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestQueues builds queues for two instances sharing one in-memory Redis.
+func newTestQueues(t *testing.T) (*Queue, *Queue, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	games := game.NewStore(client, sessions.NewVerifier([]byte("secret"), client))
+	return NewQueue(client, games, nil), NewQueue(client, games, nil), server
+}
+
+func TestStaleGroupCannotMatchPlayersTwice(t *testing.T) {
+	first, second, _ := newTestQueues(t)
+	ctx := context.Background()
+
+	for i := range MinGroupSize {
+		userID := fmt.Sprintf("user-%d", i)
+		if _, err := first.Enqueue(userID, userID); err != nil {
+			t.Fatalf("unexpected enqueue error %v", err)
+		}
+	}
+	group, err := first.entries(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	first.startMatch(ctx, group)
+	matched, _ := first.Status("user-0")
+	if matched.State != "matched" {
+		t.Fatalf("expected user-0 to be matched, got %s", matched.State)
+	}
+
+	// user-0 queues again while another instance still holds the old group.
+	requeued, err := first.Enqueue("user-0", "user-0")
+	if err != nil {
+		t.Fatalf("unexpected enqueue error %v", err)
+	}
+	second.startMatch(ctx, group)
+
+	status, _ := second.Status("user-0")
+	if status.State != "queued" || !status.QueuedAt.Equal(requeued.QueuedAt) {
+		t.Fatalf("expected user-0's new entry to be left alone, got %+v", status)
+	}
+	for _, userID := range []string{"user-1", "user-2", "user-3"} {
+		status, _ := second.Status(userID)
+		if status.State != "matched" || status.Match.GameID != matched.Match.GameID {
+			t.Fatalf("expected %s to keep the first match, got %+v", userID, status)
+		}
+	}
+}
+
+func TestLockIsExtendedWhileMatching(t *testing.T) {
+	first, second, server := newTestQueues(t)
+	ctx := context.Background()
+
+	if err := server.Set(lockKey, first.instanceID); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	server.SetTTL(lockKey, lockTTL)
+	server.FastForward(lockTTL / 2)
+
+	if held, _ := extendLock.Run(ctx, first.redis, []string{lockKey}, first.instanceID, lockTTL.Milliseconds()).Int(); held != 1 {
+		t.Fatalf("expected the holder to extend the lock")
+	}
+	if held, _ := extendLock.Run(ctx, second.redis, []string{lockKey}, second.instanceID, lockTTL.Milliseconds()).Int(); held != 0 {
+		t.Fatalf("expected another instance not to extend the lock")
+	}
+
+	server.FastForward(lockTTL * 3 / 4)
+	if owner, _ := server.Get(lockKey); owner != first.instanceID {
+		t.Fatalf("expected the extended lock to outlive its first TTL, got %q", owner)
+	}
+}
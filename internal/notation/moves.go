@@ -0,0 +1,234 @@
+package notation
+
+import (
+	"fmt"
+	"influence_game/internal/game"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+Moves are written one per line, naming players by seat:
+
+	P1 declare tax as Duke
+	P1 declare steal on P3 as Captain
+	P2 declare coup on P1
+	P3 pass | P3 challenge | P3 block Contessa
+	P1 lose Duke
+	P1 exchange Duke Captain      (the roles kept)
+	P2 show Duke                  (the card shown to an examiner)
+	P1 examine swap | P1 examine keep
+	P1 draft Duke Captain         (two-player draft)
+
+Role names with spaces are double quoted.
+*/
+
+func formatMove(command game.Command, seats map[string]string) (string, error) {
+	seat, ok := seats[command.PlayerID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownSeat, command.PlayerID)
+	}
+
+	parts := []string{seat}
+
+	switch command.Type {
+	case game.CommandDeclare:
+		if command.Action == nil {
+			return "", game.ErrInvalidCommand
+		}
+		parts = append(parts, "declare", command.Action.ActionName)
+		if command.Action.TargetPlayerID != nil {
+			target, ok := seats[*command.Action.TargetPlayerID]
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ErrUnknownSeat, *command.Action.TargetPlayerID)
+			}
+			parts = append(parts, "on", target)
+		}
+		if command.Action.ClaimedRole != "" {
+			parts = append(parts, "as", quoteRole(command.Action.ClaimedRole))
+		}
+
+	case game.CommandRespond:
+		if command.Response == nil {
+			return "", game.ErrInvalidCommand
+		}
+		parts = append(parts, command.Response.Response)
+		if command.Response.Response == game.ResponseBlock {
+			parts = append(parts, quoteRole(command.Response.Role))
+		}
+
+	case game.CommandLoseInfluence:
+		parts = append(parts, "lose")
+		parts = append(parts, quoteRoles(command.Roles)...)
+
+	case game.CommandExchange:
+		parts = append(parts, "exchange")
+		parts = append(parts, quoteRoles(command.Roles)...)
+
+	case game.CommandShowInfluence:
+		parts = append(parts, "show")
+		parts = append(parts, quoteRoles(command.Roles)...)
+
+	case game.CommandExamination:
+		choice := "keep"
+		if command.ForceSwap {
+			choice = "swap"
+		}
+		parts = append(parts, "examine", choice)
+
+	case game.CommandSelectInfluences:
+		parts = append(parts, "draft")
+		parts = append(parts, quoteRoles(command.Roles)...)
+
+	default:
+		return "", game.ErrInvalidCommand
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+func parseMove(line string, players map[string]string) (game.Command, error) {
+	invalid := func() (game.Command, error) {
+		return game.Command{}, fmt.Errorf("%w: %q", ErrInvalidMove, line)
+	}
+
+	tokens, err := tokenize(line)
+	if err != nil || len(tokens) < 2 {
+		return invalid()
+	}
+
+	playerID, ok := players[tokens[0]]
+	if !ok {
+		return game.Command{}, fmt.Errorf("%w: %s", ErrUnknownSeat, tokens[0])
+	}
+	command := game.Command{PlayerID: playerID}
+	verb, args := tokens[1], tokens[2:]
+
+	switch verb {
+	case "declare":
+		if len(args) == 0 {
+			return invalid()
+		}
+		action := &game.DeclareActionPayload{ActionName: args[0]}
+		for rest := args[1:]; len(rest) > 0; rest = rest[2:] {
+			if len(rest) < 2 {
+				return invalid()
+			}
+			switch rest[0] {
+			case "on":
+				targetID, ok := players[rest[1]]
+				if !ok {
+					return game.Command{}, fmt.Errorf("%w: %s", ErrUnknownSeat, rest[1])
+				}
+				action.TargetPlayerID = &targetID
+			case "as":
+				action.ClaimedRole = rest[1]
+			default:
+				return invalid()
+			}
+		}
+		command.Type = game.CommandDeclare
+		command.Action = action
+
+	case game.ResponsePass, game.ResponseChallenge:
+		if len(args) != 0 {
+			return invalid()
+		}
+		command.Type = game.CommandRespond
+		command.Response = &game.ActionResponse{Response: verb}
+
+	case game.ResponseBlock:
+		if len(args) != 1 {
+			return invalid()
+		}
+		command.Type = game.CommandRespond
+		command.Response = &game.ActionResponse{Response: verb, Role: args[0]}
+
+	case "lose":
+		if len(args) != 1 {
+			return invalid()
+		}
+		command.Type = game.CommandLoseInfluence
+		command.Roles = args
+
+	case "exchange":
+		if len(args) == 0 {
+			return invalid()
+		}
+		command.Type = game.CommandExchange
+		command.Roles = args
+
+	case "show":
+		if len(args) != 1 {
+			return invalid()
+		}
+		command.Type = game.CommandShowInfluence
+		command.Roles = args
+
+	case "examine":
+		if len(args) != 1 || (args[0] != "swap" && args[0] != "keep") {
+			return invalid()
+		}
+		command.Type = game.CommandExamination
+		command.ForceSwap = args[0] == "swap"
+
+	case "draft":
+		if len(args) == 0 {
+			return invalid()
+		}
+		command.Type = game.CommandSelectInfluences
+		command.Roles = args
+
+	default:
+		return invalid()
+	}
+
+	return command, nil
+}
+
+func quoteRole(role string) string {
+	if role == "" || strings.IndexFunc(role, func(r rune) bool { return unicode.IsSpace(r) || r == '"' }) >= 0 {
+		return strconv.Quote(role)
+	}
+	return role
+}
+
+func quoteRoles(roles []string) []string {
+	quoted := make([]string, 0, len(roles))
+	for _, role := range roles {
+		quoted = append(quoted, quoteRole(role))
+	}
+	return quoted
+}
+
+// tokenize splits a move on spaces, keeping double-quoted names whole.
+func tokenize(line string) ([]string, error) {
+	tokens := []string{}
+	rest := strings.TrimSpace(line)
+
+	for rest != "" {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, err
+			}
+			token, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+			rest = strings.TrimLeftFunc(rest[len(quoted):], unicode.IsSpace)
+			continue
+		}
+
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		tokens = append(tokens, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,268 @@
+/*
+Package notation writes finished games in a portable form anyone can store,
+share and check: the table (settings, role definitions, seed and seating),
+the hands as dealt, every move as a line of text, and the final state.
+Importing a document replays its moves through the rules engine and verifies
+that the deal and the ending come out exactly as written.
+*/
+package notation
+
+import (
+	"errors"
+	"fmt"
+	"influence_game/internal/game"
+	"net/http"
+	"reflect"
+)
+
+const Format = "influence/1"
+
+// MaxMoves bounds how long a document can be; real games end well before it.
+const MaxMoves = 2000
+
+var (
+	ErrUnsupportedFormat = game.NewError("unsupported_notation_format", http.StatusBadRequest)
+	ErrInvalidMove       = game.NewError("invalid_move", http.StatusBadRequest)
+	ErrUnknownSeat       = game.NewError("unknown_seat", http.StatusBadRequest)
+	ErrDealMismatch      = game.NewError("deal_mismatch", http.StatusUnprocessableEntity)
+	ErrResultMismatch    = game.NewError("result_mismatch", http.StatusUnprocessableEntity)
+	ErrIllegalMove       = game.NewError("illegal_move", http.StatusUnprocessableEntity)
+	ErrTooManyMoves      = game.NewError("too_many_moves", http.StatusBadRequest)
+	ErrInvalidSeating    = game.NewError("invalid_seating", http.StatusBadRequest)
+)
+
+type Notation struct {
+	Format   string                `json:"format"`
+	GameID   string                `json:"gameId,omitempty"`
+	Settings game.RoomSettings     `json:"settings"`
+	Roles    []game.RoleDefinition `json:"roles"`
+	Seed     int64                 `json:"seed"`
+	Players  []Seat                `json:"players"`
+	Admin    string                `json:"admin"`
+	Deal     Position              `json:"deal"`
+	Moves    []string              `json:"moves"`
+	Result   Position              `json:"result"`
+}
+
+// Seat is a player as seated at the table. Moves refer to players by seat.
+type Seat struct {
+	Seat     string `json:"seat"`
+	Nickname string `json:"nickname"`
+	IsBot    bool   `json:"isBot,omitempty"`
+}
+
+/*
+Position is the whole table at one moment, hidden cards included: every
+player's hand and coins, the cards still being drafted in the two-player
+variant, the deck in order and, once the game is over, the winner.
+*/
+type Position struct {
+	ToMove string                      `json:"toMove,omitempty"`
+	Hands  map[string][]game.Influence `json:"hands"`
+	Coins  map[string]int              `json:"coins"`
+	Drafts map[string][]game.Influence `json:"drafts,omitempty"`
+	Deck   []game.Influence            `json:"deck"`
+	Winner string                      `json:"winner,omitempty"`
+}
+
+// Verification is what Import found when it played a document through.
+type Verification struct {
+	Moves  int        `json:"moves"`
+	Winner string     `json:"winner,omitempty"`
+	Final  *game.Game `json:"-"`
+}
+
+// Export writes a finished game's replay log in notation.
+func Export(replayLog *game.ReplayLog) (*Notation, error) {
+	steps, err := game.Replay(replayLog)
+	if err != nil {
+		return nil, err
+	}
+	setup := replayLog.Setup
+
+	seats := map[string]string{}
+	players := make([]Seat, 0, len(setup.Players))
+	for i, player := range setup.Players {
+		seat := seatName(i)
+		seats[player.ID] = seat
+		players = append(players, Seat{Seat: seat, Nickname: player.Nickname, IsBot: player.IsBot})
+	}
+
+	moves := make([]string, 0, len(replayLog.Commands))
+	for _, command := range replayLog.Commands {
+		move, err := formatMove(command, seats)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, move)
+	}
+
+	return &Notation{
+		Format:   Format,
+		GameID:   replayLog.GameID,
+		Settings: setup.Settings,
+		Roles:    setup.Roles,
+		Seed:     setup.Seed,
+		Players:  players,
+		Admin:    seats[setup.AdminID],
+		Deal:     position(steps[0].State, seats),
+		Moves:    moves,
+		Result:   position(steps[len(steps)-1].State, seats),
+	}, nil
+}
+
+/*
+Import plays a document's moves from its seed and checks that the cards were
+dealt as written and that the game ends in the written result. Players are
+given their seat names as IDs.
+*/
+func Import(notation *Notation) (*Verification, error) {
+	if notation.Format != Format {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, notation.Format)
+	}
+	if err := validate(notation); err != nil {
+		return nil, err
+	}
+
+	seats := map[string]string{}
+	setup := &game.ReplaySetup{
+		Settings: notation.Settings,
+		Roles:    notation.Roles,
+		Seed:     notation.Seed,
+	}
+	for _, seated := range notation.Players {
+		if _, taken := seats[seated.Seat]; taken || seated.Seat == "" {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownSeat, seated.Seat)
+		}
+		seats[seated.Seat] = seated.Seat
+		setup.Players = append(setup.Players, game.Player{
+			ID:         seated.Seat,
+			Nickname:   seated.Nickname,
+			Influences: []game.Influence{},
+			IsBot:      seated.IsBot,
+		})
+	}
+	adminID, ok := seats[notation.Admin]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSeat, notation.Admin)
+	}
+	setup.AdminID = adminID
+
+	// The first turn is part of the deal: drawn from the seed, or chosen for
+	// a rematch. Seating it as written covers both.
+	setup.StartingPlayerID = seats[notation.Deal.ToMove]
+
+	replayLog := &game.ReplayLog{GameID: notation.GameID, Setup: setup}
+	for i, line := range notation.Moves {
+		command, err := parseMove(line, seats)
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+		replayLog.Commands = append(replayLog.Commands, command)
+	}
+
+	steps, err := game.Replay(replayLog)
+	if errors.Is(err, game.ErrReplayDiverged) {
+		return nil, fmt.Errorf("%w: %w", ErrIllegalMove, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := compare(ErrDealMismatch, notation.Deal, position(steps[0].State, seats)); err != nil {
+		return nil, err
+	}
+	final := steps[len(steps)-1].State
+	if err := compare(ErrResultMismatch, notation.Result, position(final, seats)); err != nil {
+		return nil, err
+	}
+
+	verification := &Verification{Moves: len(notation.Moves)}
+	if final.WinnerID != nil {
+		verification.Winner = *final.WinnerID
+	}
+	return verification, nil
+}
+
+/*
+validate checks what a document asks the engine to build before anything is
+dealt: the settings as a room would check them, the roles as a role pack
+would, and how many seats and moves it carries.
+*/
+func validate(notation *Notation) error {
+	pack := game.RolePack{Name: notation.Settings.RolePack, Roles: notation.Roles}
+	if err := pack.Validate(); err != nil {
+		return err
+	}
+	if err := notation.Settings.Validate(); err != nil {
+		return err
+	}
+	copies := notation.Settings.DeckCopies(len(notation.Players))
+	if copies*len(notation.Roles) < 2*len(notation.Players)+1 {
+		return game.ErrNotEnoughInfluences
+	}
+
+	if len(notation.Players) < game.MinPlayers || len(notation.Players) > notation.Settings.MaxPlayers {
+		return fmt.Errorf("%w: %d players", ErrInvalidSeating, len(notation.Players))
+	}
+	if len(notation.Moves) > MaxMoves {
+		return fmt.Errorf("%w: %d", ErrTooManyMoves, len(notation.Moves))
+	}
+	return nil
+}
+
+func seatName(index int) string {
+	return fmt.Sprintf("P%d", index+1)
+}
+
+func position(state *game.Game, seats map[string]string) Position {
+	result := Position{
+		Hands: map[string][]game.Influence{},
+		Coins: map[string]int{},
+		Deck:  append([]game.Influence{}, state.Deck...),
+	}
+
+	for _, player := range state.Players {
+		seat := seats[player.ID]
+		result.Hands[seat] = append([]game.Influence{}, player.Influences...)
+		result.Coins[seat] = player.Coins
+	}
+
+	if len(state.Drafts) > 0 {
+		result.Drafts = map[string][]game.Influence{}
+		for playerID, dealt := range state.Drafts {
+			result.Drafts[seats[playerID]] = append([]game.Influence{}, dealt...)
+		}
+	}
+
+	if state.Finished {
+		if state.WinnerID != nil {
+			result.Winner = seats[*state.WinnerID]
+		}
+	} else if len(state.Players) > 0 {
+		result.ToMove = seats[state.Players[state.TurnIndex].ID]
+	}
+
+	return result
+}
+
+// compare reports the first part of the written position the replay disagrees with.
+func compare(mismatch error, written Position, played Position) error {
+	switch {
+	case written.ToMove != played.ToMove:
+		return fmt.Errorf("%w: %s to move, not %s", mismatch, played.ToMove, written.ToMove)
+	case written.Winner != played.Winner:
+		return fmt.Errorf("%w: winner is %q, not %q", mismatch, played.Winner, written.Winner)
+	case !reflect.DeepEqual(written.Hands, played.Hands):
+		return fmt.Errorf("%w: hands", mismatch)
+	case !reflect.DeepEqual(written.Coins, played.Coins):
+		return fmt.Errorf("%w: coins", mismatch)
+	case len(written.Drafts) != len(played.Drafts) ||
+		(len(played.Drafts) > 0 && !reflect.DeepEqual(written.Drafts, played.Drafts)):
+		return fmt.Errorf("%w: drafts", mismatch)
+	case len(written.Deck) != len(played.Deck) ||
+		(len(played.Deck) > 0 && !reflect.DeepEqual(written.Deck, played.Deck)):
+		return fmt.Errorf("%w: deck", mismatch)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package notation
+
+import (
+	"encoding/json"
+	"errors"
+	"influence_game/internal/game"
+	"math/rand"
+	"testing"
+)
+
+func playedGame(t *testing.T, playerCount int, variants []string, seed int64) *game.Game {
+	t.Helper()
+
+	settings := game.DefaultRoomSettings()
+	settings.Variants = variants
+	settings.MaxPlayers = playerCount
+	played, err := game.NewLocalGame(playerCount, settings, seed)
+	if err != nil {
+		t.Fatalf("unexpected setup error %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	for moves := 0; moves < 500 && !played.Finished; moves++ {
+		for _, player := range played.Players {
+			view := game.NewPlayerView(played, player.ID)
+
+			var err error
+			switch view.Decision {
+			case game.DecisionNone:
+				continue
+			case game.DecisionDeclare:
+				_, err = game.Declare(played, player.ID, view.LegalActions[rng.Intn(len(view.LegalActions))])
+			case game.DecisionRespond:
+				_, err = game.Respond(played, player.ID, view.LegalResponses[rng.Intn(len(view.LegalResponses))])
+			case game.DecisionLoseInfluence:
+				for _, influence := range view.Hand {
+					if !influence.Revealed {
+						_, err = game.LoseInfluence(played, player.ID, influence.Role)
+						break
+					}
+				}
+			case game.DecisionExchange:
+				pool := []string{}
+				for _, influence := range append(view.Options, view.Hand...) {
+					if !influence.Revealed {
+						pool = append(pool, influence.Role)
+					}
+				}
+				_, err = game.CompleteExchange(played, player.ID, pool[:view.Keep])
+			case game.DecisionShowInfluence:
+				for _, influence := range view.Hand {
+					if !influence.Revealed {
+						_, err = game.ShowInfluence(played, player.ID, influence.Role)
+						break
+					}
+				}
+			case game.DecisionExamination:
+				_, err = game.CompleteExamination(played, player.ID, rng.Intn(2) == 0)
+			case game.DecisionDraft:
+				_, err = game.SelectInfluences(played, player.ID, []string{view.Options[0].Role, view.Options[1].Role})
+			}
+			if err != nil {
+				t.Fatalf("unexpected %s error %v", view.Decision, err)
+			}
+			break
+		}
+	}
+	if !played.Finished {
+		t.Fatalf("game did not finish")
+	}
+
+	return played
+}
+
+func exported(t *testing.T, played *game.Game) *Notation {
+	t.Helper()
+
+	replayLog, err := game.NewReplayLog(played)
+	if err != nil {
+		t.Fatalf("unexpected replay log error %v", err)
+	}
+	notation, err := Export(replayLog)
+	if err != nil {
+		t.Fatalf("unexpected export error %v", err)
+	}
+
+	// Go through JSON, as a shared document would.
+	data, err := json.Marshal(notation)
+	if err != nil {
+		t.Fatalf("unexpected marshal error %v", err)
+	}
+	var decoded Notation
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected unmarshal error %v", err)
+	}
+	return &decoded
+}
+
+func TestExportedGamesImportAndVerify(t *testing.T) {
+	cases := []struct {
+		name     string
+		players  int
+		variants []string
+	}{
+		{"base", 4, []string{}},
+		{"two player draft", 2, []string{game.VariantTwoPlayer}},
+		{"reformation", 5, []string{game.VariantReformation}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			played := playedGame(t, tc.players, tc.variants, 42)
+			notation := exported(t, played)
+
+			if len(notation.Moves) != len(played.Commands) {
+				t.Fatalf("expected %d moves, got %d", len(played.Commands), len(notation.Moves))
+			}
+
+			verification, err := Import(notation)
+			if err != nil {
+				t.Fatalf("unexpected import error %v", err)
+			}
+
+			winner := 0
+			for i, player := range played.Players {
+				if player.ID == *played.WinnerID {
+					winner = i
+				}
+			}
+			if verification.Winner != seatName(winner) {
+				t.Fatalf("expected winner %s, got %s", seatName(winner), verification.Winner)
+			}
+		})
+	}
+}
+
+func TestImportRejectsATamperedDocument(t *testing.T) {
+	notation := exported(t, playedGame(t, 4, []string{}, 42))
+
+	tampered := *notation
+	tampered.Seed++
+	if _, err := Import(&tampered); !errors.Is(err, ErrDealMismatch) && !errors.Is(err, game.ErrReplayDiverged) {
+		t.Fatalf("expected a deal mismatch, got %v", err)
+	}
+
+	tampered = *notation
+	tampered.Result.Coins = map[string]int{"P1": 99}
+	if _, err := Import(&tampered); !errors.Is(err, ErrResultMismatch) {
+		t.Fatalf("expected a result mismatch, got %v", err)
+	}
+
+	tampered = *notation
+	tampered.Moves = append([]string{"P9 pass"}, notation.Moves...)
+	if _, err := Import(&tampered); !errors.Is(err, ErrUnknownSeat) {
+		t.Fatalf("expected an unknown seat, got %v", err)
+	}
+}
+
+func TestImportRejectsOversizedDocuments(t *testing.T) {
+	notation := exported(t, playedGame(t, 4, []string{}, 42))
+
+	tests := []struct {
+		name   string
+		tamper func(*Notation)
+		want   error
+	}{
+		{"huge deck", func(n *Notation) { n.Settings.CopiesPerRole = 5000000 }, game.ErrInvalidCopiesPerRole},
+		{"invalid settings", func(n *Notation) { n.Settings.MaxPlayers = 1000 }, game.ErrInvalidMaxPlayers},
+		{"unknown role action", func(n *Notation) {
+			n.Roles = append([]game.RoleDefinition{{Name: "Thief", Actions: []string{"rob"}}}, n.Roles[1:]...)
+		}, game.ErrUnknownAction},
+		{"too many roles", func(n *Notation) {
+			for i := 0; i < game.MaxRolesPerPack; i++ {
+				n.Roles = append(n.Roles, game.RoleDefinition{Name: seatName(i)})
+			}
+		}, game.ErrInvalidRolePack},
+		{"too many players", func(n *Notation) {
+			for i := len(n.Players); i <= n.Settings.MaxPlayers; i++ {
+				n.Players = append(n.Players, Seat{Seat: seatName(i), Nickname: seatName(i)})
+			}
+		}, ErrInvalidSeating},
+		{"too many moves", func(n *Notation) {
+			for len(n.Moves) <= MaxMoves {
+				n.Moves = append(n.Moves, "P1 income")
+			}
+		}, ErrTooManyMoves},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tampered := *notation
+			tampered.Roles = append([]game.RoleDefinition{}, notation.Roles...)
+			tampered.Players = append([]Seat{}, notation.Players...)
+			tampered.Moves = append([]string{}, notation.Moves...)
+			test.tamper(&tampered)
+
+			_, err := Import(&tampered)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, err)
+			}
+			if status := game.AsError(err).Status; status < 400 || status >= 500 {
+				t.Fatalf("expected a client error, got %d", status)
+			}
+		})
+	}
+}
+
+func TestMovesRoundTrip(t *testing.T) {
+	seats := map[string]string{"a": "P1", "b": "P2"}
+	players := map[string]string{"P1": "a", "P2": "b"}
+	target := "b"
+
+	commands := []game.Command{
+		{Type: game.CommandDeclare, PlayerID: "a", Action: &game.DeclareActionPayload{ActionName: "steal", TargetPlayerID: &target, ClaimedRole: "Captain"}},
+		{Type: game.CommandRespond, PlayerID: "b", Response: &game.ActionResponse{Response: game.ResponseBlock, Role: "Grand Inquisitor"}},
+		{Type: game.CommandExchange, PlayerID: "a", Roles: []string{"Duke", `The "Boss"`}},
+		{Type: game.CommandShowInfluence, PlayerID: "b", Roles: []string{"Contessa"}},
+		{Type: game.CommandExamination, PlayerID: "a", ForceSwap: true},
+	}
+	want := []string{
+		"P1 declare steal on P2 as Captain",
+		`P2 block "Grand Inquisitor"`,
+		`P1 exchange Duke "The \"Boss\""`,
+		"P2 show Contessa",
+		"P1 examine swap",
+	}
+
+	for i, command := range commands {
+		line, err := formatMove(command, seats)
+		if err != nil || line != want[i] {
+			t.Fatalf("expected %q, got %q (%v)", want[i], line, err)
+		}
+
+		parsed, err := parseMove(line, players)
+		if err != nil {
+			t.Fatalf("unexpected parse error %v", err)
+		}
+		again, _ := formatMove(parsed, seats)
+		if again != line {
+			t.Fatalf("expected %q after parsing, got %q", line, again)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package ratings
+
+import (
+	"influence_game/internal/game"
+	"math"
+)
+
+/*
+Placements returns the finishers of a game from first to last, leaving out
+players without a user ID (guests and bots) so only accounts are ranked.
+*/
+func Placements(finished *game.Game) []*game.Player {
+	placed := []*game.Player{}
+	for _, player := range game.FinishingOrder(finished) {
+		if player.UserID != "" {
+			placed = append(placed, player)
+		}
+	}
+	return placed
+}
+
+/*
+Update applies multiplayer Elo to ratings given in placement order. The game
+counts as a win against everyone placed below and a loss against everyone
+above, each pairing weighted K/(n-1) so a full table moves a rating as much
+as a single duel would.
+*/
+func Update(ratings []float64) []float64 {
+	updated := append([]float64{}, ratings...)
+	if len(ratings) < 2 {
+		return updated
+	}
+
+	weight := K / float64(len(ratings)-1)
+	for i := range ratings {
+		delta := 0.0
+		for j := range ratings {
+			if i == j {
+				continue
+			}
+			score := 0.0
+			if i < j {
+				score = 1
+			}
+			delta += weight * (score - expected(ratings[i], ratings[j]))
+		}
+		updated[i] = ratings[i] + delta
+	}
+
+	return updated
+}
+
+func expected(rating float64, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
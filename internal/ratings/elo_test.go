@@ -0,0 +1,68 @@
+package ratings
+
+import (
+	"influence_game/internal/game"
+	"math"
+	"testing"
+)
+
+func TestUpdateRewardsPlacement(t *testing.T) {
+	after := Update([]float64{1500, 1500, 1500, 1500})
+
+	for i := 1; i < len(after); i++ {
+		if after[i] >= after[i-1] {
+			t.Fatalf("placement %d got %.1f, not below %.1f", i+1, after[i], after[i-1])
+		}
+	}
+
+	total := 0.0
+	for _, rating := range after {
+		total += rating - 1500
+	}
+	if math.Abs(total) > 1e-9 {
+		t.Fatalf("ratings should be zero-sum, drifted by %f", total)
+	}
+	if after[0]-1500 != K/2 {
+		t.Fatalf("winner of an even table should gain K/2, got %.2f", after[0]-1500)
+	}
+}
+
+func TestUpsetMovesMoreThanExpectedResult(t *testing.T) {
+	upset := Update([]float64{1300, 1700})
+	expected := Update([]float64{1700, 1300})
+
+	if upset[0]-1300 <= expected[0]-1700 {
+		t.Fatalf("upset gain %.1f should beat expected gain %.1f", upset[0]-1300, expected[0]-1700)
+	}
+}
+
+func TestPlacementsSkipGuestsAndOrderByElimination(t *testing.T) {
+	winner := "p3"
+	finished := &game.Game{
+		Finished: true,
+		WinnerID: &winner,
+		Players: []*game.Player{
+			{ID: "p1", UserID: "u1"},
+			{ID: "p2"},
+			{ID: "p3", UserID: "u3"},
+			{ID: "p4", UserID: "u4"},
+		},
+		EliminatedPlayerIDs: []string{"p1", "p2", "p4"},
+	}
+
+	placed := Placements(finished)
+	got := []string{}
+	for _, player := range placed {
+		got = append(got, player.UserID)
+	}
+
+	want := []string{"u3", "u4", "u1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package ratings
+
+import (
+	"influence_game/internal/game"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultRating = 1500.0
+
+	// K is how far one game can move a rating, split across the opponents.
+	K = 32.0
+
+	// HistoryLength is how many past rating changes are kept per user.
+	HistoryLength = 100
+
+	DefaultLeaderboardSize = 50
+	MaxLeaderboardSize     = 100
+)
+
+var (
+	ErrInvalidUserID = game.NewError("invalid_user_id", http.StatusBadRequest)
+	ErrInvalidLimit  = game.NewError("invalid_limit", http.StatusBadRequest)
+)
+
+type Rating struct {
+	UserID    string    `json:"userId"`
+	Rating    float64   `json:"rating"`
+	Games     int       `json:"games"`
+	Wins      int       `json:"wins"`
+	UpdatedAt time.Time `json:"updatedAt,omitzero"`
+}
+
+// Change is one game's effect on a user's rating.
+type Change struct {
+	GameID      string    `json:"gameId"`
+	Placement   int       `json:"placement"` // 1 is the winner
+	PlayerCount int       `json:"playerCount"`
+	Before      float64   `json:"before"`
+	After       float64   `json:"after"`
+	PlayedAt    time.Time `json:"playedAt"`
+}
+
+type RatingWithHistory struct {
+	Rating
+	Username string   `json:"username,omitempty"`
+	History  []Change `json:"history"`
+}
+
+type LeaderboardEntry struct {
+	Rank int `json:"rank"`
+	Rating
+	Username string `json:"username,omitempty"`
+}
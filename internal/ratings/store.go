@@ -0,0 +1,224 @@
+package ratings
+
+import (
+	"context"
+	"encoding/json"
+	"influence_game/internal/game"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	leaderboardKey = "ratings:leaderboard"
+
+	// How long a rated game is remembered, so it is never rated twice.
+	ratedGameTTL = 7 * 24 * time.Hour
+)
+
+/*
+Store keeps ratings in Redis, keyed by account ID:
+
+	rating:{userID}          current rating
+	rating:history:{userID}  latest changes first, capped at HistoryLength
+	rating:game:{gameID}     marks a game as already rated
+	ratings:leaderboard      sorted set of user IDs by rating
+*/
+type Store struct {
+	redis *redis.Client
+}
+
+func NewStore(redisClient *redis.Client) *Store {
+	return &Store{
+		redis: redisClient,
+	}
+}
+
+/*
+HandleGameFinished is meant to be registered with game.Store.OnGameFinished.
+Ratings are updated in the background, off the request that finished the
+game.
+*/
+func (store *Store) HandleGameFinished(finished *game.Game) {
+	go func() {
+		if err := store.RecordGame(finished); err != nil {
+			log.Error().Err(err).Str("gameID", finished.ID).Msg("Failed to update ratings.")
+		}
+	}()
+}
+
+/*
+RecordGame rates a finished game once. The ratings of every participant are
+watched, so two games finishing at once for the same user never overwrite
+each other.
+*/
+func (store *Store) RecordGame(finished *game.Game) error {
+	placed := Placements(finished)
+	if len(placed) < 2 {
+		return nil
+	}
+
+	ctx := context.Background()
+	gameKey := "rating:game:" + finished.ID
+
+	keys := []string{gameKey}
+	for _, player := range placed {
+		keys = append(keys, ratingKey(player.UserID))
+	}
+
+	for {
+		err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
+			rated, err := tx.Exists(ctx, gameKey).Result()
+			if err != nil || rated > 0 {
+				return err
+			}
+
+			current := make([]Rating, len(placed))
+			before := make([]float64, len(placed))
+			for i, player := range placed {
+				rating, err := getRating(ctx, tx, player.UserID)
+				if err != nil {
+					return err
+				}
+				current[i] = *rating
+				before[i] = rating.Rating
+			}
+
+			after := Update(before)
+			now := time.Now().UTC()
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, gameKey, "1", ratedGameTTL)
+
+				for i, rating := range current {
+					rating.Rating = after[i]
+					rating.Games++
+					if i == 0 {
+						rating.Wins++
+					}
+					rating.UpdatedAt = now
+
+					ratingJSON, err := json.Marshal(rating)
+					if err != nil {
+						return err
+					}
+					changeJSON, err := json.Marshal(Change{
+						GameID:      finished.ID,
+						Placement:   i + 1,
+						PlayerCount: len(placed),
+						Before:      before[i],
+						After:       after[i],
+						PlayedAt:    now,
+					})
+					if err != nil {
+						return err
+					}
+
+					historyKey := "rating:history:" + rating.UserID
+					pipe.Set(ctx, ratingKey(rating.UserID), ratingJSON, 0)
+					pipe.LPush(ctx, historyKey, changeJSON)
+					pipe.LTrim(ctx, historyKey, 0, HistoryLength-1)
+					pipe.ZAdd(ctx, leaderboardKey, redis.Z{Score: rating.Rating, Member: rating.UserID})
+				}
+				return nil
+			})
+			return err
+		}, keys...)
+
+		if err == redis.TxFailedErr {
+			continue
+		}
+		return err
+	}
+}
+
+// Rating returns a user's current rating. Unrated users start at DefaultRating.
+func (store *Store) Rating(ctx context.Context, userID string) (float64, error) {
+	rating, err := getRating(ctx, store.redis, userID)
+	if err != nil {
+		return 0, err
+	}
+	return rating.Rating, nil
+}
+
+func (store *Store) GetRating(userID string) (*RatingWithHistory, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	ctx := context.Background()
+
+	rating, err := getRating(ctx, store.redis, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := store.redis.LRange(ctx, "rating:history:"+userID, 0, -1).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get rating history from Redis.")
+		return nil, err
+	}
+
+	history := make([]Change, 0, len(entries))
+	for _, entry := range entries {
+		var change Change
+		if err := json.Unmarshal([]byte(entry), &change); err != nil {
+			log.Error().Err(err).Msg("Failed to unmarshal rating change.")
+			continue
+		}
+		history = append(history, change)
+	}
+
+	return &RatingWithHistory{Rating: *rating, History: history}, nil
+}
+
+// Leaderboard returns the top rated users, best first.
+func (store *Store) Leaderboard(limit int) ([]LeaderboardEntry, error) {
+	if limit <= 0 {
+		limit = DefaultLeaderboardSize
+	}
+	limit = min(limit, MaxLeaderboardSize)
+
+	ctx := context.Background()
+
+	userIDs, err := store.redis.ZRevRange(ctx, leaderboardKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get leaderboard from Redis.")
+		return nil, err
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(userIDs))
+	for _, userID := range userIDs {
+		rating, err := getRating(ctx, store.redis, userID)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, LeaderboardEntry{Rank: len(entries) + 1, Rating: *rating})
+	}
+
+	return entries, nil
+}
+
+func ratingKey(userID string) string {
+	return "rating:" + userID
+}
+
+func getRating(ctx context.Context, client redis.Cmdable, userID string) (*Rating, error) {
+	data, err := client.Get(ctx, ratingKey(userID)).Bytes()
+	if err == redis.Nil {
+		return &Rating{UserID: userID, Rating: DefaultRating}, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get rating from Redis.")
+		return nil, err
+	}
+
+	var rating Rating
+	if err := json.Unmarshal(data, &rating); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal rating.")
+		return nil, err
+	}
+
+	return &rating, nil
+}
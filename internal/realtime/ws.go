@@ -1,6 +1,7 @@
 package realtime
 
 import (
+	"influence_game/internal/versions"
 	"sync"
 
 	"github.com/gorilla/websocket"
@@ -10,6 +11,18 @@ type Client struct {
 	Conn     *websocket.Conn
 	GameID   string
 	PlayerID string
+	Language string // "" for the default language
+	Version  versions.Version
+}
+
+// Audience is what a message is rendered for: a socket's language and API version.
+type Audience struct {
+	Language string
+	Version  versions.Version
+}
+
+func (c *Client) audience() Audience {
+	return Audience{Language: c.Language, Version: c.Version}
 }
 
 type RoomManager struct {
@@ -49,25 +62,140 @@ func (m *RoomManager) RemoveClient(c *Client) {
 	}
 }
 
+// Broadcast sends a message written with v1 names, upgraded to each client's version.
 func (m *RoomManager) Broadcast(gameID string, msg []byte) {
+	m.BroadcastRendered(gameID, func(audience Audience) []byte {
+		return audience.Version.Upgrade(msg)
+	})
+}
+
+// SendToPlayer is Broadcast for one player's socket.
+func (m *RoomManager) SendToPlayer(gameID string, playerID string, msg []byte) {
+	m.SendToPlayerRendered(gameID, playerID, func(audience Audience) []byte {
+		return audience.Version.Upgrade(msg)
+	})
+}
+
+/*
+BroadcastRendered sends every client in the game the message rendered for its
+language and version. Each audience is rendered once; a nil message is not
+sent.
+*/
+func (m *RoomManager) BroadcastRendered(gameID string, render func(audience Audience) []byte) {
 	m.mu.RLock()
 	clients := m.rooms[gameID]
+	audiences := make([]Audience, len(clients))
+	for i, c := range clients {
+		audiences[i] = c.audience()
+	}
 	m.mu.RUnlock()
 
-	for _, c := range clients {
-		_ = c.Conn.WriteMessage(websocket.TextMessage, msg)
+	rendered := make(map[Audience][]byte)
+	for i, c := range clients {
+		msg, ok := rendered[audiences[i]]
+		if !ok {
+			msg = render(audiences[i])
+			rendered[audiences[i]] = msg
+		}
+		if msg != nil {
+			_ = c.Conn.WriteMessage(websocket.TextMessage, msg)
+		}
 	}
 }
 
-func (m *RoomManager) SendToPlayer(gameID string, playerID string, msg []byte) {
+// SendToPlayerRendered is BroadcastRendered for one player's socket.
+func (m *RoomManager) SendToPlayerRendered(gameID string, playerID string, render func(audience Audience) []byte) {
 	m.mu.RLock()
-	clients := m.rooms[gameID]
+	var client *Client
+	var audience Audience
+	for _, c := range m.rooms[gameID] {
+		if c.PlayerID == playerID {
+			client, audience = c, c.audience()
+			break
+		}
+	}
 	m.mu.RUnlock()
 
+	if client == nil {
+		return
+	}
+	if msg := render(audience); msg != nil {
+		_ = client.Conn.WriteMessage(websocket.TextMessage, msg)
+	}
+}
+
+// SetLanguage switches the language of every socket the player has open in the game.
+func (m *RoomManager) SetLanguage(gameID string, playerID string, language string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.rooms[gameID] {
+		if c.PlayerID == playerID {
+			c.Language = language
+		}
+	}
+}
+
+// DisconnectPlayer closes every socket the player has open in the game.
+func (m *RoomManager) DisconnectPlayer(gameID string, playerID string) {
+	m.mu.Lock()
+	clients := m.rooms[gameID]
+	remaining := make([]*Client, 0, len(clients))
+	var closing []*Client
 	for _, c := range clients {
 		if c.PlayerID == playerID {
-			_ = c.Conn.WriteMessage(websocket.TextMessage, msg)
-			return
+			closing = append(closing, c)
+		} else {
+			remaining = append(remaining, c)
 		}
 	}
+	if len(remaining) == 0 {
+		delete(m.rooms, gameID)
+	} else {
+		m.rooms[gameID] = remaining
+	}
+	m.mu.Unlock()
+
+	for _, c := range closing {
+		_ = c.Conn.Close()
+	}
+}
+
+// GameIDs lists every game with a socket open on this instance.
+func (m *RoomManager) GameIDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	gameIDs := make([]string, 0, len(m.rooms))
+	for gameID := range m.rooms {
+		gameIDs = append(gameIDs, gameID)
+	}
+	return gameIDs
+}
+
+// DisconnectGame closes every socket open in the game.
+func (m *RoomManager) DisconnectGame(gameID string) {
+	m.mu.Lock()
+	clients := m.rooms[gameID]
+	delete(m.rooms, gameID)
+	m.mu.Unlock()
+
+	for _, c := range clients {
+		_ = c.Conn.Close()
+	}
+}
+
+// MoveGame hands every socket open in one game over to another, and returns whose they were.
+func (m *RoomManager) MoveGame(fromGameID string, toGameID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	playerIDs := []string{}
+	for _, c := range m.rooms[fromGameID] {
+		c.GameID = toGameID
+		m.rooms[toGameID] = append(m.rooms[toGameID], c)
+		playerIDs = append(playerIDs, c.PlayerID)
+	}
+	delete(m.rooms, fromGameID)
+	return playerIDs
 }
@@ -0,0 +1,215 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const revocationsChannel = "sessions:revoked"
+
+type revocation struct {
+	Kind      string `json:"kind"` // "token" or "seat"
+	Key       string `json:"key"`
+	NotBefore int64  `json:"notBefore,omitempty"` // seat revocations: tokens issued before this are dead
+	Until     int64  `json:"until"`               // unix seconds after which the entry can be forgotten
+}
+
+/*
+Verifier issues and checks session tokens. It is the only place tokens are
+validated, for REST and WebSocket alike.
+
+Revocations are written to Redis (revoked:token:{jti} and
+revoked:seat:{gameID}:{playerID}) and announced on a pub/sub channel; every
+instance keeps them in memory, so checking a token never goes to Redis.
+*/
+type Verifier struct {
+	secret []byte
+	redis  *redis.Client
+	now    func() time.Time
+
+	mu            sync.RWMutex
+	revokedTokens map[string]revocation
+	revokedSeats  map[string]revocation
+}
+
+func NewVerifier(secret []byte, redisClient *redis.Client) *Verifier {
+	return &Verifier{
+		secret:        secret,
+		redis:         redisClient,
+		now:           time.Now,
+		revokedTokens: map[string]revocation{},
+		revokedSeats:  map[string]revocation{},
+	}
+}
+
+func (verifier *Verifier) Issue(gameID, playerID, role string, ttl time.Duration) (string, Claims, error) {
+	claims := newClaims(gameID, playerID, role, verifier.now(), ttl)
+
+	token, err := sign(verifier.secret, claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+
+	return token, claims, nil
+}
+
+func (verifier *Verifier) Verify(token string) (*Claims, error) {
+	claims, err := parse(verifier.secret, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifier.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	verifier.mu.RLock()
+	defer verifier.mu.RUnlock()
+
+	if _, revoked := verifier.revokedTokens[claims.ID]; revoked {
+		return nil, ErrRevokedToken
+	}
+	if seat, revoked := verifier.revokedSeats[seatKey(claims.GameID, claims.PlayerID)]; revoked && claims.IssuedAt < seat.NotBefore {
+		return nil, ErrRevokedToken
+	}
+
+	return &claims, nil
+}
+
+// RevokeToken kills a single token, e.g. on logout or rotation.
+func (verifier *Verifier) RevokeToken(ctx context.Context, claims Claims) error {
+	return verifier.publish(ctx, revocation{
+		Kind:  "token",
+		Key:   claims.ID,
+		Until: claims.ExpiresAt,
+	})
+}
+
+// RevokeSeat kills every token issued so far for a player, e.g. on a kick.
+func (verifier *Verifier) RevokeSeat(ctx context.Context, gameID, playerID string, maxTTL time.Duration) error {
+	now := verifier.now()
+
+	return verifier.publish(ctx, revocation{
+		Kind:      "seat",
+		Key:       seatKey(gameID, playerID),
+		NotBefore: now.UnixNano(),
+		Until:     now.Add(maxTTL).Unix(),
+	})
+}
+
+func (verifier *Verifier) publish(ctx context.Context, entry revocation) error {
+	verifier.remember(entry)
+
+	if verifier.redis == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(time.Unix(entry.Until, 0))
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := verifier.redis.Set(ctx, "revoked:"+entry.Kind+":"+entry.Key, data, ttl).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to save session revocation to Redis.")
+		return err
+	}
+	if err := verifier.redis.Publish(ctx, revocationsChannel, data).Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to publish session revocation.")
+		return err
+	}
+
+	return nil
+}
+
+/*
+Listen loads the revocations already in Redis and then follows the ones other
+instances publish, until ctx is done. Run it once per process.
+*/
+func (verifier *Verifier) Listen(ctx context.Context) {
+	if verifier.redis == nil {
+		return
+	}
+
+	pubsub := verifier.redis.Subscribe(ctx, revocationsChannel)
+	defer pubsub.Close()
+
+	iter := verifier.redis.Scan(ctx, 0, "revoked:*", 100).Iterator()
+	for iter.Next(ctx) {
+		data, err := verifier.redis.Get(ctx, iter.Val()).Bytes()
+		if err != nil {
+			continue
+		}
+		verifier.rememberJSON(data)
+	}
+	if err := iter.Err(); err != nil {
+		log.Error().Err(err).Msg("Failed to load session revocations.")
+	}
+
+	for message := range pubsub.Channel() {
+		verifier.rememberJSON([]byte(message.Payload))
+	}
+}
+
+func (verifier *Verifier) rememberJSON(data []byte) {
+	var entry revocation
+	if err := json.Unmarshal(data, &entry); err != nil {
+		log.Error().Err(err).Msg("Failed to decode session revocation.")
+		return
+	}
+	verifier.remember(entry)
+}
+
+func (verifier *Verifier) remember(entry revocation) {
+	verifier.mu.Lock()
+	defer verifier.mu.Unlock()
+
+	verifier.forgetExpired()
+
+	switch entry.Kind {
+	case "token":
+		verifier.revokedTokens[entry.Key] = entry
+	case "seat":
+		if current, ok := verifier.revokedSeats[entry.Key]; !ok || current.NotBefore < entry.NotBefore {
+			verifier.revokedSeats[entry.Key] = entry
+		}
+	}
+}
+
+// ForgetExpired drops revocations that no longer matter, for long idle instances.
+func (verifier *Verifier) ForgetExpired() {
+	verifier.mu.Lock()
+	defer verifier.mu.Unlock()
+
+	verifier.forgetExpired()
+}
+
+// forgetExpired drops entries whose tokens have all expired anyway.
+func (verifier *Verifier) forgetExpired() {
+	now := verifier.now().Unix()
+
+	for key, entry := range verifier.revokedTokens {
+		if entry.Until < now {
+			delete(verifier.revokedTokens, key)
+		}
+	}
+	for key, entry := range verifier.revokedSeats {
+		if entry.Until < now {
+			delete(verifier.revokedSeats, key)
+		}
+	}
+}
+
+func seatKey(gameID, playerID string) string {
+	return strings.Join([]string{gameID, playerID}, ":")
+}
@@ -0,0 +1,76 @@
+package sessions
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIssuedTokenVerifies(t *testing.T) {
+	verifier := NewVerifier([]byte("secret"), nil)
+
+	token, issued, err := verifier.Issue("game", "player", RoleAdmin, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if *claims != issued {
+		t.Fatalf("expected %+v, got %+v", issued, claims)
+	}
+}
+
+func TestTamperedOrForeignTokenIsRejected(t *testing.T) {
+	verifier := NewVerifier([]byte("secret"), nil)
+	token, _, _ := verifier.Issue("game", "player", RolePlayer, time.Hour)
+
+	if _, err := NewVerifier([]byte("other"), nil).Verify(token); err != ErrBadSignature {
+		t.Fatalf("expected bad signature, got %v", err)
+	}
+
+	forged, _ := sign([]byte("other"), Claims{GameID: "game", PlayerID: "admin"})
+	if _, err := verifier.Verify(forged); err != ErrBadSignature {
+		t.Fatalf("expected bad signature, got %v", err)
+	}
+
+	if _, err := verifier.Verify("not-a-token"); err != ErrMalformedToken {
+		t.Fatalf("expected malformed token, got %v", err)
+	}
+}
+
+func TestExpiredTokenIsRejected(t *testing.T) {
+	verifier := NewVerifier([]byte("secret"), nil)
+	token, _, _ := verifier.Issue("game", "player", RolePlayer, time.Minute)
+
+	verifier.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+	if _, err := verifier.Verify(token); err != ErrExpiredToken {
+		t.Fatalf("expected expired token, got %v", err)
+	}
+}
+
+func TestRevokedSeatRejectsOlderTokensOnly(t *testing.T) {
+	verifier := NewVerifier([]byte("secret"), nil)
+	ctx := context.Background()
+
+	old, _, _ := verifier.Issue("game", "player", RolePlayer, time.Hour)
+	other, _, _ := verifier.Issue("game", "someone", RolePlayer, time.Hour)
+
+	if err := verifier.RevokeSeat(ctx, "game", "player", time.Hour); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	fresh, _, _ := verifier.Issue("game", "player", RolePlayer, time.Hour)
+
+	if _, err := verifier.Verify(old); err != ErrRevokedToken {
+		t.Fatalf("expected revoked token, got %v", err)
+	}
+	if _, err := verifier.Verify(other); err != nil {
+		t.Fatalf("expected other seats to be unaffected, got %v", err)
+	}
+	if _, err := verifier.Verify(fresh); err != nil {
+		t.Fatalf("expected tokens issued after the revocation to work, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package tournaments
+
+import (
+	"influence_game/internal/game"
+	"net/http"
+	"time"
+)
+
+const (
+	// Every game at one table, the same players each time.
+	FormatSeries = "series"
+	// Table winners advance until one player is left.
+	FormatBracket = "bracket"
+	// A fixed number of rounds; tables are drawn from the standings.
+	FormatSwiss = "swiss"
+
+	StatusRegistering = "registering"
+	StatusRunning     = "running"
+	StatusFinished    = "finished"
+
+	DefaultTableSize   = 4
+	DefaultSeriesGames = 3
+	DefaultSwissRounds = 3
+	MaxGames           = 15
+	MaxParticipants    = 64
+	MaxNameLength      = 60
+)
+
+var (
+	ErrTournamentNotFound    = game.NewError("tournament_not_found", http.StatusNotFound)
+	ErrInvalidFormat         = game.NewError("invalid_tournament_format", http.StatusBadRequest)
+	ErrInvalidName           = game.NewError("invalid_tournament_name", http.StatusBadRequest)
+	ErrInvalidGames          = game.NewError("invalid_tournament_games", http.StatusBadRequest)
+	ErrInvalidTableSize      = game.NewError("invalid_table_size", http.StatusBadRequest)
+	ErrInvalidPoints         = game.NewError("invalid_placement_points", http.StatusBadRequest)
+	ErrRegistrationClosed    = game.NewError("registration_closed", http.StatusConflict)
+	ErrAlreadyRegistered     = game.NewError("already_registered", http.StatusConflict)
+	ErrTournamentFull        = game.NewError("tournament_full", http.StatusConflict)
+	ErrOnlyOrganizerCanStart = game.NewError("only_organizer_can_start", http.StatusForbidden)
+	ErrNotEnoughParticipants = game.NewError("not_enough_participants", http.StatusConflict)
+	ErrNoSeat                = game.NewError("no_seat", http.StatusNotFound)
+)
+
+/*
+Options is what the organizer picks when creating a tournament. Games is the
+length of a series or the number of Swiss rounds; brackets run until one
+player is left. Points[i] is awarded for finishing (i+1)-th in a game, and
+defaults to one point per player beaten. Settings apply to every room.
+*/
+type Options struct {
+	Name      string                  `json:"name"`
+	Format    string                  `json:"format"`
+	Games     int                     `json:"games,omitempty"`
+	TableSize int                     `json:"tableSize,omitempty"`
+	Points    []int                   `json:"points,omitempty"`
+	Settings  *game.RoomSettingsPatch `json:"settings,omitempty"`
+}
+
+type Participant struct {
+	UserID   string `json:"userId"`
+	Nickname string `json:"nickname"`
+}
+
+type Tournament struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Format       string                  `json:"format"`
+	OrganizerID  string                  `json:"organizerId"`
+	Games        int                     `json:"games,omitempty"`
+	TableSize    int                     `json:"tableSize"`
+	Points       []int                   `json:"points,omitempty"`
+	Settings     *game.RoomSettingsPatch `json:"settings,omitempty"`
+	Participants []Participant           `json:"participants"`
+	Rounds       []Round                 `json:"rounds"`
+	Status       string                  `json:"status"`
+	WinnerID     string                  `json:"winnerId,omitempty"`
+	CreatedAt    time.Time               `json:"createdAt"`
+	FinishedAt   time.Time               `json:"finishedAt,omitzero"`
+}
+
+type Round struct {
+	Number int     `json:"number"`
+	Tables []Table `json:"tables"`
+}
+
+/*
+Table is one game of a round. A table with a single player is a bye: it is
+finished from the start, the player goes through and no points are awarded.
+*/
+type Table struct {
+	UserIDs    []string `json:"userIds"`
+	GameID     string   `json:"gameId,omitempty"`
+	Placements []string `json:"placements,omitempty"` // user IDs, winner first
+	Finished   bool     `json:"finished"`
+}
+
+type Standing struct {
+	Rank       int    `json:"rank"`
+	UserID     string `json:"userId"`
+	Nickname   string `json:"nickname"`
+	Points     int    `json:"points"`
+	Wins       int    `json:"wins"`
+	Games      int    `json:"games"`
+	Eliminated bool   `json:"eliminated,omitempty"`
+}
+
+// Details is a tournament with its standings, as the API shows it.
+type Details struct {
+	*Tournament
+	Standings []Standing `json:"standings"`
+}
+
+// Seat is a participant's place at their current table.
+type Seat struct {
+	TournamentID string `json:"tournamentId"`
+	Round        int    `json:"round"`
+	GameID       string `json:"gameId"`
+	JoinCode     string `json:"joinCode"`
+	Token        string `json:"token"`
+	RejoinCode   string `json:"rejoinCode"`
+}
@@ -0,0 +1,346 @@
+package tournaments
+
+import (
+	"influence_game/internal/game"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// newTournament checks the organizer's options and fills in the defaults.
+func newTournament(id string, options Options, organizerID string, now time.Time) (*Tournament, error) {
+	name := strings.TrimSpace(options.Name)
+	if name == "" || len(name) > MaxNameLength {
+		return nil, ErrInvalidName
+	}
+
+	tournament := &Tournament{
+		ID:           id,
+		Name:         name,
+		Format:       options.Format,
+		OrganizerID:  organizerID,
+		Games:        options.Games,
+		TableSize:    options.TableSize,
+		Points:       options.Points,
+		Settings:     options.Settings,
+		Participants: []Participant{},
+		Rounds:       []Round{},
+		Status:       StatusRegistering,
+		CreatedAt:    now,
+	}
+
+	switch tournament.Format {
+	case FormatSeries:
+		if tournament.Games == 0 {
+			tournament.Games = DefaultSeriesGames
+		}
+		// The whole series plays at one table, so it caps registration.
+		if tournament.TableSize == 0 {
+			tournament.TableSize = game.DefaultMaxPlayers
+		}
+	case FormatSwiss:
+		if tournament.Games == 0 {
+			tournament.Games = DefaultSwissRounds
+		}
+	case FormatBracket:
+		tournament.Games = 0
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	if tournament.TableSize == 0 {
+		tournament.TableSize = DefaultTableSize
+	}
+	if tournament.TableSize < game.MinPlayers || tournament.TableSize > game.MaxPlayers {
+		return nil, ErrInvalidTableSize
+	}
+	if tournament.Games < 0 || tournament.Games > MaxGames {
+		return nil, ErrInvalidGames
+	}
+
+	if len(tournament.Points) > game.MaxPlayers {
+		return nil, ErrInvalidPoints
+	}
+	for _, points := range tournament.Points {
+		if points < 0 || points > 100 {
+			return nil, ErrInvalidPoints
+		}
+	}
+
+	if err := tableSettings(tournament, tournament.TableSize).Apply(game.DefaultRoomSettings()).Validate(); err != nil {
+		return nil, err
+	}
+
+	return tournament, nil
+}
+
+// tableSettings is the organizer's settings for a private room of the given size.
+func tableSettings(tournament *Tournament, size int) *game.RoomSettingsPatch {
+	patch := game.RoomSettingsPatch{}
+	if tournament.Settings != nil {
+		patch = *tournament.Settings
+	}
+
+	private := true
+	patch.MaxPlayers = &size
+	patch.Private = &private
+
+	return &patch
+}
+
+func (tournament *Tournament) register(participant Participant) error {
+	if tournament.Status != StatusRegistering {
+		return ErrRegistrationClosed
+	}
+	if tournament.participant(participant.UserID) != nil {
+		return ErrAlreadyRegistered
+	}
+
+	limit := MaxParticipants
+	if tournament.Format == FormatSeries {
+		limit = tournament.TableSize
+	}
+	if len(tournament.Participants) >= limit {
+		return ErrTournamentFull
+	}
+
+	tournament.Participants = append(tournament.Participants, participant)
+	return nil
+}
+
+func (tournament *Tournament) participant(userID string) *Participant {
+	for i := range tournament.Participants {
+		if tournament.Participants[i].UserID == userID {
+			return &tournament.Participants[i]
+		}
+	}
+	return nil
+}
+
+func (tournament *Tournament) start(userID string) error {
+	if tournament.OrganizerID != userID {
+		return ErrOnlyOrganizerCanStart
+	}
+	if tournament.Status != StatusRegistering {
+		return ErrRegistrationClosed
+	}
+	if len(tournament.Participants) < game.MinPlayers {
+		return ErrNotEnoughParticipants
+	}
+
+	userIDs := make([]string, 0, len(tournament.Participants))
+	for _, participant := range tournament.Participants {
+		userIDs = append(userIDs, participant.UserID)
+	}
+
+	tournament.Status = StatusRunning
+	tournament.addRound(userIDs)
+	return nil
+}
+
+/*
+recordGame writes down the finishing order of one of the current tables and,
+once every table of the round is done, either finishes the tournament or
+draws the next round. It reports whether a new round was drawn.
+*/
+func (tournament *Tournament) recordGame(gameID string, finishingOrder []string, now time.Time) bool {
+	if tournament.Status != StatusRunning || len(tournament.Rounds) == 0 {
+		return false
+	}
+	round := &tournament.Rounds[len(tournament.Rounds)-1]
+
+	for i := range round.Tables {
+		table := &round.Tables[i]
+		if table.GameID != gameID || table.Finished {
+			continue
+		}
+
+		placements := []string{}
+		for _, userID := range finishingOrder {
+			if slices.Contains(table.UserIDs, userID) && !slices.Contains(placements, userID) {
+				placements = append(placements, userID)
+			}
+		}
+		for _, userID := range table.UserIDs {
+			if !slices.Contains(placements, userID) {
+				placements = append(placements, userID)
+			}
+		}
+
+		table.Placements = placements
+		table.Finished = true
+		return tournament.advance(now)
+	}
+
+	return false
+}
+
+func (tournament *Tournament) advance(now time.Time) bool {
+	round := tournament.Rounds[len(tournament.Rounds)-1]
+	winners := []string{}
+	for _, table := range round.Tables {
+		if !table.Finished {
+			return false
+		}
+		winners = append(winners, table.Placements[0])
+	}
+
+	standings := Standings(tournament)
+
+	switch tournament.Format {
+	case FormatSeries:
+		// Best of N: over as soon as someone has won most of the games.
+		for _, standing := range standings {
+			if standing.Wins*2 > tournament.Games {
+				tournament.finish(standing.UserID, now)
+				return false
+			}
+		}
+		if len(tournament.Rounds) >= tournament.Games {
+			tournament.finish(standings[0].UserID, now)
+			return false
+		}
+		tournament.addRound(standingOrder(standings))
+
+	case FormatBracket:
+		if len(winners) == 1 {
+			tournament.finish(winners[0], now)
+			return false
+		}
+		tournament.addRound(winners)
+
+	case FormatSwiss:
+		if len(tournament.Rounds) >= tournament.Games {
+			tournament.finish(standings[0].UserID, now)
+			return false
+		}
+		tournament.addRound(standingOrder(standings))
+	}
+
+	return true
+}
+
+func (tournament *Tournament) finish(winnerID string, now time.Time) {
+	tournament.Status = StatusFinished
+	tournament.WinnerID = winnerID
+	tournament.FinishedAt = now
+}
+
+// addRound seats the players in order; a series keeps everyone at one table.
+func (tournament *Tournament) addRound(userIDs []string) {
+	size := tournament.TableSize
+	if tournament.Format == FormatSeries {
+		size = len(userIDs)
+	}
+
+	round := Round{Number: len(tournament.Rounds) + 1, Tables: []Table{}}
+	for _, seated := range splitTables(userIDs, size) {
+		table := Table{UserIDs: seated}
+		if len(seated) == 1 {
+			table.Placements = []string{seated[0]}
+			table.Finished = true
+		}
+		round.Tables = append(round.Tables, table)
+	}
+
+	tournament.Rounds = append(tournament.Rounds, round)
+}
+
+/*
+splitTables seats players in order at as few tables as the size allows,
+keeping the tables within one player of each other. Only a lone player left
+over from tables of two gets a table to themselves.
+*/
+func splitTables(userIDs []string, size int) [][]string {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	count := (len(userIDs) + size - 1) / size
+	base, extra := len(userIDs)/count, len(userIDs)%count
+
+	tables := make([][]string, 0, count)
+	for i, start := 0, 0; i < count; i++ {
+		end := start + base
+		if i < extra {
+			end++
+		}
+		tables = append(tables, append([]string{}, userIDs[start:end]...))
+		start = end
+	}
+
+	return tables
+}
+
+// pointsFor is what finishing at the placement (0 for the winner) is worth.
+func (tournament *Tournament) pointsFor(placement int, tableSize int) int {
+	if tournament.Points == nil {
+		return tableSize - 1 - placement
+	}
+	if placement < len(tournament.Points) {
+		return tournament.Points[placement]
+	}
+	return 0
+}
+
+/*
+Standings ranks the participants by points, then wins. In a bracket, players
+still in the running come before those knocked out.
+*/
+func Standings(tournament *Tournament) []Standing {
+	standings := make([]Standing, 0, len(tournament.Participants))
+	index := map[string]int{}
+	for i, participant := range tournament.Participants {
+		index[participant.UserID] = i
+		standings = append(standings, Standing{
+			UserID:   participant.UserID,
+			Nickname: participant.Nickname,
+		})
+	}
+
+	for _, round := range tournament.Rounds {
+		for _, table := range round.Tables {
+			if !table.Finished || len(table.UserIDs) < 2 {
+				continue
+			}
+			for placement, userID := range table.Placements {
+				i, ok := index[userID]
+				if !ok {
+					continue
+				}
+				standings[i].Games++
+				standings[i].Points += tournament.pointsFor(placement, len(table.UserIDs))
+				if placement == 0 {
+					standings[i].Wins++
+				} else if tournament.Format == FormatBracket {
+					standings[i].Eliminated = true
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Eliminated != b.Eliminated {
+			return !a.Eliminated
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		return a.Wins > b.Wins
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+
+	return standings
+}
+
+func standingOrder(standings []Standing) []string {
+	userIDs := make([]string, 0, len(standings))
+	for _, standing := range standings {
+		userIDs = append(userIDs, standing.UserID)
+	}
+	return userIDs
+}
@@ -0,0 +1,139 @@
+package tournaments
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func started(t *testing.T, options Options, participants int) *Tournament {
+	t.Helper()
+
+	tournament, err := newTournament("t1", options, "organizer", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected create error %v", err)
+	}
+	for i := 0; i < participants; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		if err := tournament.register(Participant{UserID: userID, Nickname: userID}); err != nil {
+			t.Fatalf("unexpected register error %v", err)
+		}
+	}
+	if err := tournament.start("organizer"); err != nil {
+		t.Fatalf("unexpected start error %v", err)
+	}
+	return tournament
+}
+
+// playRound finishes every open table of the current round, seats in order.
+func playRound(tournament *Tournament) {
+	round := tournament.Rounds[len(tournament.Rounds)-1]
+	for i, table := range round.Tables {
+		if table.Finished {
+			continue
+		}
+		gameID := fmt.Sprintf("game-%d-%d", round.Number, i)
+		tournament.Rounds[len(tournament.Rounds)-1].Tables[i].GameID = gameID
+		tournament.recordGame(gameID, table.UserIDs, time.Now())
+	}
+}
+
+func TestSplitTablesKeepsTablesEven(t *testing.T) {
+	cases := map[[2]int][]int{
+		{8, 4}: {4, 4},
+		{9, 4}: {3, 3, 3},
+		{5, 4}: {3, 2},
+		{3, 2}: {2, 1},
+		{2, 6}: {2},
+	}
+
+	for input, want := range cases {
+		userIDs := make([]string, input[0])
+		tables := splitTables(userIDs, input[1])
+
+		if len(tables) != len(want) {
+			t.Fatalf("%v: expected %d tables, got %d", input, len(want), len(tables))
+		}
+		for i, table := range tables {
+			if len(table) != want[i] {
+				t.Fatalf("%v: expected table sizes %v, got %v", input, want, tables)
+			}
+		}
+	}
+}
+
+func TestSeriesEndsOnceSomeoneWinsTheMajority(t *testing.T) {
+	tournament := started(t, Options{Name: "Office", Format: FormatSeries, Games: 5}, 3)
+
+	for game := 0; game < 3; game++ {
+		playRound(tournament)
+	}
+
+	// The same seat wins every game, so it is over after three of five.
+	if tournament.Status != StatusFinished || len(tournament.Rounds) != 3 {
+		t.Fatalf("expected the series to end after 3 games, got %s after %d", tournament.Status, len(tournament.Rounds))
+	}
+
+	standings := Standings(tournament)
+	if standings[0].UserID != tournament.WinnerID || standings[0].Wins != 3 || standings[0].Points != 6 {
+		t.Fatalf("unexpected leader %+v", standings[0])
+	}
+}
+
+func TestBracketAdvancesTableWinners(t *testing.T) {
+	tournament := started(t, Options{Name: "Cup", Format: FormatBracket, TableSize: 3}, 9)
+
+	playRound(tournament)
+	if len(tournament.Rounds) != 2 || len(tournament.Rounds[1].Tables) != 1 {
+		t.Fatalf("expected the three table winners to meet in a final, got %+v", tournament.Rounds)
+	}
+
+	final := tournament.Rounds[1].Tables[0]
+	if len(final.UserIDs) != 3 || final.UserIDs[0] != "user-0" || final.UserIDs[1] != "user-3" {
+		t.Fatalf("unexpected final table %v", final.UserIDs)
+	}
+
+	playRound(tournament)
+	if tournament.Status != StatusFinished || tournament.WinnerID != "user-0" {
+		t.Fatalf("expected user-0 to win the bracket, got %s", tournament.WinnerID)
+	}
+
+	standings := Standings(tournament)
+	if standings[0].UserID != "user-0" || standings[0].Eliminated || !standings[1].Eliminated {
+		t.Fatalf("expected the winner alone in the running, got %+v", standings[:2])
+	}
+}
+
+func TestSwissDrawsTablesFromTheStandings(t *testing.T) {
+	tournament := started(t, Options{Name: "League", Format: FormatSwiss, Games: 2, TableSize: 2, Points: []int{3}}, 4)
+
+	playRound(tournament)
+	second := tournament.Rounds[1].Tables
+	if second[0].UserIDs[0] != "user-0" || second[0].UserIDs[1] != "user-2" {
+		t.Fatalf("expected the two winners to meet, got %v", second)
+	}
+
+	playRound(tournament)
+	if tournament.Status != StatusFinished || tournament.WinnerID != "user-0" {
+		t.Fatalf("expected user-0 to win the league, got %s", tournament.WinnerID)
+	}
+	if standings := Standings(tournament); standings[0].Points != 6 || standings[3].Points != 0 {
+		t.Fatalf("unexpected standings %+v", standings)
+	}
+}
+
+func TestTournamentOptionsAreValidated(t *testing.T) {
+	cases := map[error]Options{
+		ErrInvalidName:      {Format: FormatSwiss},
+		ErrInvalidFormat:    {Name: "x", Format: "knockout"},
+		ErrInvalidTableSize: {Name: "x", Format: FormatBracket, TableSize: 1},
+		ErrInvalidGames:     {Name: "x", Format: FormatSeries, Games: MaxGames + 1},
+		ErrInvalidPoints:    {Name: "x", Format: FormatSwiss, Points: []int{-1}},
+	}
+
+	for want, options := range cases {
+		if _, err := newTournament("t1", options, "organizer", time.Now()); err != want {
+			t.Fatalf("expected %v, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package tournaments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"influence_game/internal/game"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	tournamentTTL  = 30 * 24 * time.Hour
+	recentKey      = "tournaments:recent"
+	RecentListSize = 50
+
+	// How long a table that failed to open waits before it is tried again.
+	TableRetryInterval = time.Minute
+)
+
+/*
+Store keeps tournaments in Redis:
+
+	tournament:{id}                 the tournament as JSON
+	tournament:game:{gameID}        the tournament a game was played for
+	tournament:seat:{id}:{userID}   the participant's seat at their current table
+	tournament:opening:{id}:{r}:{t} claim on opening table t of round r
+	tournaments:recent              sorted set of tournament IDs by creation time
+
+Rooms are opened through the game store, like any other room, and results
+come back through HandleGameUpdated. A table whose room could not be opened
+is tried again by Get once its claim has expired.
+*/
+type Store struct {
+	redis *redis.Client
+	games *game.Store
+}
+
+func NewStore(redisClient *redis.Client, games *game.Store) *Store {
+	return &Store{
+		redis: redisClient,
+		games: games,
+	}
+}
+
+func (store *Store) Create(options Options, organizerID string) (*Details, error) {
+	ctx := context.Background()
+
+	tournament, err := newTournament(uuid.NewString(), options, organizerID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = store.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, tournamentKey(tournament.ID), data, tournamentTTL)
+		pipe.ZAdd(ctx, recentKey, redis.Z{Score: float64(tournament.CreatedAt.Unix()), Member: tournament.ID})
+		pipe.ZRemRangeByRank(ctx, recentKey, 0, -RecentListSize-1)
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to save tournament.")
+		return nil, err
+	}
+
+	return details(tournament), nil
+}
+
+func (store *Store) Get(tournamentID string) (*Details, error) {
+	ctx := context.Background()
+
+	tournament, err := store.load(ctx, store.redis, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasUnopenedTables(tournament) {
+		if reopened := store.openTables(ctx, tournament); reopened != nil {
+			tournament = reopened
+		}
+	}
+	return details(tournament), nil
+}
+
+// List returns the most recent tournaments, newest first.
+func (store *Store) List() ([]*Details, error) {
+	ctx := context.Background()
+
+	ids, err := store.redis.ZRevRange(ctx, recentKey, 0, RecentListSize-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*Details{}
+	for _, id := range ids {
+		tournament, err := store.load(ctx, store.redis, id)
+		if err == ErrTournamentNotFound {
+			_ = store.redis.ZRem(ctx, recentKey, id).Err()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, details(tournament))
+	}
+
+	return list, nil
+}
+
+func (store *Store) Register(tournamentID string, userID string, nickname string) (*Details, error) {
+	tournament, err := store.update(context.Background(), tournamentID, func(tournament *Tournament) error {
+		return tournament.register(Participant{UserID: userID, Nickname: nickname})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return details(tournament), nil
+}
+
+// Start closes registration and opens the rooms of the first round.
+func (store *Store) Start(tournamentID string, userID string) (*Details, error) {
+	ctx := context.Background()
+
+	tournament, err := store.update(ctx, tournamentID, func(tournament *Tournament) error {
+		return tournament.start(userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if spawned := store.openTables(ctx, tournament); spawned != nil {
+		tournament = spawned
+	}
+	return details(tournament), nil
+}
+
+// Seat returns the participant's seat at their latest table.
+func (store *Store) Seat(tournamentID string, userID string) (*Seat, error) {
+	ctx := context.Background()
+
+	data, err := store.redis.Get(ctx, seatKey(tournamentID, userID)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNoSeat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var seat Seat
+	if err := json.Unmarshal(data, &seat); err != nil {
+		return nil, err
+	}
+	return &seat, nil
+}
+
+/*
+HandleGameUpdated is meant to be registered with game.Store.OnGameUpdated.
+When a tournament game finishes it records the result, and if that completes
+the round it opens the next one in the background.
+*/
+func (store *Store) HandleGameUpdated(updated *game.Game) {
+	if !updated.Finished {
+		return
+	}
+
+	ctx := context.Background()
+
+	tournamentID, err := store.redis.Get(ctx, gameKey(updated.ID)).Result()
+	if err == redis.Nil {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to look up tournament game.")
+		return
+	}
+
+	finishingOrder := []string{}
+	for _, player := range game.FinishingOrder(updated) {
+		finishingOrder = append(finishingOrder, player.UserID)
+	}
+
+	advanced := false
+	tournament, err := store.update(ctx, tournamentID, func(tournament *Tournament) error {
+		advanced = tournament.recordGame(updated.ID, finishingOrder, time.Now().UTC())
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Str("tournamentID", tournamentID).Msg("Failed to record tournament game.")
+		return
+	}
+
+	if advanced {
+		go store.openTables(context.Background(), tournament)
+	}
+}
+
+/*
+openTables opens a room for every table of the current round that has none
+yet, seats its players and starts the game. Each table is claimed first, so
+instances never open the same table twice; a table that fails keeps its claim
+until TableRetryInterval has passed. It returns the tournament with the new
+game IDs, or nil if it could not be saved.
+*/
+func (store *Store) openTables(ctx context.Context, tournament *Tournament) *Tournament {
+	round := tournament.Rounds[len(tournament.Rounds)-1]
+
+	opened := map[int]string{}
+	for i, table := range round.Tables {
+		if table.Finished || table.GameID != "" {
+			continue
+		}
+
+		claimed, err := store.redis.SetNX(ctx, openingKey(tournament.ID, round.Number, i), "1", TableRetryInterval).Result()
+		if err != nil || !claimed {
+			continue
+		}
+
+		gameID, err := store.openTable(ctx, tournament, round.Number, table)
+		if err != nil {
+			log.Error().Err(err).Str("tournamentID", tournament.ID).Int("round", round.Number).Msg("Failed to open tournament table.")
+			continue
+		}
+		opened[i] = gameID
+	}
+
+	updated, err := store.update(ctx, tournament.ID, func(tournament *Tournament) error {
+		current := &tournament.Rounds[len(tournament.Rounds)-1]
+		if current.Number != round.Number {
+			return nil
+		}
+		for i, gameID := range opened {
+			if current.Tables[i].GameID == "" {
+				current.Tables[i].GameID = gameID
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Str("tournamentID", tournament.ID).Msg("Failed to save tournament tables.")
+		return nil
+	}
+
+	return updated
+}
+
+/*
+openTable seats a table in a new room and starts its game. If anything fails
+once the room exists, the room is discarded so nobody is left seated in a
+game the tournament does not know about.
+*/
+func (store *Store) openTable(ctx context.Context, tournament *Tournament, round int, table Table) (_ string, err error) {
+	nicknames := map[string]string{}
+	for _, participant := range tournament.Participants {
+		nicknames[participant.UserID] = participant.Nickname
+	}
+
+	adminID := table.UserIDs[0]
+	admin, err := store.games.CreateGameRoom(nicknames[adminID], tableSettings(tournament, len(table.UserIDs)), adminID)
+	if err != nil {
+		return "", err
+	}
+	gameID := admin.Game.GameID
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		_ = store.redis.Del(ctx, gameKey(gameID)).Err()
+		if discardErr := store.games.DiscardRoom(gameID); discardErr != nil {
+			log.Error().Err(discardErr).Str("gameID", gameID).Msg("Failed to discard tournament room.")
+		}
+	}()
+
+	seated := map[string]*game.OnboardingResult{adminID: admin}
+	for _, userID := range table.UserIDs[1:] {
+		result, err := store.games.Join(admin.Game.JoinCode, nicknames[userID], userID)
+		if err != nil {
+			return "", err
+		}
+		seated[userID] = result
+	}
+
+	if err := store.redis.Set(ctx, gameKey(gameID), tournament.ID, tournamentTTL).Err(); err != nil {
+		return "", err
+	}
+
+	if _, err := store.games.StartGame(gameID, admin.Token); err != nil {
+		return "", err
+	}
+
+	for userID, result := range seated {
+		data, err := json.Marshal(Seat{
+			TournamentID: tournament.ID,
+			Round:        round,
+			GameID:       gameID,
+			JoinCode:     result.Game.JoinCode,
+			Token:        result.Token,
+			RejoinCode:   result.RejoinCode,
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := store.redis.Set(ctx, seatKey(tournament.ID, userID), data, game.SessionDuration).Err(); err != nil {
+			return "", err
+		}
+	}
+
+	return gameID, nil
+}
+
+// update applies fn to the tournament under WATCH, retrying on conflicts.
+func (store *Store) update(
+	ctx context.Context,
+	tournamentID string,
+	fn func(*Tournament) error,
+) (*Tournament, error) {
+	key := tournamentKey(tournamentID)
+	var updated *Tournament
+
+	for {
+		err := store.redis.Watch(ctx, func(tx *redis.Tx) error {
+			tournament, err := store.load(ctx, tx, tournamentID)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(tournament); err != nil {
+				return err
+			}
+
+			data, err := json.Marshal(tournament)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, tournamentTTL)
+				return nil
+			})
+			if err == nil {
+				updated = tournament
+			}
+			return err
+		}, key)
+
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+}
+
+func (store *Store) load(ctx context.Context, client redis.Cmdable, tournamentID string) (*Tournament, error) {
+	data, err := client.Get(ctx, tournamentKey(tournamentID)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrTournamentNotFound
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tournament from Redis.")
+		return nil, err
+	}
+
+	var tournament Tournament
+	if err := json.Unmarshal(data, &tournament); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal tournament.")
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// hasUnopenedTables reports whether a running round has a table still waiting for its room.
+func hasUnopenedTables(tournament *Tournament) bool {
+	if tournament.Status != StatusRunning || len(tournament.Rounds) == 0 {
+		return false
+	}
+	for _, table := range tournament.Rounds[len(tournament.Rounds)-1].Tables {
+		if !table.Finished && table.GameID == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func details(tournament *Tournament) *Details {
+	return &Details{Tournament: tournament, Standings: Standings(tournament)}
+}
+
+func tournamentKey(tournamentID string) string {
+	return "tournament:" + tournamentID
+}
+
+func gameKey(gameID string) string {
+	return "tournament:game:" + gameID
+}
+
+func seatKey(tournamentID string, userID string) string {
+	return "tournament:seat:" + tournamentID + ":" + userID
+}
+
+func openingKey(tournamentID string, round int, table int) string {
+	return fmt.Sprintf("tournament:opening:%s:%d:%d", tournamentID, round, table)
+}
@@ -0,0 +1,83 @@
+package tournaments
+
+import (
+	"context"
+	"influence_game/internal/game"
+	"influence_game/internal/sessions"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	games := game.NewStore(client, sessions.NewVerifier([]byte("secret"), client))
+	return NewStore(client, games), server
+}
+
+func TestFailedTableIsDiscardedAndRetried(t *testing.T) {
+	store, server := newTestStore(t)
+
+	created, err := store.Create(Options{Name: "Office", Format: FormatSeries, Games: 3, TableSize: 2}, "organizer")
+	if err != nil {
+		t.Fatalf("unexpected create error %v", err)
+	}
+	// Two seats under one nickname: the second cannot join the room.
+	for _, userID := range []string{"user-1", "user-2"} {
+		if _, err := store.Register(created.ID, userID, "ana"); err != nil {
+			t.Fatalf("unexpected register error %v", err)
+		}
+	}
+
+	started, err := store.Start(created.ID, "organizer")
+	if err != nil {
+		t.Fatalf("unexpected start error %v", err)
+	}
+	if gameID := started.Rounds[0].Tables[0].GameID; gameID != "" {
+		t.Fatalf("expected the table to stay unopened, got %s", gameID)
+	}
+	if keys := server.Keys(); containsPrefix(keys, "game:") || containsPrefix(keys, "joincode:") {
+		t.Fatalf("expected the half-filled room to be discarded, got %v", keys)
+	}
+
+	_, err = store.update(context.Background(), created.ID, func(tournament *Tournament) error {
+		tournament.Participants[1].Nickname = "bia"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected update error %v", err)
+	}
+
+	if got, _ := store.Get(created.ID); got.Rounds[0].Tables[0].GameID != "" {
+		t.Fatalf("expected no retry before %v, got %s", TableRetryInterval, got.Rounds[0].Tables[0].GameID)
+	}
+
+	server.FastForward(TableRetryInterval)
+	got, err := store.Get(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected get error %v", err)
+	}
+	gameID := got.Rounds[0].Tables[0].GameID
+	if gameID == "" {
+		t.Fatalf("expected the table to be opened on retry")
+	}
+	if seat, err := store.Seat(created.ID, "user-2"); err != nil || seat.GameID != gameID {
+		t.Fatalf("expected user-2 to be seated at %s, got %+v (%v)", gameID, seat, err)
+	}
+}
+
+func containsPrefix(keys []string, prefix string) bool {
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
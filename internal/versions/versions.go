@@ -0,0 +1,121 @@
+/*
+Package versions tells the versions of the API apart. The code writes v1;
+each later version renames what the one before it named inconsistently, and
+is derived from v1 on the way out.
+*/
+package versions
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type Version int
+
+const (
+	V1 Version = 1
+	// V2 names identifiers the same way everywhere: gameId, not gameID.
+	V2 Version = 2
+
+	Latest = V2
+)
+
+// All lists every version served, oldest first.
+var All = []Version{V1, V2}
+
+/*
+renamed maps the name the previous version gives a JSON key or query
+parameter to the name each version gives it instead.
+*/
+var renamed = map[Version]map[string]string{
+	V2: {
+		"gameID":   "gameId",
+		"adminID":  "adminId",
+		"per_page": "perPage",
+	},
+}
+
+// Parse reads a version the way clients write it: "2" or "v2".
+func Parse(value string) (Version, bool) {
+	number, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(value), "v"))
+	if err != nil {
+		return 0, false
+	}
+
+	for _, version := range All {
+		if int(version) == number {
+			return version, true
+		}
+	}
+	return 0, false
+}
+
+func (version Version) String() string {
+	return "v" + strconv.Itoa(int(version))
+}
+
+// Key is the name version gives what v1 calls name.
+func (version Version) Key(name string) string {
+	for _, step := range All {
+		if step > version {
+			break
+		}
+		if to, ok := renamed[step][name]; ok {
+			name = to
+		}
+	}
+	return name
+}
+
+func (version Version) renames() bool {
+	for _, step := range All {
+		if step <= version && len(renamed[step]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Upgrade rewrites JSON written with v1 names into version's names. Anything
+that is not JSON is returned as is.
+*/
+func (version Version) Upgrade(data []byte) []byte {
+	if !version.renames() {
+		return data
+	}
+
+	// Numbers are kept as written, so large ones such as seeds stay exact.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return data
+	}
+
+	upgraded, err := json.Marshal(version.rename(value))
+	if err != nil {
+		return data
+	}
+	return upgraded
+}
+
+func (version Version) rename(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		renamedObject := make(map[string]any, len(value))
+		for key, field := range value {
+			renamedObject[version.Key(key)] = version.rename(field)
+		}
+		return renamedObject
+	case []any:
+		for i, item := range value {
+			value[i] = version.rename(item)
+		}
+		return value
+	}
+	return value
+}
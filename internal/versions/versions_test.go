@@ -0,0 +1,58 @@
+package versions
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Version{"1": V1, "v1": V1, "V2": V2, "2": V2}
+	for value, want := range tests {
+		if got, ok := Parse(value); !ok || got != want {
+			t.Fatalf("expected %q to be %v, got %v", value, want, got)
+		}
+	}
+
+	for _, value := range []string{"", "v", "0", "v9", "latest"} {
+		if _, ok := Parse(value); ok {
+			t.Fatalf("expected %q not to be a version", value)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := V1.Key("gameID"); got != "gameID" {
+		t.Fatalf("expected v1 to keep gameID, got %s", got)
+	}
+	if got := V2.Key("gameID"); got != "gameId" {
+		t.Fatalf("expected v2 to name it gameId, got %s", got)
+	}
+	if got := V2.Key("nickname"); got != "nickname" {
+		t.Fatalf("expected v2 to keep nickname, got %s", got)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	v1 := []byte(`{"gameID":"g1","players":[{"id":"p1","gameID":"g1"}],"settings":{"adminID":"p1"},"seed":9007199254740993}`)
+
+	if got := V1.Upgrade(v1); string(got) != string(v1) {
+		t.Fatalf("expected v1 to be left as is, got %s", got)
+	}
+
+	var got, want any
+	if err := json.Unmarshal(V2.Upgrade(v1), &got); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_ = json.Unmarshal([]byte(`{"gameId":"g1","players":[{"id":"p1","gameId":"g1"}],"settings":{"adminId":"p1"},"seed":9007199254740993}`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if string(V2.Upgrade(V2.Upgrade(v1))) != string(V2.Upgrade(v1)) {
+		t.Fatalf("expected upgrading twice to change nothing more")
+	}
+	if got := V2.Upgrade([]byte("not json")); string(got) != "not json" {
+		t.Fatalf("expected anything but JSON to be left as is, got %s", got)
+	}
+}
@@ -3,6 +3,7 @@ package locales
 import (
 	"embed"
 	"io/fs"
+	"path"
 
 	"github.com/gobuffalo/buffalo"
 )
@@ -11,5 +12,26 @@ import (
 var files embed.FS
 
 func FS() fs.FS {
-	return buffalo.NewFS(files, "locales")
+	return translationFS{buffalo.NewFS(files, "locales")}
+}
+
+/*
+translationFS lists only the yaml files. In development buffalo.FS reads this
+directory from disk, and the i18n middleware tries to load every file it finds
+as translations, Go sources included.
+*/
+type translationFS struct {
+	fs.FS
+}
+
+func (fsys translationFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(fsys.FS, name)
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) == ".yaml" {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, err
 }
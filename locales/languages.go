@@ -0,0 +1,87 @@
+package locales
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages the API has messages for. The first one is the default.
+var Languages = []string{"en-US", "pt-BR", "es"}
+
+/*
+Match picks the supported language closest to tag: "pt", "pt-PT" and "PT-br"
+all give "pt-BR". Tags no language matches give "".
+*/
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return ""
+	}
+
+	for _, language := range Languages {
+		if strings.ToLower(language) == tag {
+			return language
+		}
+	}
+
+	base, _, _ := strings.Cut(tag, "-")
+	for _, language := range Languages {
+		supported, _, _ := strings.Cut(strings.ToLower(language), "-")
+		if supported == base {
+			return language
+		}
+	}
+
+	return ""
+}
+
+/*
+FromHeader picks the supported language an Accept-Language header weighs
+highest. Entries without a q= weight count as 1, ties keep the header's
+order, and q=0 rules a language out.
+*/
+func FromHeader(acceptLanguage string) string {
+	type weighted struct {
+		tag    string
+		weight float64
+	}
+
+	entries := []weighted{}
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		entries = append(entries, weighted{tag: tag, weight: weight(params)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].weight > entries[j].weight
+	})
+
+	for _, entry := range entries {
+		if entry.weight <= 0 {
+			break
+		}
+		if language := Match(entry.tag); language != "" {
+			return language
+		}
+	}
+	return ""
+}
+
+/*
+weight reads the q= parameter of an Accept-Language entry. Weights that
+cannot be read count as 0, so a malformed entry is never preferred.
+*/
+func weight(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
@@ -0,0 +1,109 @@
+package locales
+
+import (
+	_ "influence_game/internal/accounts"
+	_ "influence_game/internal/archive"
+	_ "influence_game/internal/bots"
+	"influence_game/internal/game"
+	_ "influence_game/internal/matchmaking"
+	_ "influence_game/internal/notation"
+	_ "influence_game/internal/ratings"
+	_ "influence_game/internal/tournaments"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+// Every error the API can answer with needs a message in every language.
+func TestEveryErrorIsTranslated(t *testing.T) {
+	names, err := fs.Glob(files, "*.yaml")
+	if err != nil || len(names) == 0 {
+		t.Fatalf("no locale files found (%v)", err)
+	}
+
+	for _, name := range names {
+		data, err := files.ReadFile(name)
+		if err != nil {
+			t.Fatalf("unexpected read error %v", err)
+		}
+
+		for _, known := range game.Catalogue() {
+			if !strings.Contains(string(data), "- id: error."+known.Code+"\n") {
+				t.Errorf("%s: no message for %s", name, known.Code)
+			}
+		}
+	}
+}
+
+// Every message in English needs a version in every other language.
+func TestEveryLanguageHasEveryMessage(t *testing.T) {
+	english, err := files.ReadFile("all.en-us.yaml")
+	if err != nil {
+		t.Fatalf("unexpected read error %v", err)
+	}
+
+	var ids []string
+	for _, line := range strings.Split(string(english), "\n") {
+		if id, ok := strings.CutPrefix(line, "- id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	for _, language := range []string{"all.pt-br.yaml", "all.es.yaml"} {
+		data, err := files.ReadFile(language)
+		if err != nil {
+			t.Fatalf("unexpected read error %v", err)
+		}
+
+		for _, id := range ids {
+			if !strings.Contains(string(data), "- id: "+id+"\n") {
+				t.Errorf("%s: no message for %s", language, id)
+			}
+		}
+	}
+}
+
+func TestMatchPicksClosestLanguage(t *testing.T) {
+	cases := map[string]string{
+		"en-US": "en-US",
+		"en-gb": "en-US",
+		"pt-BR": "pt-BR",
+		"pt":    "pt-BR",
+		"PT-pt": "pt-BR",
+		"es-AR": "es",
+		"fr":    "",
+		"":      "",
+	}
+
+	for tag, expected := range cases {
+		if got := Match(tag); got != expected {
+			t.Errorf("Match(%q) = %q, expected %q", tag, got, expected)
+		}
+	}
+}
+
+func TestFromHeaderSkipsUnsupportedLanguages(t *testing.T) {
+	if got := FromHeader("fr-FR,fr;q=0.9,pt;q=0.8,en;q=0.7"); got != "pt-BR" {
+		t.Errorf("expected pt-BR, got %q", got)
+	}
+	if got := FromHeader("de"); got != "" {
+		t.Errorf("expected no language, got %q", got)
+	}
+}
+
+func TestFromHeaderFollowsWeights(t *testing.T) {
+	tests := map[string]string{
+		"es;q=0.1, pt-BR;q=0.9": "pt-BR",
+		"es;q=0.5, en":          "en-US",
+		"pt, es":                "pt-BR",
+		"pt;q=0, es;q=0.2":      "es",
+		"en;q=0":                "",
+		"es;q=high, pt;q=0.3":   "pt-BR",
+	}
+
+	for header, want := range tests {
+		if got := FromHeader(header); got != want {
+			t.Errorf("%q: expected %q, got %q", header, want, got)
+		}
+	}
+}
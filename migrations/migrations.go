@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS holds the fizz migrations so the binary can migrate without the source tree.
+//
+//go:embed *.fizz
+var FS embed.FS